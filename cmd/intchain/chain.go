@@ -5,6 +5,7 @@ import (
 	"github.com/intfoundation/intchain/accounts/keystore"
 	"github.com/intfoundation/intchain/cmd/utils"
 	tdmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core"
 	"github.com/intfoundation/intchain/log"
 	intnode "github.com/intfoundation/intchain/node"
 	"gopkg.in/urfave/cli.v1"
@@ -76,7 +77,7 @@ func StartChain(ctx *cli.Context, chain *Chain, startDone chan<- struct{}) error
 	return nil
 }
 
-func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivValidator, keyJson []byte, validators []tdmTypes.GenesisValidator) error {
+func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivValidator, keyJson []byte, validators []tdmTypes.GenesisValidator, template *core.ChildChainGenesisTemplate) error {
 
 	// Get Tendermint config base on chain id
 	config := utils.GetTendermintConfig(chainId, ctx)
@@ -97,7 +98,7 @@ func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivV
 	validator.Save()
 
 	// Init the INT Genesis
-	err := initEthGenesisFromExistValidator(chainId, config, validators)
+	err := initEthGenesisFromExistValidator(chainId, config, validators, template)
 	if err != nil {
 		return err
 	}
@@ -106,7 +107,10 @@ func CreateChildChain(ctx *cli.Context, chainId string, validator tdmTypes.PrivV
 	init_int_blockchain(chainId, config.GetString("int_genesis_file"), ctx)
 
 	// Init the Tendermint Genesis
-	init_em_files(config, chainId, config.GetString("int_genesis_file"), validators)
+	err = initTDMGenesisFromExistValidator(chainId, config, validators, template)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/consensus"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/node"
+	"github.com/intfoundation/intchain/p2p"
+	"github.com/intfoundation/intchain/rpc"
+)
+
+// ChainStatus is the status of a running chain returned by int_chains
+type ChainStatus struct {
+	ChainId     string         `json:"chainId"`
+	MainChain   bool           `json:"mainChain"`
+	RPCPath     string         `json:"rpcPath"` // path of the chain rpc on the http/ws endpoint
+	GenesisHash common.Hash    `json:"genesisHash"`
+	Height      hexutil.Uint64 `json:"height"`
+	Epoch       hexutil.Uint64 `json:"epoch"`
+}
+
+// PublicChainAPI provides the discovery of the chains running in this node
+type PublicChainAPI struct {
+	cm *ChainManager
+}
+
+// Chains returns the status of the main chain and the running child chains, the child chains are sorted by chain id
+func (api *PublicChainAPI) Chains() []*ChainStatus {
+	cm := api.cm
+
+	chains := []*ChainStatus{}
+	if status := chainStatus(cm.mainChain, true); status != nil {
+		chains = append(chains, status)
+	}
+
+	children := cm.getChildChains()
+	sort.Slice(children, func(i, j int) bool { return children[i].Id < children[j].Id })
+	for _, chain := range children {
+		if status := chainStatus(chain, false); status != nil {
+			chains = append(chains, status)
+		}
+	}
+	return chains
+}
+
+func chainStatus(chain *Chain, mainChain bool) *ChainStatus {
+	if chain == nil || chain.IntNode == nil {
+		return nil
+	}
+	intChain, err := getIntChainFromNode(chain.IntNode)
+	if err != nil {
+		log.Debugf("chainStatus: chain %s not running: %v", chain.Id, err)
+		return nil
+	}
+
+	bc := intChain.BlockChain()
+	status := &ChainStatus{
+		ChainId:     chain.Id,
+		MainChain:   mainChain,
+		RPCPath:     "/" + chain.Id,
+		GenesisHash: bc.Genesis().Hash(),
+		Height:      hexutil.Uint64(bc.CurrentBlock().NumberU64()),
+	}
+	if tdm, ok := intChain.Engine().(consensus.IPBFT); ok {
+		if ep := tdm.GetEpoch(); ep != nil {
+			status.Epoch = hexutil.Uint64(ep.Number)
+		}
+	}
+	return status
+}
+
+// chainAPIService exposes PublicChainAPI on the main chain node
+type chainAPIService struct {
+	cm *ChainManager
+}
+
+func (s *chainAPIService) Protocols() []p2p.Protocol { return nil }
+
+func (s *chainAPIService) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "int",
+			Version:   "1.0",
+			Service:   &PublicChainAPI{s.cm},
+			Public:    true,
+		},
+	}
+}
+
+func (s *chainAPIService) Start(server *p2p.Server) error { return nil }
+
+func (s *chainAPIService) Stop() error { return nil }
+
+// registerChainAPIService adds the chain discovery api to the main chain node
+func registerChainAPIService(stack *node.Node, cm *ChainManager) error {
+	return stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return &chainAPIService{cm}, nil
+	})
+}
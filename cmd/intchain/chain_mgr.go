@@ -1,7 +1,6 @@
 package main
 
 import (
-	"github.com/intfoundation/go-crypto"
 	dbm "github.com/intfoundation/go-db"
 	"github.com/intfoundation/intchain/accounts"
 	"github.com/intfoundation/intchain/cmd/utils"
@@ -33,11 +32,15 @@ type ChainManager struct {
 	mainStartDone chan struct{}
 
 	createChildChainLock sync.Mutex
+	childChainsLock      sync.RWMutex // Protects the childChains updates from the concurrent readers
 	childChains          map[string]*Chain
 	childQuits           map[string]<-chan struct{}
 
 	stop chan struct{} // Channel wait for INT CHAIN stop
 
+	tx3SweeperQuit chan struct{} // Channel to stop the tx3 sweeper
+	tx3SweeperDone chan struct{} // Channel closed when the tx3 sweeper exits
+
 	server *utils.IntChainP2PServer
 	cch    *CrossChainHelper
 }
@@ -123,7 +126,9 @@ func (cm *ChainManager) LoadChains(childIds []string) error {
 			continue
 		}
 
+		cm.childChainsLock.Lock()
 		cm.childChains[chainId] = chain
+		cm.childChainsLock.Unlock()
 		log.Infof("Load child chain: %s Success!", chainId)
 	}
 	return nil
@@ -187,7 +192,7 @@ func (cm *ChainManager) StartMainChain() error {
 
 func (cm *ChainManager) StartChains() error {
 
-	for _, chain := range cm.childChains {
+	for _, chain := range cm.getChildChains() {
 		// Start each Chain
 		srv := cm.server.Server()
 		childProtocols := chain.IntNode.GatherProtocols()
@@ -228,7 +233,7 @@ func (cm *ChainManager) StartRPC() error {
 			} else {
 				log.Errorf("Load Main Chain RPC HTTP handler failed: %v", err)
 			}
-			for _, chain := range cm.childChains {
+			for _, chain := range cm.getChildChains() {
 				if h, err := chain.IntNode.GetHTTPHandler(); err == nil {
 					utils.HookupHTTP(chain.Id, h)
 				} else {
@@ -243,7 +248,7 @@ func (cm *ChainManager) StartRPC() error {
 			} else {
 				log.Errorf("Load Main Chain RPC WS handler failed: %v", err)
 			}
-			for _, chain := range cm.childChains {
+			for _, chain := range cm.getChildChains() {
 				if h, err := chain.IntNode.GetWSHandler(); err == nil {
 					utils.HookupWS(chain.Id, h)
 				} else {
@@ -291,7 +296,14 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 		return
 	}
 
-	validators := make([]types.GenesisValidator, 0, len(cci.JoinedValidators))
+	// Load the genesis template set by the SetChildChainGenesis tx (Optional)
+	template, err := core.LoadChildChainGenesisTemplate(cm.cch.chainInfoDB, chainId)
+	if err != nil {
+		log.Errorf("child chain: %s has invalid genesis template, can't load: %v", chainId, err)
+		return
+	}
+
+	validators := genesisValidatorsFromJoined(cci.JoinedValidators)
 
 	validator := false
 
@@ -303,26 +315,14 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 		localEtherbase = ipbft.PrivateValidator()
 	}
 
-	for _, v := range cci.JoinedValidators {
-		if v.Address == localEtherbase {
+	for _, v := range validators {
+		if v.EthAccount == localEtherbase {
 			validator = true
 		}
-
-		// dereference the PubKey
-		if pubkey, ok := v.PubKey.(*crypto.BLSPubKey); ok {
-			v.PubKey = *pubkey
-		}
-
-		// append the Validator
-		validators = append(validators, types.GenesisValidator{
-			EthAccount: v.Address,
-			PubKey:     v.PubKey,
-			Amount:     v.DepositAmount,
-		})
 	}
 
 	// Write down the genesis into chain info db when exit the routine
-	defer writeGenesisIntoChainInfoDB(cm.cch.chainInfoDB, chainId, validators)
+	defer writeGenesisIntoChainInfoDB(cm.cch.chainInfoDB, chainId, validators, template)
 
 	if !validator {
 		log.Warnf("You are not in the validators of child chain %v, no need to start the child chain", chainId)
@@ -332,7 +332,7 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 	}
 
 	// if child chain already loaded, just return (For catch-up case)
-	if _, ok := cm.childChains[chainId]; ok {
+	if _, ok := cm.getChildChain(chainId); ok {
 		log.Infof("Child Chain [%v] has been already loaded.", chainId)
 		return
 	}
@@ -352,7 +352,7 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 	privValidatorFile := cm.mainChain.Config.GetString("priv_validator_file")
 	self := types.LoadPrivValidator(privValidatorFile)
 
-	err := CreateChildChain(cm.ctx, chainId, *self, keyJson, validators)
+	err = CreateChildChain(cm.ctx, chainId, *self, keyJson, validators, template)
 	if err != nil {
 		log.Errorf("Create Child Chain %v failed! %v", chainId, err)
 		return
@@ -396,7 +396,9 @@ func (cm *ChainManager) LoadChildChainInRT(chainId string) {
 	cm.formalizeChildChain(chainId, *cci, firstEpoch)
 
 	// Add Child Chain Id into Chain Manager
+	cm.childChainsLock.Lock()
 	cm.childChains[chainId] = chain
+	cm.childChainsLock.Unlock()
 
 	//TODO Broadcast Child ID to all Main Chain peers
 	go cm.server.BroadcastNewChildChainMsg(chainId)
@@ -447,7 +449,7 @@ func (cm *ChainManager) StopChain() {
 			log.Info("Main Chain Closed")
 		}
 	}()
-	for _, child := range cm.childChains {
+	for _, child := range cm.getChildChains() {
 		go func() {
 			childChainError := child.IntNode.Close()
 			if childChainError != nil {
@@ -457,6 +459,28 @@ func (cm *ChainManager) StopChain() {
 	}
 }
 
+// getChildChains returns a snapshot of the child chains, the loading of a child
+// chain doesn't block the caller.
+func (cm *ChainManager) getChildChains() []*Chain {
+	cm.childChainsLock.RLock()
+	defer cm.childChainsLock.RUnlock()
+
+	children := make([]*Chain, 0, len(cm.childChains))
+	for _, chain := range cm.childChains {
+		children = append(children, chain)
+	}
+	return children
+}
+
+// getChildChain returns the child chain of the id if it is loaded.
+func (cm *ChainManager) getChildChain(chainId string) (*Chain, bool) {
+	cm.childChainsLock.RLock()
+	defer cm.childChainsLock.RUnlock()
+
+	chain, ok := cm.childChains[chainId]
+	return chain, ok
+}
+
 func (cm *ChainManager) WaitChainsStop() {
 	<-cm.mainQuit
 	for _, quit := range cm.childQuits {
@@ -467,6 +491,7 @@ func (cm *ChainManager) WaitChainsStop() {
 func (cm *ChainManager) Stop() {
 	utils.StopRPC()
 	cm.server.Stop()
+	cm.StopTX3Sweeper()
 	cm.cch.localTX3CacheDB.Close()
 	cm.cch.chainInfoDB.Close()
 
@@ -491,8 +516,8 @@ func (cm *ChainManager) getNodeValidator(intNode *node.Node) (common.Address, bo
 	return coinbase, epoch.Validators.HasAddress(coinbase[:])
 }
 
-func writeGenesisIntoChainInfoDB(db dbm.DB, childChainId string, validators []types.GenesisValidator) {
-	ethByte, _ := generateETHGenesis(childChainId, validators)
-	tdmByte, _ := generateTDMGenesis(childChainId, validators)
+func writeGenesisIntoChainInfoDB(db dbm.DB, childChainId string, validators []types.GenesisValidator, template *core.ChildChainGenesisTemplate) {
+	ethByte, _ := generateETHGenesis(childChainId, validators, template)
+	tdmByte, _ := generateTDMGenesis(childChainId, validators, template)
 	core.SaveChainGenesis(db, childChainId, ethByte, tdmByte)
 }
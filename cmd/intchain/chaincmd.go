@@ -17,13 +17,19 @@
 package main
 
 import (
+	"compress/gzip"
 	"fmt"
+	dbm "github.com/intfoundation/go-db"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
 	"github.com/intfoundation/intchain/intdb"
 	"github.com/intfoundation/intchain/intprotocol"
 	"github.com/intfoundation/intchain/params"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -33,6 +39,8 @@ import (
 	"github.com/intfoundation/intchain/core"
 	"github.com/intfoundation/intchain/core/rawdb"
 	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/state/pruner"
+	"github.com/intfoundation/intchain/core/state/statefile"
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/event"
 	"github.com/intfoundation/intchain/intprotocol/downloader"
@@ -180,10 +188,15 @@ The export-preimages command export hash preimages to an RLP encoded stream`,
 			utils.CacheFlag,
 			utils.SyncModeFlag,
 			utils.TestnetFlag,
+			utils.DBEngineFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
-The first argument must be the directory containing the blockchain to download from`,
+The first argument must be the directory containing the blockchain to download from.
+The source is opened with the engine it was created with, while the local chain
+is created with --db.engine, so a leveldb chain can be migrated to pebble with
+
+    intchain --db.engine pebble copydb <sourceChaindataDir> <chainName>`,
 	}
 	removedbCommand = cli.Command{
 		Action:    utils.MigrateFlags(removeDB),
@@ -225,6 +238,63 @@ Use "ethereum dump 0" to dump the genesis block.`,
 		Description: `
 	The count-blockstate command count the block state from a given height.`,
 	}
+	pruneStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(pruneState),
+		Name:      "prune-state",
+		Usage:     "Prune the stale state data of a stopped node",
+		ArgsUsage: "<chainname>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+			utils.PruneRetainFlag,
+			utils.BloomFilterSizeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The prune-state command deletes the state data not reachable from the state of the
+latest --prune.retain blocks and the genesis, and reports the space reclaimed. The
+reachable trie nodes of the account, storage, tx1, tx3, proxied and reward tries are
+marked into a bloom filter of --bloomfilter.size megabytes, a larger filter keeps less
+stale data. The node must be stopped, an interrupted pruning is completed by running
+the command again.`,
+	}
+
+	exportStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportState),
+		Name:      "export-state",
+		Usage:     "Export the full state of a block into a portable state file",
+		ArgsUsage: "<chainname> <blockNum> <filename>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-state command writes the block, the state of the block including the
+tx1, tx3, proxied and reward tries, and the epochs of the block into a checksummed
+state file. The file is gzipped if its name ends with .gz. The state of the block
+must be available, the node should be stopped.`,
+	}
+	importStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(importState),
+		Name:      "import-state",
+		Usage:     "Initialize a node at the block of a state file",
+		ArgsUsage: "<chainname> <filename> <blockHash>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-state command imports a state file exported by export-state into a node
+initialized with the genesis only. The block of the file must match the trusted
+block hash, and the state must be complete and match the state root of the block.
+The node then starts syncing from the block of the file.`,
+	}
 
 	versionCommand = cli.Command{
 		Action:    utils.MigrateFlags(version),
@@ -485,7 +555,7 @@ func copyDb(ctx *cli.Context) error {
 	dl := downloader.New(syncmode, chainDb, new(event.TypeMux), chain, nil, nil, nil)
 
 	// Create a source peer to satisfy downloader requests from
-	db, err := rawdb.NewLevelDBDatabase(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256, "")
+	db, err := rawdb.OpenDatabase("", ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256, "", "")
 	if err != nil {
 		return err
 	}
@@ -697,6 +767,193 @@ func countTrie(db intdb.Database, t state.Trie, count *CountSize, processLeaf pr
 	}
 }
 
+func pruneState(ctx *cli.Context) error {
+	chainName := ctx.Args().First()
+	if chainName == "" {
+		utils.Fatalf("This command requires chain name specified.")
+	}
+
+	stack, _ := makeConfigNode(ctx, chainName)
+	defer stack.Close()
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	dbdir := stack.ResolvePath("chaindata")
+	before := dirSize(dbdir)
+
+	start := time.Now()
+	stats, err := pruner.NewPruner(chainDb, ctx.GlobalUint64(utils.BloomFilterSizeFlag.Name)).Prune(ctx.GlobalUint64(utils.PruneRetainFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to prune state: %v", err)
+	}
+	after := dirSize(dbdir)
+
+	fmt.Printf("Prune done in %v.\n\n", time.Since(start))
+	fmt.Printf("State entries retained: %d\n", stats.Marked)
+	fmt.Printf("State entries deleted:  %d (%v)\n", stats.Deleted, stats.Size)
+	fmt.Printf("Database size:          %v -> %v\n", before, after)
+	if before > after {
+		fmt.Printf("Space reclaimed:        %v\n", before-after)
+	}
+	return nil
+}
+
+// dirSize returns the total size of the files in the directory.
+func dirSize(dir string) common.StorageSize {
+	var size int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return common.StorageSize(size)
+}
+
+func exportState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 3 {
+		utils.Fatalf("This command requires chain name, block number and file name.")
+	}
+	chainName := ctx.Args().First()
+	number, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid block number: %v", err)
+	}
+	fn := ctx.Args().Get(2)
+
+	stack, _ := makeConfigNode(ctx, chainName)
+	defer stack.Close()
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	hash := rawdb.ReadCanonicalHash(chainDb, number)
+	block := rawdb.ReadBlock(chainDb, hash, number)
+	if block == nil {
+		utils.Fatalf("Block %d not found", number)
+	}
+	td := rawdb.ReadTd(chainDb, hash, number)
+	if td == nil {
+		utils.Fatalf("Total difficulty of block %d not found", number)
+	}
+	if head := rawdb.ReadHeadBlockHash(chainDb); head != hash {
+		log.Warn("Exporting the state of a block before the head, the state may be pruned", "number", number, "head", head)
+	}
+
+	epochDB := dbm.NewDB("epoch", "leveldb", utils.GetTendermintConfig(chainName, ctx).GetString("db_dir"))
+	entries, err := epoch.ExportEpochDB(epochDB, number)
+	epochDB.Close()
+	if err != nil {
+		utils.Fatalf("Failed to export epochs: %v", err)
+	}
+	snap := &statefile.Snapshot{ChainID: chainName, Block: block, Td: td}
+	for _, entry := range entries {
+		snap.Epochs = append(snap.Epochs, statefile.Entry{Key: entry.Key, Value: entry.Value})
+	}
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		utils.Fatalf("Failed to create file: %v", err)
+	}
+	defer fh.Close()
+
+	var (
+		writer io.Writer = fh
+		gz     *gzip.Writer
+	)
+	if strings.HasSuffix(fn, ".gz") {
+		gz = gzip.NewWriter(writer)
+		writer = gz
+	}
+
+	start := time.Now()
+	count, err := statefile.Export(writer, chainDb, snap)
+	if err != nil {
+		utils.Fatalf("Failed to export state: %v", err)
+	}
+	// The gzip stream is complete once closed
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			utils.Fatalf("Failed to export state: %v", err)
+		}
+	}
+	if err := fh.Close(); err != nil {
+		utils.Fatalf("Failed to export state: %v", err)
+	}
+	fmt.Printf("Exported the state of block %d (%x), %d state entries, in %v\n", number, hash, count, time.Since(start))
+	return nil
+}
+
+func importState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 3 {
+		utils.Fatalf("This command requires chain name, file name and trusted block hash.")
+	}
+	chainName := ctx.Args().First()
+	fn := ctx.Args().Get(1)
+	trusted := common.HexToHash(ctx.Args().Get(2))
+
+	// The blocks before the state file block are never imported, the freezer can't
+	// move past them
+	if ctx.GlobalIsSet(utils.AncientFlag.Name) {
+		utils.Fatalf("The state file can't be imported into a node with the freezer")
+	}
+
+	stack, _ := makeConfigNode(ctx, chainName)
+	defer stack.Close()
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	// Only a node initialized with the genesis can be moved to the block of the file
+	head := rawdb.ReadHeadBlockHash(chainDb)
+	if head == (common.Hash{}) {
+		utils.Fatalf("The chain is not initialized, run init first")
+	}
+	if number := rawdb.ReadHeaderNumber(chainDb, head); number == nil || *number != 0 {
+		utils.Fatalf("The chain is already synced, import the state into a node initialized with the genesis only")
+	}
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		utils.Fatalf("Failed to open file: %v", err)
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			utils.Fatalf("Failed to open file: %v", err)
+		}
+	}
+
+	start := time.Now()
+	snap, err := statefile.Import(reader, chainDb, trusted)
+	if err != nil {
+		utils.Fatalf("Failed to import state: %v", err)
+	}
+	if snap.ChainID != chainName {
+		utils.Fatalf("State file of chain %s, not %s", snap.ChainID, chainName)
+	}
+
+	entries := make([]epoch.EpochDBEntry, 0, len(snap.Epochs))
+	for _, entry := range snap.Epochs {
+		entries = append(entries, epoch.EpochDBEntry{Key: entry.Key, Value: entry.Value})
+	}
+	epochDB := dbm.NewDB("epoch", "leveldb", utils.GetTendermintConfig(chainName, ctx).GetString("db_dir"))
+	ep, err := epoch.ImportEpochDB(epochDB, entries, snap.Block.Header())
+	epochDB.Close()
+	if err != nil {
+		utils.Fatalf("Failed to import epochs: %v", err)
+	}
+	statefile.WriteChainHead(chainDb, snap)
+
+	fmt.Printf("Imported the state of block %d (%x), epoch %d, in %v\n", snap.Block.NumberU64(), snap.Block.Hash(), ep.Number, time.Since(start))
+	return nil
+}
+
 func version(ctx *cli.Context) error {
 	fmt.Println("Chain:", clientIdentifier)
 	fmt.Println("Version:", params.VersionWithMeta)
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/intfoundation/go-crypto"
+	dbm "github.com/intfoundation/go-db"
+	"github.com/intfoundation/intchain/cmd/utils"
+	"github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	childChainTemplateFlag = cli.StringFlag{
+		Name:  "template",
+		Usage: "Genesis template json file set by the SetChildChainGenesis tx (default: the template stored in chain info db)",
+	}
+	childChainValidatorsFlag = cli.StringFlag{
+		Name:  "validators",
+		Usage: "Genesis validators json file (default: the joined validators stored in chain info db)",
+	}
+	childChainOutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "Directory to write int_genesis.json and genesis.json into (default: print to stdout)",
+	}
+
+	childChainCommand = cli.Command{
+		Name:     "child-chain",
+		Usage:    "Manage the child chain genesis",
+		Category: "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(childChainDryRun),
+				Name:      "dry-run",
+				Usage:     "Render the genesis of the child chain without launching it",
+				ArgsUsage: "<chainId>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					childChainTemplateFlag,
+					childChainValidatorsFlag,
+					childChainOutputFlag,
+				},
+				Description: `
+The dry-run command renders the INT genesis and the tendermint genesis exactly
+as the validators produce them when the child chain launches.
+
+The genesis template and the joined validators are loaded from the chain info
+database of the main chain, either of them can be overridden by the --template
+and --validators files, so the template can be previewed before sending the
+SetChildChainGenesis tx. The genesis_time of the tendermint genesis is the launch
+time, it is rendered as the current time.`,
+			},
+		},
+	}
+)
+
+func childChainDryRun(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires the child chain id as argument.")
+	}
+	chainId := ctx.Args().First()
+	if err := validateChildChainId(chainId); err != nil {
+		utils.Fatalf("Invalid child chain id: %v", err)
+	}
+
+	var (
+		template   *core.ChildChainGenesisTemplate
+		validators []types.GenesisValidator
+	)
+	templateFile := ctx.String(childChainTemplateFlag.Name)
+	if templateFile != "" {
+		contents, err := ioutil.ReadFile(templateFile)
+		if err != nil {
+			utils.Fatalf("Failed to read genesis template: %v", err)
+		}
+		if template, err = core.DecodeChildChainGenesisTemplate(chainId, contents); err != nil {
+			utils.Fatalf("%v", err)
+		}
+	}
+	if file := ctx.String(childChainValidatorsFlag.Name); file != "" {
+		contents, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read genesis validators: %v", err)
+		}
+		if err := json.Unmarshal(contents, &validators); err != nil {
+			utils.Fatalf("Invalid genesis validators: %v", err)
+		}
+	}
+
+	// Load the missing data from chain info db
+	if templateFile == "" || validators == nil {
+		chainInfoDb := dbm.NewDB("chaininfo", "leveldb", ctx.GlobalString(utils.DataDirFlag.Name))
+		if chainInfoDb == nil {
+			return errors.New("could not open chain info database")
+		}
+		defer chainInfoDb.Close()
+
+		var err error
+		if templateFile == "" {
+			if template, err = core.LoadChildChainGenesisTemplate(chainInfoDb, chainId); err != nil {
+				utils.Fatalf("%v", err)
+			}
+		}
+		if validators == nil {
+			if validators, err = loadChildChainValidators(chainInfoDb, chainId); err != nil {
+				utils.Fatalf("%v", err)
+			}
+		}
+	}
+
+	if len(validators) == 0 {
+		utils.Fatalf("child chain %s has no validators", chainId)
+	}
+
+	ethGenesis, err := generateETHGenesis(chainId, validators, template)
+	if err != nil {
+		return err
+	}
+	tdmGenesis, err := generateTDMGenesis(chainId, validators, template)
+	if err != nil {
+		return err
+	}
+
+	// Build the genesis block the same way as init does
+	var coreGenesis core.Genesis
+	if err := json.Unmarshal(ethGenesis, &coreGenesis); err != nil {
+		utils.Fatalf("Invalid INT genesis: %v", err)
+	}
+	block := coreGenesis.ToBlock(rawdb.NewMemoryDatabase())
+
+	if dir := ctx.String(childChainOutputFlag.Name); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			utils.Fatalf("Failed to create output directory: %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "int_genesis.json"), indentJson(ethGenesis), 0644); err != nil {
+			utils.Fatalf("Failed to write INT genesis: %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "genesis.json"), indentJson(tdmGenesis), 0644); err != nil {
+			utils.Fatalf("Failed to write tendermint genesis: %v", err)
+		}
+	} else {
+		fmt.Printf("INT genesis (int_genesis.json):\n%s\n\n", indentJson(ethGenesis))
+		fmt.Printf("Tendermint genesis (genesis.json):\n%s\n\n", indentJson(tdmGenesis))
+	}
+
+	fmt.Printf("Chain:        %s\n", chainId)
+	fmt.Printf("Validators:   %d\n", len(validators))
+	fmt.Printf("Genesis hash: %x\n", block.Hash())
+	fmt.Printf("State root:   %x\n", block.Root())
+	return nil
+}
+
+// loadChildChainValidators loads the joined validators of the pending or the launched child chain
+func loadChildChainValidators(db dbm.DB, chainId string) ([]types.GenesisValidator, error) {
+	cci := core.GetPendingChildChainData(db, chainId)
+	if cci == nil {
+		if ci := core.GetChainInfo(db, chainId); ci != nil {
+			cci = &ci.CoreChainInfo
+		}
+	}
+	if cci == nil {
+		return nil, fmt.Errorf("child chain %s does not exist, provide the validators file instead", chainId)
+	}
+	return genesisValidatorsFromJoined(cci.JoinedValidators), nil
+}
+
+// genesisValidatorsFromJoined converts the joined validators to the genesis validators of the child chain
+func genesisValidatorsFromJoined(joined []core.JoinedValidator) []types.GenesisValidator {
+	validators := make([]types.GenesisValidator, 0, len(joined))
+	for _, v := range joined {
+		// dereference the PubKey
+		if pubkey, ok := v.PubKey.(*crypto.BLSPubKey); ok {
+			v.PubKey = *pubkey
+		}
+
+		validators = append(validators, types.GenesisValidator{
+			EthAccount: v.Address,
+			PubKey:     v.PubKey,
+			Amount:     v.DepositAmount,
+		})
+	}
+	return validators
+}
+
+func indentJson(contents []byte) []byte {
+	var out bytes.Buffer
+	if err := json.Indent(&out, contents, "", "\t"); err != nil {
+		return contents
+	}
+	return out.Bytes()
+}
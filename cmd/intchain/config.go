@@ -146,6 +146,13 @@ func makeFullNode(ctx *cli.Context, cch core.CrossChainHelper, chainId string) *
 
 	utils.RegisterIntService(stack, &cfg.Eth, ctx, cch)
 
+	// Chain discovery is served by the main chain only
+	if chainId == MainChain || chainId == TestnetChain {
+		if err := registerChainAPIService(stack, GetCMInstance(ctx)); err != nil {
+			utils.Fatalf("Failed to register the chain api service: %v", err)
+		}
+	}
+
 	// Whisper must be explicitly enabled by specifying at least 1 whisper flag or in dev mode
 	//shhEnabled := enableWhisper(ctx)
 	//shhAutoEnabled := !ctx.GlobalIsSet(utils.WhisperEnabledFlag.Name) && ctx.GlobalIsSet(utils.DeveloperFlag.Name)
@@ -57,9 +57,8 @@ func (cch *CrossChainHelper) GetMainChainId() string {
 	return cch.mainChainId
 }
 
-// CanCreateChildChain check the condition before send the create child chain into the tx pool
-func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount, startupCost *big.Int, startBlock, endBlock *big.Int) error {
-
+// validateChildChainId check the naming rule of the child chain id
+func validateChildChainId(chainId string) error {
 	if chainId == "" || strings.Contains(chainId, ";") {
 		return errors.New("chainId is nil or empty, or contains ';', should be meaningful")
 	}
@@ -77,6 +76,19 @@ func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId st
 		return errors.New("you can't create IntChain as a child chain, try use other name instead")
 	}
 
+	return nil
+}
+
+// CanCreateChildChain check the condition before send the create child chain into the tx pool.
+// The genesis template is not carried by the CreateChildChain tx, so its selector stays
+// the same for the existing clients, it is validated by ValidateSetChildChainGenesis
+// once the owner sets it with the SetChildChainGenesis tx.
+func (cch *CrossChainHelper) CanCreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount, startupCost *big.Int, startBlock, endBlock *big.Int) error {
+
+	if err := validateChildChainId(chainId); err != nil {
+		return err
+	}
+
 	// Check if "chainId" has been created
 	ci := core.GetChainInfo(cch.chainInfoDB, chainId)
 	if ci != nil {
@@ -139,6 +151,41 @@ func (cch *CrossChainHelper) CreateChildChain(from common.Address, chainId strin
 	return nil
 }
 
+// ValidateSetChildChainGenesis check the owner of the pending child chain customizes its genesis with a valid template
+func (cch *CrossChainHelper) ValidateSetChildChainGenesis(from common.Address, chainId string, genesisTemplate []byte) error {
+	log.Debug("ValidateSetChildChainGenesis - start")
+
+	ci := core.GetPendingChildChainData(cch.chainInfoDB, chainId)
+	if ci == nil {
+		return fmt.Errorf("chain %s not exist or already launched", chainId)
+	}
+
+	if ci.Owner != from {
+		return fmt.Errorf("only the owner %x can set the genesis of chain %s", ci.Owner, chainId)
+	}
+
+	if len(genesisTemplate) == 0 {
+		return core.ErrInvalidGenesisTemplate
+	}
+
+	if _, err := core.DecodeChildChainGenesisTemplate(chainId, genesisTemplate); err != nil {
+		return err
+	}
+
+	log.Debug("ValidateSetChildChainGenesis - end")
+	return nil
+}
+
+// SetChildChainGenesis Save the genesis template of the pending Child Chain, it is used when the Child Chain launches
+func (cch *CrossChainHelper) SetChildChainGenesis(chainId string, genesisTemplate []byte) error {
+	log.Debug("SetChildChainGenesis - start")
+
+	core.SaveChildChainGenesisTemplate(cch.chainInfoDB, chainId, genesisTemplate)
+
+	log.Debug("SetChildChainGenesis - end")
+	return nil
+}
+
 // ValidateJoinChildChain check the criteria whether it meets the join child chain requirement
 func (cch *CrossChainHelper) ValidateJoinChildChain(from common.Address, consensusPubkey []byte, chainId string, depositAmount *big.Int, signature []byte) error {
 	log.Debug("ValidateJoinChildChain - start")
@@ -318,6 +365,27 @@ func (cch *CrossChainHelper) GetTxFromMainChain(txHash common.Hash) *types.Trans
 	return tx
 }
 
+// GetReceiptFromMainChain returns the receipt of the tx only if the block including it is canonical
+func (cch *CrossChainHelper) GetReceiptFromMainChain(txHash common.Hash) *types.Receipt {
+	intnode := MustGetIntChainFromNode(chainMgr.mainChain.IntNode)
+	chainDb := intnode.ChainDb()
+
+	receipt, blockHash, number, _ := rawdb.ReadReceipt(chainDb, txHash)
+	if receipt == nil || rawdb.ReadCanonicalHash(chainDb, number) != blockHash {
+		return nil
+	}
+	return receipt
+}
+
+// GetMainChainBridge returns the token bridge contract on the main chain, zero address if the bridge is disabled
+func (cch *CrossChainHelper) GetMainChainBridge() common.Address {
+	intnode := MustGetIntChainFromNode(chainMgr.mainChain.IntNode)
+	if bridge := intnode.ChainConfig().TokenBridge; bridge != nil {
+		return bridge.MainChainBridge
+	}
+	return common.Address{}
+}
+
 func (cch *CrossChainHelper) GetEpochFromMainChain() (string, *epoch.Epoch) {
 	intnode := MustGetIntChainFromNode(chainMgr.mainChain.IntNode)
 	var ep *epoch.Epoch
@@ -336,7 +404,7 @@ func (cch *CrossChainHelper) ChangeValidators(chainId string) {
 	var chain *Chain = nil
 	if chainId == MainChain || chainId == TestnetChain {
 		chain = chainMgr.mainChain
-	} else if chn, ok := chainMgr.childChains[chainId]; ok {
+	} else if chn, ok := chainMgr.getChildChain(chainId); ok {
 		chain = chn
 	}
 
@@ -351,17 +419,59 @@ func (cch *CrossChainHelper) ChangeValidators(chainId string) {
 
 // verify the signature of validators who voted for the block
 // most of the logic here is from 'VerifyHeader'
-func (cch *CrossChainHelper) VerifyChildChainProofData(bs []byte) error {
+// the data carries either one header or a batch of headers in ascending order of height,
+// number is the main chain block carrying the data
+func (cch *CrossChainHelper) VerifyChildChainProofData(bs []byte, number *big.Int) error {
 
 	log.Debug("VerifyChildChainProofData - start")
 
-	var proofData types.ChildChainProofData
-	err := rlp.DecodeBytes(bs, &proofData)
+	headers, err := types.DecodeChildChainProofData(bs)
 	if err != nil {
 		return err
 	}
 
-	header := proofData.Header
+	var (
+		chainId     string
+		lastHeight  uint64
+		batchEpochs []*epoch.Epoch
+		checkEpoch  = MustGetIntChainFromNode(chainMgr.mainChain.IntNode).BlockChain().Config().IsChildEpoch(number)
+	)
+	for i, header := range headers {
+		tdmExtra, verified, err := cch.verifyChildChainHeader(header, batchEpochs, checkEpoch)
+		if err != nil {
+			return err
+		}
+
+		// all the headers of the batch must come from the same chain in ascending order
+		if i > 0 {
+			if tdmExtra.ChainID != chainId {
+				return fmt.Errorf("inconsistent child chain id in batch: %s, %s", chainId, tdmExtra.ChainID)
+			}
+			if tdmExtra.Height <= lastHeight {
+				return fmt.Errorf("child chain headers not in ascending order: %v after %v", tdmExtra.Height, lastHeight)
+			}
+		}
+		chainId, lastHeight = tdmExtra.ChainID, tdmExtra.Height
+
+		// the epoch carried by the earlier header may be used to verify the later ones,
+		// only if the commit of the header has been verified
+		if verified && len(tdmExtra.EpochBytes) != 0 {
+			if ep := epoch.FromBytes(tdmExtra.EpochBytes); ep != nil {
+				batchEpochs = append(batchEpochs, ep)
+			}
+		}
+	}
+
+	log.Debug("VerifyChildChainProofData - end")
+	return nil
+}
+
+// verifyChildChainHeader verifies one header of the child chain, the epochs carried
+// by the previous headers of the same batch take precedence over the chain info.
+// It reports whether the commit of the header has been verified by the validators.
+// The header carrying epoch 0 is only checked after the child chain epoch fork, if
+// checkEpoch is set, it is accepted unverified before.
+func (cch *CrossChainHelper) verifyChildChainHeader(header *types.Header, batchEpochs []*epoch.Epoch, checkEpoch bool) (*tdmTypes.TendermintExtra, bool, error) {
 	// Don't waste time checking blocks from the future
 	if header.Time.Cmp(big.NewInt(time.Now().Unix())) > 0 {
 		//return errors.New("block in the future")
@@ -369,36 +479,42 @@ func (cch *CrossChainHelper) VerifyChildChainProofData(bs []byte) error {
 
 	tdmExtra, err := tdmTypes.ExtractTendermintExtra(header)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	chainId := tdmExtra.ChainID
 	if chainId == "" || chainId == MainChain || chainId == TestnetChain {
-		return fmt.Errorf("invalid child chain id: %s", chainId)
+		return nil, false, fmt.Errorf("invalid child chain id: %s", chainId)
 	}
 
 	if header.Nonce != (types.TendermintEmptyNonce) && !bytes.Equal(header.Nonce[:], types.TendermintNonce) {
-		return errors.New("invalid nonce")
+		return nil, false, errors.New("invalid nonce")
 	}
 
 	if header.MixDigest != types.TendermintDigest {
-		return errors.New("invalid mix digest")
+		return nil, false, errors.New("invalid mix digest")
 	}
 
 	if header.UncleHash != types.TendermintNilUncleHash {
-		return errors.New("invalid uncle Hash")
+		return nil, false, errors.New("invalid uncle Hash")
 	}
 
 	if header.Difficulty == nil || header.Difficulty.Cmp(types.TendermintDefaultDifficulty) != 0 {
-		return errors.New("invalid difficulty")
+		return nil, false, errors.New("invalid difficulty")
 	}
 
-	// special case: epoch 0 update
-	// TODO: how to verify this block which includes epoch 0?
+	// special case: epoch 0 update, only the first block of the child chain carries the genesis epoch
+	var genesisEpoch *epoch.Epoch
 	if tdmExtra.EpochBytes != nil && len(tdmExtra.EpochBytes) != 0 {
 		ep := epoch.FromBytes(tdmExtra.EpochBytes)
 		if ep != nil && ep.Number == 0 {
-			return nil
+			if !checkEpoch {
+				return tdmExtra, false, nil
+			}
+			if tdmExtra.Height > 1 {
+				return nil, false, fmt.Errorf("epoch 0 carried by non genesis block %v", tdmExtra.Height)
+			}
+			genesisEpoch = ep
 		}
 	}
 
@@ -407,48 +523,81 @@ func (cch *CrossChainHelper) VerifyChildChainProofData(bs []byte) error {
 	if chainId != "child_0" {
 		ci := core.GetChainInfo(cch.chainInfoDB, chainId)
 		if ci == nil {
-			return fmt.Errorf("chain info %s not found", chainId)
+			// the genesis epoch can't be verified before the child chain launched, it is never trusted
+			if genesisEpoch != nil {
+				return tdmExtra, false, nil
+			}
+			return nil, false, fmt.Errorf("chain info %s not found", chainId)
 		}
-		epoch := ci.GetEpochByBlockNumber(tdmExtra.Height)
-		if epoch == nil {
-			return fmt.Errorf("could not get epoch for block height %v", tdmExtra.Height)
+
+		// the genesis epoch is only verified by the chain info, never by the batch
+		var ep *epoch.Epoch
+		for i := len(batchEpochs) - 1; i >= 0 && genesisEpoch == nil; i-- {
+			if batchEpochs[i].StartBlock <= tdmExtra.Height && tdmExtra.Height <= batchEpochs[i].EndBlock {
+				ep = batchEpochs[i]
+				break
+			}
 		}
+		if ep == nil {
+			ep = ci.GetEpochByBlockNumber(tdmExtra.Height)
+			if ep == nil {
+				if genesisEpoch != nil {
+					return tdmExtra, false, nil
+				}
+				return nil, false, fmt.Errorf("could not get epoch for block height %v", tdmExtra.Height)
+			}
 
-		if epoch.Number > ci.EpochNumber {
-			ci.EpochNumber = epoch.Number
-			ci.Epoch = epoch
-			core.SaveChainInfo(cch.chainInfoDB, ci)
+			if ep.Number > ci.EpochNumber {
+				ci.EpochNumber = ep.Number
+				ci.Epoch = ep
+				core.SaveChainInfo(cch.chainInfoDB, ci)
+			}
 		}
 
-		valSet := epoch.Validators
+		valSet := ep.Validators
 		if !bytes.Equal(valSet.Hash(), tdmExtra.ValidatorsHash) {
-			return errors.New("inconsistent validator set")
+			return nil, false, errors.New("inconsistent validator set")
+		}
+
+		if genesisEpoch != nil && (ep.Number != 0 || !bytes.Equal(genesisEpoch.Validators.Hash(), valSet.Hash())) {
+			return nil, false, errors.New("inconsistent genesis epoch")
 		}
 
 		seenCommit := tdmExtra.SeenCommit
 		if !bytes.Equal(tdmExtra.SeenCommitHash, seenCommit.Hash()) {
-			return errors.New("invalid committed seals")
+			return nil, false, errors.New("invalid committed seals")
 		}
 
 		if err = valSet.VerifyCommit(tdmExtra.ChainID, tdmExtra.Height, seenCommit); err != nil {
-			return err
+			return nil, false, err
 		}
+
+		return tdmExtra, true, nil
 	}
 
-	log.Debug("VerifyChildChainProofData - end")
-	return nil
+	return tdmExtra, false, nil
 }
 
 func (cch *CrossChainHelper) SaveChildChainProofDataToMainChain(bs []byte) error {
 	log.Debug("SaveChildChainProofDataToMainChain - start")
 
-	var proofData types.ChildChainProofData
-	err := rlp.DecodeBytes(bs, &proofData)
+	headers, err := types.DecodeChildChainProofData(bs)
 	if err != nil {
 		return err
 	}
 
-	header := proofData.Header
+	for _, header := range headers {
+		if err := cch.saveChildChainHeader(header); err != nil {
+			return err
+		}
+	}
+
+	log.Debug("SaveChildChainProofDataToMainChain - end")
+	return nil
+}
+
+// saveChildChainHeader saves the epoch carried by the child chain header into chain info db
+func (cch *CrossChainHelper) saveChildChainHeader(header *types.Header) error {
 	tdmExtra, err := tdmTypes.ExtractTendermintExtra(header)
 	if err != nil {
 		return err
@@ -491,7 +640,6 @@ func (cch *CrossChainHelper) SaveChildChainProofDataToMainChain(bs []byte) error
 		}
 	}
 
-	log.Debug("SaveChildChainProofDataToMainChain - end")
 	return nil
 }
 
@@ -591,8 +739,6 @@ func (cch *CrossChainHelper) ValidateTX4WithInMemTX3ProofData(tx4 *types.Transac
 		return core.ErrInvalidSender
 	}
 
-	var args intAbi.WithdrawFromMainChainArgs
-
 	if !intAbi.IsIntChainContractAddr(tx4.To()) {
 		return errors.New("invalid TX4: wrong To()")
 	}
@@ -603,14 +749,10 @@ func (cch *CrossChainHelper) ValidateTX4WithInMemTX3ProofData(tx4 *types.Transac
 		return err
 	}
 
-	if function != intAbi.WithdrawFromMainChain {
+	if function != intAbi.WithdrawFromMainChain && function != intAbi.UnlockTokenInMainChain {
 		return errors.New("invalid TX4: wrong function")
 	}
 
-	if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.WithdrawFromMainChain.String(), data[4:]); err != nil {
-		return err
-	}
-
 	// TX3
 	header := tx3ProofData.Header
 	if err != nil {
@@ -635,15 +777,65 @@ func (cch *CrossChainHelper) ValidateTX4WithInMemTX3ProofData(tx4 *types.Transac
 		return core.ErrInvalidSender
 	}
 
-	var tx3Args intAbi.WithdrawFromChildChainArgs
+	if !intAbi.IsIntChainContractAddr(tx3.To()) {
+		return errors.New("invalid TX3: wrong To()")
+	}
+
 	tx3Data := tx3.Data()
-	if err := intAbi.ChainABI.UnpackMethodInputs(&tx3Args, intAbi.WithdrawFromChildChain.String(), tx3Data[4:]); err != nil {
+	if len(tx3Data) < 4 {
+		return errors.New("invalid TX3: wrong data")
+	}
+	tx3Function, err := intAbi.FunctionTypeFromId(tx3Data[:4])
+	if err != nil {
 		return err
 	}
 
-	// Does TX3 & TX4 Match
-	if from != tx3From || args.ChainId != tx3Args.ChainId || args.Amount.Cmp(tx3.Value()) != 0 {
-		return errors.New("params are not consistent with tx in child chain")
+	switch function {
+	case intAbi.WithdrawFromMainChain:
+		var args intAbi.WithdrawFromMainChainArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.WithdrawFromMainChain.String(), data[4:]); err != nil {
+			return err
+		}
+
+		// TX3 has to be the withdraw claimed by TX4, the token burns are unlocked by their own tx
+		if tx3Function != intAbi.WithdrawFromChildChain {
+			return errors.New("invalid TX3: function not match TX4")
+		}
+
+		var tx3Args intAbi.WithdrawFromChildChainArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&tx3Args, intAbi.WithdrawFromChildChain.String(), tx3Data[4:]); err != nil {
+			return err
+		}
+
+		// Does TX3 & TX4 Match
+		if from != tx3From || args.ChainId != tx3Args.ChainId || args.Amount.Cmp(tx3.Value()) != 0 {
+			return errors.New("params are not consistent with tx in child chain")
+		}
+	case intAbi.UnlockTokenInMainChain:
+		var args intAbi.UnlockTokenInMainChainArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.UnlockTokenInMainChain.String(), data[4:]); err != nil {
+			return err
+		}
+
+		// TX3 has to be the token burn unlocked by TX4, proven by the block of the child chain it burnt on
+		if tx3Function != intAbi.BurnTokenInChildChain {
+			return errors.New("invalid TX3: function not match TX4")
+		}
+
+		var tx3Args intAbi.BurnTokenInChildChainArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&tx3Args, intAbi.BurnTokenInChildChain.String(), tx3Data[4:]); err != nil {
+			return err
+		}
+
+		tdmExtra, err := tdmTypes.ExtractTendermintExtra(header)
+		if err != nil {
+			return err
+		}
+
+		// Does TX3 & TX4 Match
+		if from != tx3From || args.TxHash != tx3.Hash() || args.ChainId != tx3Args.ChainId || args.ChainId != tdmExtra.ChainID {
+			return errors.New("params are not consistent with tx in child chain")
+		}
 	}
 
 	return nil
@@ -825,7 +1017,11 @@ func (cch *CrossChainHelper) DeleteTX3(chainId string, txHash common.Hash) {
 }
 
 func (cch *CrossChainHelper) WriteTX3ProofData(proofData *types.TX3ProofData) error {
-	return rawdb.WriteTX3ProofData(cch.localTX3CacheDB, proofData)
+	var epochNumber uint64
+	if _, ep := cch.GetEpochFromMainChain(); ep != nil {
+		epochNumber = ep.Number
+	}
+	return rawdb.WriteTX3ProofData(cch.localTX3CacheDB, proofData, epochNumber)
 }
 
 func (cch *CrossChainHelper) GetTX3ProofData(chainId string, txHash common.Hash) *types.TX3ProofData {
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	dbm "github.com/intfoundation/go-db"
+	"github.com/intfoundation/intchain/cmd/utils"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/console"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const (
+	chainDataDB = "chaindata"
+	tx3CacheDB  = "tx3cache"
+	epochDB     = "epoch"
+	chainInfoDB = "chaininfo"
+)
+
+var (
+	dbNameFlag = cli.StringFlag{
+		Name:  "db.name",
+		Usage: `Database to operate on ("chaindata", "tx3cache", "epoch", "chaininfo")`,
+		Value: chainDataDB,
+	}
+	dbCommand = cli.Command{
+		Name:      "db",
+		Usage:     "Low level database operations",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Inspect the storage size for each type of data in the databases",
+				ArgsUsage: "<chainname>",
+				Action:    utils.MigrateFlags(inspectDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+				},
+				Description: `
+The inspect command walks the chain database, the local TX3 cache, the epoch
+database and the chain info database, and reports the number of entries and
+their size for each key prefix. The node should be stopped.`,
+			},
+			{
+				Name:      "get",
+				Usage:     "Show the value of a database key",
+				ArgsUsage: "<chainname> <hex-encoded key>",
+				Action:    utils.MigrateFlags(dbGet),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					dbNameFlag,
+				},
+				Description: `
+The get command prints the hex-encoded value of the key in the database chosen
+with --db.name, the chain database by default.`,
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a database key (WARNING: may corrupt your database)",
+				ArgsUsage: "<chainname> <hex-encoded key>",
+				Action:    utils.MigrateFlags(dbDelete),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					dbNameFlag,
+				},
+				Description: `
+The delete command removes the key from the database chosen with --db.name, the
+chain database by default. It's meant for the surgical repair of a database and
+asks for confirmation. The node should be stopped.`,
+			},
+		},
+	}
+)
+
+// epochCategories are the categories of the epoch database entries, see the
+// keys in consensus/ipbft/epoch.
+var epochCategories = []rawdb.InspectCategory{
+	{Name: "Epochs", Match: hasPrefix("Epoch:")},
+	{Name: "Epoch validator votes", Match: hasPrefix("EpochValidatorVote_")},
+	{Name: "Latest epoch", Match: hasPrefix("LatestEpoch")},
+}
+
+// chainInfoCategories are the categories of the chain info database entries,
+// see the keys in core/chains_info.go.
+var chainInfoCategories = []rawdb.InspectCategory{
+	{Name: "Chain infos", Match: hasPrefix("CHAIN:")},
+	{Name: "Chain epochs", Match: hasPrefix("CHAIN-")},
+	{Name: "Pending chain infos", Match: hasPrefix("PENDING_CHAIN:")},
+	{Name: "Genesis", Match: func(key []byte) bool {
+		return hasPrefix("ETH_GENESIS:")(key) || hasPrefix("TDM_GENESIS:")(key) || hasPrefix("GENESIS_TEMPLATE:")(key)
+	}},
+	{Name: "Chain indexes", Match: func(key []byte) bool {
+		return bytes.Equal(key, []byte("AllChainID")) || bytes.Equal(key, []byte("PENDING_CHAIN_IDX"))
+	}},
+}
+
+// hasPrefix matches the keys of the given prefix.
+func hasPrefix(prefix string) func([]byte) bool {
+	return func(key []byte) bool {
+		return bytes.HasPrefix(key, []byte(prefix))
+	}
+}
+
+// rawStore is the key-value access to one of the node databases.
+type rawStore interface {
+	intdb.Reader
+	intdb.Writer
+	NewIterator() intdb.Iterator
+	io.Closer
+}
+
+// dbmStore adapts a tendermint database to the rawStore interface.
+type dbmStore struct {
+	db dbm.DB
+}
+
+func (s *dbmStore) Has(key []byte) (bool, error) { return s.db.Get(key) != nil, nil }
+
+func (s *dbmStore) Get(key []byte) ([]byte, error) {
+	if value := s.db.Get(key); value != nil {
+		return value, nil
+	}
+	return nil, fmt.Errorf("key %x not found", key)
+}
+
+func (s *dbmStore) Put(key []byte, value []byte) error {
+	s.db.SetSync(key, value)
+	return nil
+}
+
+func (s *dbmStore) Delete(key []byte) error {
+	s.db.DeleteSync(key)
+	return nil
+}
+
+func (s *dbmStore) NewIterator() intdb.Iterator { return s.db.Iterator() }
+
+func (s *dbmStore) Close() error {
+	s.db.Close()
+	return nil
+}
+
+// openRawStore opens the named database of the chain along with the categories
+// of its entries, nil if the database doesn't exist.
+func openRawStore(ctx *cli.Context, chainName string, name string) (rawStore, []rawdb.InspectCategory) {
+	dataDir := ctx.GlobalString(utils.DataDirFlag.Name)
+
+	switch name {
+	case chainDataDB:
+		stack, _ := makeConfigNode(ctx, chainName)
+		if !common.FileExist(stack.ResolvePath(chainDataDB)) {
+			stack.Close()
+			return nil, nil
+		}
+		return &nodeStore{utils.MakeChainDatabase(ctx, stack), stack}, rawdb.ChainDataCategories
+
+	case tx3CacheDB:
+		dir := filepath.Join(dataDir, tx3CacheDB)
+		if !common.FileExist(dir) {
+			return nil, nil
+		}
+		db, err := rawdb.OpenDatabase("", dir, 0, 0, "", "")
+		if err != nil {
+			utils.Fatalf("Could not open database: %v", err)
+		}
+		return db, rawdb.TX3Categories
+
+	case epochDB:
+		dir := utils.GetTendermintConfig(chainName, ctx).GetString("db_dir")
+		if !common.FileExist(filepath.Join(dir, epochDB+".db")) {
+			return nil, nil
+		}
+		return &dbmStore{dbm.NewDB(epochDB, dbm.LevelDBBackendStr, dir)}, epochCategories
+
+	case chainInfoDB:
+		if !common.FileExist(filepath.Join(dataDir, chainInfoDB+".db")) {
+			return nil, nil
+		}
+		return &dbmStore{dbm.NewDB(chainInfoDB, dbm.LevelDBBackendStr, dataDir)}, chainInfoCategories
+
+	default:
+		utils.Fatalf("Unknown database %q", name)
+	}
+	return nil, nil
+}
+
+// nodeStore is the chain database closing its node when closed.
+type nodeStore struct {
+	intdb.Database
+	stack *node.Node
+}
+
+func (s *nodeStore) Close() error {
+	err := s.Database.Close()
+	s.stack.Close()
+	return err
+}
+
+func inspectDB(ctx *cli.Context) error {
+	chainName := ctx.Args().First()
+	if chainName == "" {
+		utils.Fatalf("This command requires chain name specified.")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "DATABASE\tCATEGORY\tITEMS\tSIZE\t")
+
+	var total common.StorageSize
+	for _, name := range []string{chainDataDB, tx3CacheDB, epochDB, chainInfoDB} {
+		store, categories := openRawStore(ctx, chainName, name)
+		if store == nil {
+			log.Info("Database doesn't exist, skipping", "database", name)
+			continue
+		}
+		log.Info("Inspecting database", "database", name)
+		stats, err := rawdb.InspectIterator(store.NewIterator(), categories)
+		if err != nil {
+			store.Close()
+			utils.Fatalf("Failed to inspect %s: %v", name, err)
+		}
+		if db, ok := store.(intdb.AncientReader); ok {
+			stats = append(stats, rawdb.InspectAncients(db)...)
+		}
+		store.Close()
+
+		for _, stat := range stats {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%v\t\n", name, stat.Name, stat.Count, stat.Size)
+			total += stat.Size
+		}
+	}
+	fmt.Fprintf(w, "\t\tTOTAL\t%v\t\n", total)
+	return w.Flush()
+}
+
+// parseKey parses the hex-encoded key argument of the db commands.
+func parseKey(arg string) []byte {
+	key, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+	if err != nil || len(key) == 0 {
+		utils.Fatalf("Invalid hex-encoded key %q: %v", arg, err)
+	}
+	return key
+}
+
+func dbGet(ctx *cli.Context) error {
+	if len(ctx.Args()) < 2 {
+		utils.Fatalf("This command requires chain name and key.")
+	}
+	name := ctx.String(dbNameFlag.Name)
+	store, _ := openRawStore(ctx, ctx.Args().First(), name)
+	if store == nil {
+		utils.Fatalf("Database %s doesn't exist", name)
+	}
+	defer store.Close()
+
+	value, err := store.Get(parseKey(ctx.Args().Get(1)))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%#x\n", value)
+	return nil
+}
+
+func dbDelete(ctx *cli.Context) error {
+	if len(ctx.Args()) < 2 {
+		utils.Fatalf("This command requires chain name and key.")
+	}
+	name := ctx.String(dbNameFlag.Name)
+	store, _ := openRawStore(ctx, ctx.Args().First(), name)
+	if store == nil {
+		utils.Fatalf("Database %s doesn't exist", name)
+	}
+	defer store.Close()
+
+	key := parseKey(ctx.Args().Get(1))
+	value, err := store.Get(key)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %#x -> %#x\n", name, key, value)
+	confirm, err := console.Stdin.PromptConfirm("Delete this key?")
+	switch {
+	case err != nil:
+		utils.Fatalf("%v", err)
+	case !confirm:
+		log.Warn("Key deletion aborted")
+		return nil
+	}
+	if err := store.Delete(key); err != nil {
+		return err
+	}
+	log.Info("Key deleted", "database", name, "key", fmt.Sprintf("%#x", key))
+	return nil
+}
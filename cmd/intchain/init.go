@@ -201,7 +201,11 @@ func init_int_blockchain(chainId string, intGenesisPath string, ctx *cli.Context
 	dbPath := filepath.Join(utils.MakeDataDir(ctx), chainId, clientIdentifier, "/chaindata")
 	log.Infof("init_int_blockchain 0 with dbPath: %s", dbPath)
 
-	chainDb, err := rawdb.NewLevelDBDatabase(filepath.Join(utils.MakeDataDir(ctx), chainId, clientIdentifier, "/chaindata"), 0, 0, "intchain/db/chaindata/")
+	var engine string
+	if ctx.GlobalIsSet(utils.DBEngineFlag.Name) {
+		engine = ctx.GlobalString(utils.DBEngineFlag.Name)
+	}
+	chainDb, err := rawdb.OpenDatabase(engine, dbPath, 0, 0, "", "intchain/db/chaindata/")
 	if err != nil {
 		utils.Fatalf("could not open database: %v", err)
 	}
@@ -328,13 +332,29 @@ func createGenesisDoc(config cfg.Config, chainId string, coreGenesis *core.Genes
 	return nil
 }
 
-func generateTDMGenesis(childChainID string, validators []types.GenesisValidator) ([]byte, error) {
+func generateTDMGenesis(childChainID string, validators []types.GenesisValidator, template *core.ChildChainGenesisTemplate) ([]byte, error) {
 	var rewardScheme = types.RewardSchemeDoc{
 		TotalReward:        big.NewInt(0),
 		RewardFirstYear:    big.NewInt(0),
 		EpochNumberPerYear: 12,
 		TotalYear:          0,
 	}
+	var rewardPerBlock = big.NewInt(0)
+	var endBlock uint64 = 657000
+
+	// Apply the genesis template
+	if template != nil {
+		if template.RewardScheme != nil {
+			rewardScheme = *template.RewardScheme
+		}
+		if template.RewardPerBlock != nil {
+			rewardPerBlock = template.RewardPerBlock
+		}
+		if template.EpochLength != 0 {
+			// the first epoch starts from block 0
+			endBlock = template.EpochLength - 1
+		}
+	}
 
 	genDoc := types.GenesisDoc{
 		ChainID:      childChainID,
@@ -343,9 +363,9 @@ func generateTDMGenesis(childChainID string, validators []types.GenesisValidator
 		RewardScheme: rewardScheme,
 		CurrentEpoch: types.OneEpochDoc{
 			Number:         0,
-			RewardPerBlock: big.NewInt(0),
+			RewardPerBlock: rewardPerBlock,
 			StartBlock:     0,
-			EndBlock:       657000,
+			EndBlock:       endBlock,
 			Status:         0,
 			Validators:     validators,
 		},
@@ -433,9 +453,9 @@ func checkAccount(coreGenesis core.Genesis) (common.Address, *big.Int, error) {
 	return act, amount, nil
 }
 
-func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Config, validators []types.GenesisValidator) error {
+func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Config, validators []types.GenesisValidator, template *core.ChildChainGenesisTemplate) error {
 
-	contents, err := generateETHGenesis(childChainID, validators)
+	contents, err := generateETHGenesis(childChainID, validators, template)
 	if err != nil {
 		return err
 	}
@@ -447,9 +467,27 @@ func initEthGenesisFromExistValidator(childChainID string, childConfig cfg.Confi
 	return nil
 }
 
-func generateETHGenesis(childChainID string, validators []types.GenesisValidator) ([]byte, error) {
+func initTDMGenesisFromExistValidator(childChainID string, childConfig cfg.Config, validators []types.GenesisValidator, template *core.ChildChainGenesisTemplate) error {
+
+	genFile := childConfig.GetString("genesis_file")
+	if _, err := os.Stat(genFile); !os.IsNotExist(err) {
+		return nil
+	}
+
+	contents, err := generateTDMGenesis(childChainID, validators, template)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(genFile, contents, 0644); err != nil {
+		utils.Fatalf("write tdm genesis_file failed")
+		return err
+	}
+	return nil
+}
+
+func generateETHGenesis(childChainID string, validators []types.GenesisValidator, template *core.ChildChainGenesisTemplate) ([]byte, error) {
 	var coreGenesis = core.Genesis{
-		Config:     params.NewChildChainConfig(childChainID),
+		Config:     template.ChainConfig(childChainID),
 		Nonce:      0xdeadbeefdeadbeef,
 		Timestamp:  0x0,
 		ParentHash: common.Hash{},
@@ -460,11 +498,24 @@ func generateETHGenesis(childChainID string, validators []types.GenesisValidator
 		Coinbase:   common.Address{},
 		Alloc:      core.GenesisAlloc{},
 	}
+
+	// Apply the genesis template, validators' deposit override the same address
+	if template != nil {
+		if template.GasLimit != 0 {
+			coreGenesis.GasLimit = template.GasLimit
+		}
+		for address, account := range template.Alloc {
+			coreGenesis.Alloc[address] = account
+		}
+	}
+
 	for _, validator := range validators {
-		coreGenesis.Alloc[validator.EthAccount] = core.GenesisAccount{
-			Balance: big.NewInt(0),
-			Amount:  validator.Amount,
+		account := coreGenesis.Alloc[validator.EthAccount]
+		if account.Balance == nil {
+			account.Balance = big.NewInt(0)
 		}
+		account.Amount = validator.Amount
+		coreGenesis.Alloc[validator.EthAccount] = account
 	}
 
 	// Add Child Chain Default Token
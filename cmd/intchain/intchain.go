@@ -73,6 +73,8 @@ func intchainCmd(ctx *cli.Context) error {
 
 	chainMgr.StartInspectEvent()
 
+	chainMgr.StartTX3Sweeper()
+
 	go func() {
 		sigc := make(chan os.Signal, 1)
 		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
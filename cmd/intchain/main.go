@@ -36,6 +36,7 @@ var (
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
+		utils.AncientFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
 		utils.TxPoolNoLocalsFlag,
@@ -48,13 +49,21 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
-		//utils.FastSyncFlag,
+		utils.TxPoolSpecialSlotsFlag,
+		utils.TxPoolSpecialFunctionSlotsFlag,
+		utils.TxPoolPrivateBlocksFlag,
+		utils.FastSyncFlag,
 		utils.SyncModeFlag,
 		utils.GCModeFlag,
+		utils.TxLookupLimitFlag,
+		utils.DBEngineFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
 		utils.CacheTrieFlag,
 		utils.CacheGCFlag,
+		utils.CacheSnapshotFlag,
+		utils.PruneFlag,
+		utils.PruneIOBudgetFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
@@ -63,6 +72,7 @@ var (
 		utils.MinerGasTargetFlag,
 		utils.MinerGasLimitFlag,
 		utils.MinerGasPriceFlag,
+		utils.MinerSpecialGasReserveFlag,
 		utils.MinerCoinbaseFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
@@ -85,6 +95,7 @@ var (
 
 		//utils.LogDirFlag,
 		utils.ChildChainFlag,
+		utils.TX3RetentionFlag,
 	}
 
 	rpcFlags = []cli.Flag{
@@ -113,11 +124,17 @@ func init() {
 		initINTGenesisCmd,
 		initCommand,
 		//initChildChainCmd,
+		childChainCommand,
 		importCommand,
 		exportCommand,
 		copydbCommand,
 		removedbCommand,
+		dbCommand,
+		verifyChainCommand,
 		dumpCommand,
+		pruneStateCommand,
+		exportStateCommand,
+		importStateCommand,
 		// See monitorcmd.go:
 		monitorCommand,
 		// See accountcmd.go:
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"time"
+
+	"github.com/intfoundation/intchain/cmd/utils"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/types"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/metrics"
+)
+
+// tx3SweepInterval is the interval the expired tx3 proof data to be swept
+const tx3SweepInterval = 10 * time.Minute
+
+var (
+	tx3ProofGauge  = metrics.NewRegisteredGauge("chain/tx3/proofs", nil)
+	tx3TxGauge     = metrics.NewRegisteredGauge("chain/tx3/txs", nil)
+	tx3PrunedMeter = metrics.NewRegisteredMeter("chain/tx3/pruned", nil)
+)
+
+func init() {
+	core.RegisterInsertBlockCb("DeleteConsumedTX3", deleteConsumedTX3)
+}
+
+// deleteConsumedTX3 deletes the tx3 from the local cache once the tx4 consuming it is finalized on the main chain
+func deleteConsumedTX3(bc *core.BlockChain, block *types.Block) {
+	cch := bc.GetCrossChainHelper()
+	if cch == nil || !bc.Config().IsMainChain() {
+		return
+	}
+
+	for _, tx := range block.Transactions() {
+		if !intAbi.IsIntChainContractAddr(tx.To()) {
+			continue
+		}
+
+		data := tx.Data()
+		function, err := intAbi.FunctionTypeFromId(data[:4])
+		if err != nil {
+			continue
+		}
+
+		var chainId string
+		var txHash common.Hash
+		switch function {
+		case intAbi.WithdrawFromMainChain:
+			var args intAbi.WithdrawFromMainChainArgs
+			if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.WithdrawFromMainChain.String(), data[4:]); err != nil {
+				continue
+			}
+			chainId, txHash = args.ChainId, args.TxHash
+		case intAbi.UnlockTokenInMainChain:
+			var args intAbi.UnlockTokenInMainChainArgs
+			if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.UnlockTokenInMainChain.String(), data[4:]); err != nil {
+				continue
+			}
+			chainId, txHash = args.ChainId, args.TxHash
+		default:
+			continue
+		}
+
+		if cch.GetTX3(chainId, txHash) != nil {
+			cch.DeleteTX3(chainId, txHash)
+			tx3PrunedMeter.Mark(1)
+		}
+	}
+}
+
+// StartTX3Sweeper starts the background routine to expire the tx3 proof data consumed on the main chain
+// but missed by the insert block callback, such as the tx3 received after the block consuming it
+func (cm *ChainManager) StartTX3Sweeper() {
+	retention := cm.ctx.GlobalUint64(utils.TX3RetentionFlag.Name)
+
+	cm.tx3SweeperQuit = make(chan struct{})
+	cm.tx3SweeperDone = make(chan struct{})
+	go cm.sweepTX3(retention)
+}
+
+// StopTX3Sweeper stops the tx3 sweeper and waits for the running sweep
+func (cm *ChainManager) StopTX3Sweeper() {
+	if cm.tx3SweeperQuit == nil {
+		return
+	}
+	close(cm.tx3SweeperQuit)
+	<-cm.tx3SweeperDone
+	cm.tx3SweeperQuit = nil
+}
+
+func (cm *ChainManager) sweepTX3(retention uint64) {
+	defer close(cm.tx3SweeperDone)
+
+	ticker := time.NewTicker(tx3SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		cm.sweepTX3Once(retention)
+
+		select {
+		case <-ticker.C:
+		case <-cm.tx3SweeperQuit:
+			return
+		}
+	}
+}
+
+func (cm *ChainManager) sweepTX3Once(retention uint64) {
+	_, ep := cm.cch.GetEpochFromMainChain()
+	if ep == nil {
+		return
+	}
+
+	// retention 0 means keep forever, only collect the store size
+	var expireEpoch uint64
+	if retention != 0 && ep.Number > retention {
+		expireEpoch = ep.Number - retention
+	}
+
+	// only the tx3 consumed by the finalized main chain are expired, the validation
+	// of the tx consuming tx3 must not depend on the local retention
+	state, err := MustGetIntChainFromNode(cm.mainChain.IntNode).BlockChain().State()
+	if err != nil {
+		return
+	}
+	consumed := func(tx *types.Transaction) bool {
+		from, err := types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+		return err == nil && state.HasTX3(from, tx.Hash())
+	}
+
+	pruned, stats := rawdb.PruneTX3ProofData(cm.cch.localTX3CacheDB, expireEpoch, ep.Number, consumed)
+
+	tx3ProofGauge.Update(int64(stats.Proofs))
+	tx3TxGauge.Update(int64(stats.Txs))
+	tx3PrunedMeter.Mark(int64(pruned))
+
+	if pruned > 0 {
+		log.Infof("TX3 sweeper pruned %d consumed tx3 received before epoch %d, remaining proofs: %d, tx3: %d", pruned, expireEpoch, stats.Proofs, stats.Txs)
+	}
+}
@@ -67,12 +67,15 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			configFileFlag,
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.NetworkIdFlag,
 			utils.TestnetFlag,
 			utils.SyncModeFlag,
 			utils.GCModeFlag,
+			utils.TxLookupLimitFlag,
+			utils.DBEngineFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 		},
@@ -90,6 +93,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolSpecialSlotsFlag,
+			utils.TxPoolSpecialFunctionSlotsFlag,
+			utils.TxPoolPrivateBlocksFlag,
 		},
 	},
 	{
@@ -99,6 +105,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.CacheDatabaseFlag,
 			utils.CacheTrieFlag,
 			utils.CacheGCFlag,
+			utils.CacheSnapshotFlag,
+			utils.PruneFlag,
+			utils.PruneIOBudgetFlag,
 		},
 	},
 	{
@@ -152,6 +161,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MiningEnabledFlag,
 			utils.MinerThreadsFlag,
 			utils.MinerGasPriceFlag,
+			utils.MinerSpecialGasReserveFlag,
 			utils.MinerGasTargetFlag,
 			utils.MinerGasLimitFlag,
 			utils.MinerCoinbaseFlag,
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	dbm "github.com/intfoundation/go-db"
+	"github.com/intfoundation/intchain/cmd/utils"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus/ipbft"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+	"github.com/intfoundation/intchain/console"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state/statefile"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/core/vm"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	verifyFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block to verify",
+	}
+	verifyToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block to verify (default = head block)",
+	}
+	verifyStateFlag = cli.BoolFlag{
+		Name:  "state",
+		Usage: "Verify the integrity of the states of the blocks as well",
+	}
+	verifyChainCommand = cli.Command{
+		Action:    utils.MigrateFlags(verifyChain),
+		Name:      "verify-chain",
+		Usage:     "Verify the consistency of the local chain data",
+		ArgsUsage: "<chainname>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			verifyFromFlag,
+			verifyToFlag,
+			verifyStateFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The verify-chain command checks the canonical blocks of the range: the canonical
+hash index, the header linkage, the IPBFT committed seals against the validators
+of the stored epochs, and the transaction, uncle and receipt roots of the bodies
+and receipts. With --state, the trie nodes and contract codes of the stored states
+of the blocks are rehashed as well, the state of the head block must exist.
+
+The first inconsistency found is reported, and the chain can then be rolled back
+to the block before it. The node must be stopped.`,
+	}
+)
+
+// chainVerifier checks the canonical blocks of the chain database in ascending
+// order.
+type chainVerifier struct {
+	db      intdb.Database
+	epochDB dbm.DB
+	epoch   *epoch.Epoch
+	states  *statefile.Verifier // nil if the states are not verified
+	head    common.Hash
+	tail    uint64 // the first block with receipts
+
+	checked, skipped int // the number of states checked and skipped
+}
+
+// epochOf returns the epoch of the block, moving on from the epoch of the previous
+// block.
+func (v *chainVerifier) epochOf(number uint64) (*epoch.Epoch, error) {
+	if ep := v.epoch; ep != nil && ep.StartBlock <= number && number <= ep.EndBlock {
+		return ep, nil
+	}
+	if v.epoch != nil {
+		if ep := epoch.LoadEpoch(v.epochDB, v.epoch.Number+1, nil); ep != nil && ep.StartBlock <= number && number <= ep.EndBlock {
+			v.epoch = ep
+			return ep, nil
+		}
+	}
+	ep, err := epoch.LoadEpochByBlockNumber(v.epochDB, number)
+	if err != nil {
+		return nil, err
+	}
+	v.epoch = ep
+	return ep, nil
+}
+
+// verify checks the canonical block of the number, the child of the parent
+// header, and returns its header.
+func (v *chainVerifier) verify(number uint64, parent *types.Header) (*types.Header, error) {
+	hash := rawdb.ReadCanonicalHash(v.db, number)
+	if hash == (common.Hash{}) {
+		return nil, fmt.Errorf("canonical hash missing")
+	}
+	header := rawdb.ReadHeader(v.db, hash, number)
+	if header == nil {
+		return nil, fmt.Errorf("header %x missing", hash)
+	}
+	if have := header.Hash(); have != hash {
+		return nil, fmt.Errorf("header hash mismatch: have %x, want %x", have, hash)
+	}
+	if stored := rawdb.ReadHeaderNumber(v.db, hash); stored == nil || *stored != number {
+		return nil, fmt.Errorf("header number index of %x mismatch: have %v, want %d", hash, stored, number)
+	}
+	if parent != nil && header.ParentHash != parent.Hash() {
+		return nil, fmt.Errorf("parent hash mismatch: have %x, want %x", header.ParentHash, parent.Hash())
+	}
+
+	// The genesis block is not sealed
+	if number > 0 {
+		ep, err := v.epochOf(number)
+		if err != nil {
+			return nil, fmt.Errorf("epoch of block: %v", err)
+		}
+		if _, err := ipbft.VerifyCommittedSeals(header, ep); err != nil {
+			return nil, fmt.Errorf("committed seals of epoch %d: %v", ep.Number, err)
+		}
+	}
+
+	body := rawdb.ReadBody(v.db, hash, number)
+	if body == nil {
+		return nil, fmt.Errorf("body %x missing", hash)
+	}
+	if root := types.DeriveSha(types.Transactions(body.Transactions)); root != header.TxHash {
+		return nil, fmt.Errorf("transaction root mismatch: have %x, want %x", root, header.TxHash)
+	}
+	if uncleHash := types.CalcUncleHash(body.Uncles); uncleHash != header.UncleHash {
+		return nil, fmt.Errorf("uncle hash mismatch: have %x, want %x", uncleHash, header.UncleHash)
+	}
+
+	// The receipts below the transaction index tail are pruned
+	if number >= v.tail {
+		receipts := rawdb.ReadReceipts(v.db, hash, number)
+		if receipts == nil && len(body.Transactions) > 0 {
+			return nil, fmt.Errorf("receipts %x missing", hash)
+		}
+		if root := types.DeriveSha(receipts); root != header.ReceiptHash {
+			return nil, fmt.Errorf("receipt root mismatch: have %x, want %x", root, header.ReceiptHash)
+		}
+		if bloom := types.CreateBloom(receipts); bloom != header.Bloom {
+			return nil, fmt.Errorf("receipt bloom mismatch")
+		}
+	}
+
+	if v.states != nil {
+		if ok, _ := v.db.Has(header.Root.Bytes()); ok {
+			if _, err := v.states.Verify(header.Root); err != nil {
+				return nil, fmt.Errorf("state %x: %v", header.Root, err)
+			}
+			v.checked++
+		} else if hash == v.head {
+			return nil, fmt.Errorf("state %x of the head block missing", header.Root)
+		} else {
+			// The states of the older blocks are pruned
+			v.skipped++
+		}
+	}
+	return header, nil
+}
+
+func verifyChain(ctx *cli.Context) error {
+	chainName := ctx.Args().First()
+	if chainName == "" {
+		utils.Fatalf("This command requires chain name specified.")
+	}
+
+	stack, cfg := makeConfigNode(ctx, chainName)
+	defer stack.Close()
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	epochDB := dbm.NewDB("epoch", "leveldb", utils.GetTendermintConfig(chainName, ctx).GetString("db_dir"))
+	defer epochDB.Close()
+
+	head := rawdb.ReadHeadBlockHash(chainDb)
+	headNumber := rawdb.ReadHeaderNumber(chainDb, head)
+	if headNumber == nil {
+		utils.Fatalf("The chain is not initialized, run init first")
+	}
+	from, to := ctx.Uint64(verifyFromFlag.Name), *headNumber
+	if ctx.IsSet(verifyToFlag.Name) && ctx.Uint64(verifyToFlag.Name) < to {
+		to = ctx.Uint64(verifyToFlag.Name)
+	}
+	if from > to {
+		utils.Fatalf("Invalid block range %d-%d, the head block is %d", from, to, *headNumber)
+	}
+
+	v := &chainVerifier{db: chainDb, epochDB: epochDB, head: head}
+	if tail := rawdb.ReadTxIndexTail(chainDb); tail != nil {
+		v.tail = *tail
+	}
+	if ctx.Bool(verifyStateFlag.Name) {
+		v.states = statefile.NewVerifier(chainDb)
+	}
+
+	var (
+		parent *types.Header
+		start  = time.Now()
+		logged = time.Now()
+	)
+	if from > 0 {
+		parent = rawdb.ReadHeader(chainDb, rawdb.ReadCanonicalHash(chainDb, from-1), from-1)
+	}
+	for number := from; number <= to; number++ {
+		header, err := v.verify(number, parent)
+		if err != nil {
+			fmt.Printf("Inconsistency at block %d: %v\n", number, err)
+			return rollback(chainDb, cfg, number)
+		}
+		parent = header
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying chain", "number", number, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	fmt.Printf("Verified blocks %d-%d in %v\n", from, to, time.Since(start))
+	if v.states != nil {
+		fmt.Printf("States verified: %d, skipped: %d\n", v.checked, v.skipped)
+	}
+	return nil
+}
+
+// rollback offers to roll the chain back to the block before the inconsistent
+// one.
+func rollback(chainDb intdb.Database, cfg gethConfig, number uint64) error {
+	if number == 0 {
+		utils.Fatalf("The genesis block is inconsistent, the chain must be initialized again")
+	}
+	confirm, err := console.Stdin.PromptConfirm(fmt.Sprintf("Roll back the chain to block %d?", number-1))
+	switch {
+	case err != nil:
+		utils.Fatalf("%v", err)
+	case !confirm:
+		return fmt.Errorf("chain inconsistent at block %d", number)
+	}
+
+	config, _, err := core.SetupGenesisBlock(chainDb, nil)
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	config.ChainLogger = cfg.Node.Logger
+	cache := &core.CacheConfig{
+		TrieCleanLimit: cfg.Eth.TrieCleanCache,
+		TrieDirtyLimit: cfg.Eth.TrieDirtyCache,
+		TrieTimeLimit:  cfg.Eth.TrieTimeout,
+		TxLookupLimit:  cfg.Eth.TxLookupLimit,
+	}
+	chain, err := core.NewBlockChain(chainDb, cache, config, nil, vm.Config{}, nil)
+	if err != nil {
+		utils.Fatalf("Can't create BlockChain: %v", err)
+	}
+	defer chain.Stop()
+
+	if err := chain.SetHead(number - 1); err != nil {
+		utils.Fatalf("Failed to roll back the chain: %v", err)
+	}
+	fmt.Printf("Rolled back the chain to block %d\n", chain.CurrentBlock().NumberU64())
+	return nil
+}
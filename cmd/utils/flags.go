@@ -32,6 +32,7 @@ import (
 	"github.com/intfoundation/intchain/common/fdlimit"
 	"github.com/intfoundation/intchain/consensus"
 	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/rawdb"
 	"github.com/intfoundation/intchain/core/vm"
 	"github.com/intfoundation/intchain/crypto"
 	"github.com/intfoundation/intchain/intdb"
@@ -112,6 +113,10 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{node.DefaultDataDir()},
 	}
+	AncientFlag = DirectoryFlag{
+		Name:  "datadir.ancient",
+		Usage: "Data directory for ancient chain segments, enables the freezer (default = disabled)",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -153,7 +158,7 @@ var (
 	SyncModeFlag    = TextMarshalerFlag{
 		Name: "syncmode",
 		//Usage: `Blockchain sync mode ("fast", "full", or "light")`,
-		Usage: `Blockchain sync mode ("full")`,
+		Usage: `Blockchain sync mode ("fast" or "full")`,
 		Value: &defaultSyncMode,
 	}
 	GCModeFlag = cli.StringFlag{
@@ -161,6 +166,16 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "archive",
 	}
+	TxLookupLimitFlag = cli.Uint64Flag{
+		Name:  "txlookuplimit",
+		Usage: "Number of recent blocks to retain the receipts and transaction indexes of (default = 0, the entire chain)",
+		Value: 0,
+	}
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: `Backing database implementation of new databases ("leveldb", "pebble")`,
+		Value: "leveldb",
+	}
 
 	// Transaction pool settings
 	TxPoolNoLocalsFlag = cli.BoolFlag{
@@ -212,6 +227,21 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: intprotocol.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolSpecialSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.specialslots",
+		Usage: "Maximum number of special IntChain transactions kept in the priority lane",
+		Value: intprotocol.DefaultConfig.TxPool.SpecialSlots,
+	}
+	TxPoolSpecialFunctionSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.specialfunctionslots",
+		Usage: "Maximum number of special IntChain transactions per function in the priority lane",
+		Value: intprotocol.DefaultConfig.TxPool.SpecialFunctionSlots,
+	}
+	TxPoolPrivateBlocksFlag = cli.Uint64Flag{
+		Name:  "txpool.privateblocks",
+		Usage: "Maximum number of blocks a private transaction is kept waiting for inclusion",
+		Value: intprotocol.DefaultConfig.TxPool.PrivateBlocks,
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -233,6 +263,11 @@ var (
 		Usage: "Percentage of cache memory allowance to use for trie pruning (default = 25% full mode, 0% archive mode)",
 		Value: 25,
 	}
+	CacheSnapshotFlag = cli.IntFlag{
+		Name:  "cache.snapshot",
+		Usage: "Percentage of cache memory allowance to use for state snapshot caching, 0 disables the snapshot",
+		Value: 10,
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -258,6 +293,11 @@ var (
 		Usage: "Minimal gas price for mining a transactions",
 		Value: intprotocol.DefaultConfig.MinerGasPrice,
 	}
+	MinerSpecialGasReserveFlag = cli.Uint64Flag{
+		Name:  "miner.specialgasreserve",
+		Usage: "Percentage of the block gas reserved for the special IntChain transactions",
+		Value: intprotocol.DefaultConfig.MinerSpecialGasReserve,
+	}
 	MinerCoinbaseFlag = cli.StringFlag{
 		Name:  "miner.etherbase",
 		Usage: "Public address for block mining rewards (default = first account)",
@@ -455,6 +495,21 @@ var (
 		Name:  "prune",
 		Usage: "Enable the Data Reduction feature, history state data will be pruned by default",
 	}
+	PruneIOBudgetFlag = cli.IntFlag{
+		Name:  "prune.iobudget",
+		Usage: "Database operations per second spent by the Data Reduction in background (0 = unlimited)",
+		Value: intprotocol.DefaultConfig.PruneIOBudget,
+	}
+	PruneRetainFlag = cli.Uint64Flag{
+		Name:  "prune.retain",
+		Usage: "Number of the latest block states retained by the prune-state command",
+		Value: 128,
+	}
+	BloomFilterSizeFlag = cli.Uint64Flag{
+		Name:  "bloomfilter.size",
+		Usage: "Megabytes of memory allocated to the bloom filter of the prune-state command",
+		Value: 2048,
+	}
 
 	//for performance test
 	PerfTestFlag = cli.BoolFlag{
@@ -478,6 +533,13 @@ var (
 		Usage: "Specify one or more child chain should be start. Ex: child-1,child-2",
 	}
 
+	// TX3 Retention Flag
+	TX3RetentionFlag = cli.Uint64Flag{
+		Name:  "tx3retention",
+		Usage: "Number of main chain epochs to keep the TX3 proof data consumed on the main chain, the TX3 not consumed yet are always kept (0 = keep forever)",
+		Value: 0,
+	}
+
 	// ----------------------------
 	// Tendermint Flags
 
@@ -906,6 +968,13 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(NoUSBFlag.Name) {
 		cfg.NoUSB = ctx.GlobalBool(NoUSBFlag.Name)
 	}
+
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = ctx.GlobalString(DBEngineFlag.Name)
+		if cfg.DBEngine != rawdb.DBLeveldb && cfg.DBEngine != rawdb.DBPebble {
+			Fatalf("Invalid choice for db.engine '%s', allowed 'leveldb' or 'pebble'", cfg.DBEngine)
+		}
+	}
 }
 
 func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
@@ -948,6 +1017,15 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolSpecialSlotsFlag.Name) {
+		cfg.SpecialSlots = ctx.GlobalUint64(TxPoolSpecialSlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolSpecialFunctionSlotsFlag.Name) {
+		cfg.SpecialFunctionSlots = ctx.GlobalUint64(TxPoolSpecialFunctionSlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolPrivateBlocksFlag.Name) {
+		cfg.PrivateBlocks = ctx.GlobalUint64(TxPoolPrivateBlocksFlag.Name)
+	}
 }
 
 // checkExclusive verifies that only a single isntance of the provided flags was
@@ -1024,18 +1102,28 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *intprotocol.Config) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 	}
 	cfg.DatabaseHandles = makeDatabaseHandles()
+	if ctx.GlobalIsSet(AncientFlag.Name) {
+		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cfg.NoPruning = ctx.GlobalString(GCModeFlag.Name) == "archive"
 
+	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
+		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
+	}
+
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
 	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cfg.TrieDirtyCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
+	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheSnapshotFlag.Name) {
+		cfg.SnapshotCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheSnapshotFlag.Name) / 100
+	}
 	if ctx.GlobalIsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.GlobalString(DocRootFlag.Name)
 	}
@@ -1051,6 +1139,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *intprotocol.Config) {
 	if ctx.GlobalIsSet(MinerGasPriceFlag.Name) {
 		cfg.MinerGasPrice = GlobalBig(ctx, MinerGasPriceFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerSpecialGasReserveFlag.Name) {
+		cfg.MinerSpecialGasReserve = ctx.GlobalUint64(MinerSpecialGasReserveFlag.Name)
+	}
 	if ctx.GlobalIsSet(VMEnableDebugFlag.Name) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
@@ -1068,6 +1159,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *intprotocol.Config) {
 
 	// Data Reduction Config
 	cfg.PruneStateData = ctx.GlobalBool(PruneFlag.Name)
+	if ctx.GlobalIsSet(PruneIOBudgetFlag.Name) {
+		cfg.PruneIOBudget = ctx.GlobalInt(PruneIOBudgetFlag.Name)
+	}
 	//cfg.PruneBlockData = ctx.GlobalBool(PruneBlockFlag.Name)
 }
 
@@ -1124,7 +1218,7 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) intdb.Database {
 		handles = makeDatabaseHandles()
 	)
 	name := "chaindata"
-	chainDb, err := stack.OpenDatabase(name, cache, handles, "")
+	chainDb, err := stack.OpenDatabaseWithFreezer(name, cache, handles, ctx.GlobalString(AncientFlag.Name), "")
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
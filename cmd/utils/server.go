@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/intfoundation/intchain/log"
 	"github.com/intfoundation/intchain/node"
+	"github.com/intfoundation/intchain/params"
 	"github.com/intfoundation/intchain/rpc"
 	"gopkg.in/urfave/cli.v1"
 	"net"
@@ -85,7 +86,10 @@ func HookupHTTP(chainId string, httpHandler *rpc.Server) error {
 	if httpMux != nil {
 		log.Infof("Hookup HTTP for (chainId, http Handler): (%v, %v)", chainId, httpHandler)
 		if httpHandler != nil {
-			httpMux.Handle("/", httpHandler)
+			httpMux.Handle("/"+chainId, httpHandler)
+			if isMainChain(chainId) {
+				httpMux.Handle("/", httpHandler)
+			}
 			httpHandlerMapping[chainId] = httpHandler
 		}
 	}
@@ -96,13 +100,22 @@ func HookupWS(chainId string, wsHandler *rpc.Server) error {
 	if wsMux != nil {
 		log.Infof("Hookup WS for (chainId, ws Handler): (%v, %v)", chainId, wsHandler)
 		if wsHandler != nil {
-			wsMux.Handle("/", wsHandler.WebsocketHandler(wsOrigins))
+			handler := wsHandler.WebsocketHandler(wsOrigins)
+			wsMux.Handle("/"+chainId, handler)
+			if isMainChain(chainId) {
+				wsMux.Handle("/", handler)
+			}
 			wsHandlerMapping[chainId] = wsHandler
 		}
 	}
 	return nil
 }
 
+// isMainChain reports whether the chain is served at the root path besides its chain id path
+func isMainChain(chainId string) bool {
+	return chainId == params.MainnetChainConfig.IntChainId || chainId == params.TestnetChainConfig.IntChainId
+}
+
 func startHTTP(endpoint string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
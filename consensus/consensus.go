@@ -174,3 +174,29 @@ type IPBFT interface {
 	// VerifyHeader checks whether a header conforms to the consensus rules of a given engine.
 	VerifyHeaderBeforeConsensus(chain ChainReader, header *types.Header, seal bool) error
 }
+
+// ProposerPredictor should be implemented if the consensus knows the proposers of
+// the next block in advance
+type ProposerPredictor interface {
+	// NextProposers returns the validators proposing the block on top of the given
+	// header, in the order of the consensus rounds, at most the given number of rounds
+	NextProposers(header *types.Header, rounds int) []common.Address
+}
+
+// FastSyncer should be implemented if the consensus keeps its own data outside the state,
+// which has to be restored when the state is downloaded by fast sync
+type FastSyncer interface {
+	// EpochSyncData returns the current epoch and its vote set served to the fast sync peers
+	EpochSyncData() (epochBytes []byte, voteSetBytes []byte)
+
+	// PrepareFastSync decodes the epoch data received from the sync peer and returns
+	// the highest pivot block, whose state can be downloaded
+	PrepareFastSync(epochBytes []byte, voteSetBytes []byte) (pivot uint64, err error)
+
+	// FastSyncCommitHead verifies the data received by PrepareFastSync against the synced
+	// chain and switches the consensus to the pivot block committed as the chain head
+	FastSyncCommitHead(chain ChainReader, header *types.Header) error
+
+	// FinishFastSync ends the fast sync cycle started by PrepareFastSync
+	FinishFastSync()
+}
@@ -76,6 +76,9 @@ type backend struct {
 	// event subscription for ChainHeadEvent event
 	broadcaster consensus.Broadcaster
 
+	// epochs restored from the headers during fast sync
+	fastSync fastSyncState
+
 	//recentMessages *lru.ARCCache // the cache of peer's messages
 	//knownMessages  *lru.ARCCache // the cache of self messages
 }
@@ -0,0 +1,49 @@
+package consensus
+
+import (
+	"github.com/intfoundation/intchain/consensus/ipbft/types"
+	ethTypes "github.com/intfoundation/intchain/core/types"
+)
+
+// checkpointInterval is the number of child chain blocks covered by one checkpoint,
+// the epochs in these blocks are saved to the main chain by one SaveDataToMainChain tx
+const checkpointInterval = 10
+
+// isCheckpoint reports whether the block is a checkpoint of the child chain.
+// The block carrying tx3 is always a checkpoint, so the main chain knows the epoch
+// before the tx3 proof data is broadcast.
+func isCheckpoint(tdmExtra *types.TendermintExtra) bool {
+	return tdmExtra.Height%checkpointInterval == 0 || tdmExtra.NeedToBroadcast
+}
+
+// checkpointBlocks collects the blocks need to be saved to the main chain since the previous checkpoint,
+// in ascending order of height. The checkpoint block at the given height is the last one.
+func (cs *ConsensusState) checkpointBlocks(height uint64) []*ethTypes.Block {
+	cr := cs.GetChainReader()
+
+	var blocks []*ethTypes.Block
+	for h := height; h > 0 && height-h < checkpointInterval; h-- {
+		block := cr.GetBlockByNumber(h)
+		if block == nil {
+			break
+		}
+		tdmExtra, err := types.ExtractTendermintExtra(block.Header())
+		if err != nil {
+			cs.logger.Error("checkpointBlocks: failed to extract tendermint extra", "height", h, "err", err)
+			break
+		}
+		// stop at the previous checkpoint, the blocks before it have been saved
+		if h != height && isCheckpoint(tdmExtra) {
+			break
+		}
+		if tdmExtra.NeedToSave {
+			blocks = append(blocks, block)
+		}
+	}
+
+	// reverse to ascending order
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+	return blocks
+}
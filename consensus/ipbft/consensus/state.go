@@ -520,6 +520,12 @@ func (cs *ConsensusState) proposersByVRF() (lastProposer int, curProposer int) {
 }
 
 func (cs *ConsensusState) proposerByVRF(headerHash common.Hash, validators []*types.Validator) (proposer int) {
+	return ProposerByVRF(headerHash, validators)
+}
+
+// ProposerByVRF returns the index of the validator proposing the round 0 block on top
+// of the header with the given hash (without time), -1 if the validators have no power.
+func ProposerByVRF(headerHash common.Hash, validators []*types.Validator) (proposer int) {
 
 	idx := -1
 
@@ -999,13 +1005,15 @@ func (cs *ConsensusState) enterPropose(height uint64, round int) {
 
 	// Save block to main chain (this happens only on validator node).
 	// Note!!! This will BLOCK the WHOLE consensus stack since it blocks receiveRoutine.
-	// TODO: what if there're more than one round for a height? 'saveBlockToMainChain' would be called more than once
-	if cs.state.TdmExtra.NeedToSave &&
+	// The blocks since the previous checkpoint are saved in one batch when the last block is a checkpoint.
+	// TODO: what if there're more than one round for a height? 'saveBlocksToMainChain' would be called more than once
+	if isCheckpoint(cs.state.TdmExtra) &&
 		(cs.state.TdmExtra.ChainID != params.MainnetChainConfig.IntChainId && cs.state.TdmExtra.ChainID != params.TestnetChainConfig.IntChainId) {
 		if cs.privValidator != nil && cs.IsProposer() {
-			cs.logger.Infof("enterPropose: saveBlockToMainChain height: %v", cs.state.TdmExtra.Height)
-			lastBlock := cs.GetChainReader().GetBlockByNumber(cs.state.TdmExtra.Height)
-			cs.saveBlockToMainChain(lastBlock)
+			if blocks := cs.checkpointBlocks(cs.state.TdmExtra.Height); len(blocks) > 0 {
+				cs.logger.Infof("enterPropose: saveBlocksToMainChain height: %v, blocks: %d", cs.state.TdmExtra.Height, len(blocks))
+				cs.saveBlocksToMainChain(blocks)
+			}
 			cs.state.TdmExtra.NeedToSave = false
 		}
 	}
@@ -1171,6 +1179,28 @@ func (cs *ConsensusState) createProposalBlock() (*types.TdmBlock, *types.PartSet
 		//	}
 		//}
 
+		// retrieve TX3ProofData for the token unlocks, so the validators don't rely on their own tx3 cache
+		for _, tx := range intBlock.Transactions() {
+			if !intAbi.IsIntChainContractAddr(tx.To()) {
+				continue
+			}
+
+			data := tx.Data()
+			function, err := intAbi.FunctionTypeFromId(data[:4])
+			if err != nil || function != intAbi.UnlockTokenInMainChain {
+				continue
+			}
+
+			var args intAbi.UnlockTokenInMainChainArgs
+			if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.UnlockTokenInMainChain.String(), data[4:]); err != nil {
+				continue
+			}
+
+			if proof := cs.cch.GetTX3ProofData(args.ChainId, args.TxHash); proof != nil {
+				tx3ProofData = append(tx3ProofData, proof)
+			}
+		}
+
 		return types.MakeBlock(cs.Height, cs.state.TdmExtra.ChainID, commit, intBlock,
 			val.Hash(), cs.Epoch.Number, epochBytes,
 			tx3ProofData, 65536)
@@ -1559,7 +1589,7 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 						continue
 					}
 
-					if function == intAbi.WithdrawFromChildChain {
+					if intAbi.IsTX3Function(function) {
 						block.TdmExtra.NeedToBroadcast = true
 						cs.logger.Infof("NeedToBroadcast set to true due to tx. Tx: %s, Chain: %s, Height: %v", function.String(), block.TdmExtra.ChainID, block.TdmExtra.Height)
 						break
@@ -2079,7 +2109,7 @@ func (cs *ConsensusState) ValidateTX4(b *types.TdmBlock) error {
 				continue
 			}
 
-			if function == intAbi.WithdrawFromMainChain {
+			if function == intAbi.WithdrawFromMainChain || function == intAbi.UnlockTokenInMainChain {
 				// index of tx4 and tx3ProofData should exactly match one by one.
 				if index >= len(b.TX3ProofData) {
 					return errors.New("tx3 proof data missing")
@@ -2094,6 +2124,13 @@ func (cs *ConsensusState) ValidateTX4(b *types.TdmBlock) error {
 				if err := cs.cch.ValidateTX4WithInMemTX3ProofData(tx, tx3ProofData); err != nil {
 					return err
 				}
+
+				// the token unlock is executed with the tx3 of the local cache, keep the proven one
+				if function == intAbi.UnlockTokenInMainChain {
+					if err := cs.cch.WriteTX3ProofData(tx3ProofData); err != nil {
+						return err
+					}
+				}
 			}
 		}
 	}
@@ -2101,19 +2138,26 @@ func (cs *ConsensusState) ValidateTX4(b *types.TdmBlock) error {
 	return nil
 }
 
-func (cs *ConsensusState) saveBlockToMainChain(block *ethTypes.Block) {
+// saveBlocksToMainChain saves the blocks to the main chain by one tx,
+// a single block is sent as ChildChainProofData, otherwise as ChildChainProofDataBatch
+func (cs *ConsensusState) saveBlocksToMainChain(blocks []*ethTypes.Block) {
 
 	client := cs.cch.GetClient()
 	ctx, _ := context.WithTimeout(context.Background(), 30*time.Second)
 	//ctx := context.Background() // testing only!
 
-	bs := []byte{}
-	proofData, err := ethTypes.NewChildChainProofData(block)
+	var proofData interface{}
+	var err error
+	if len(blocks) == 1 {
+		proofData, err = ethTypes.NewChildChainProofData(blocks[0])
+	} else {
+		proofData, err = ethTypes.NewChildChainProofDataBatch(blocks)
+	}
 	if err != nil {
-		cs.logger.Error("saveDataToMainChain: failed to create proof data", "block", block, "err", err)
+		cs.logger.Error("saveDataToMainChain: failed to create proof data", "blocks", len(blocks), "err", err)
 		return
 	}
-	bs, err = rlp.EncodeToBytes(proofData)
+	bs, err := rlp.EncodeToBytes(proofData)
 	if err != nil {
 		cs.logger.Error("saveDataToMainChain: failed to encode proof data", "proof data", proofData, "err", err)
 		return
@@ -3,14 +3,17 @@ package ipbft
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"github.com/hashicorp/golang-lru"
 	"github.com/intfoundation/go-wire"
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/consensus"
 	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
 	tdmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/consensus/misc"
 	"github.com/intfoundation/intchain/core/state"
 	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/log"
 	"github.com/intfoundation/intchain/params"
 	"github.com/intfoundation/intchain/rpc"
 	"math/big"
@@ -212,7 +215,8 @@ func (sb *backend) verifyHeader(chain consensus.ChainReader, header *types.Heade
 	}
 
 	// In case of Epoch switch, we have to wait for the Epoch switched first, then verify the following fields
-	if header.Number.Uint64() > sb.GetEpoch().EndBlock {
+	// During fast sync, the epochs are restored from the headers instead of the block processing
+	if header.Number.Uint64() > sb.GetEpoch().EndBlock && !sb.isFastSync() {
 		for {
 			duration := 2 * time.Second
 			sb.logger.Infof("IPBFT VerifyHeader, Epoch Switch, wait for %v then try again", duration)
@@ -279,6 +283,14 @@ func (sb *backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
 		return consensus.ErrUnknownAncestor
 	}
+	// Verify the base fee, it is only present after the London fork
+	if !chain.Config().IsLondon(header.Number) {
+		if header.BaseFee != nil {
+			return fmt.Errorf("invalid baseFee before fork: have %d, want <nil>", header.BaseFee)
+		}
+	} else if err := misc.VerifyEip1559Header(chain.Config(), parent, header); err != nil {
+		return err
+	}
 
 	err := sb.verifyCommittedSeals(chain, header, parents)
 	return err
@@ -355,43 +367,62 @@ func (sb *backend) VerifyUncles(chain consensus.ChainReader, block *types.Block)
 // verifyCommittedSeals checks whether every committed seal is signed by one of the parent's validators
 func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
 
-	tdmExtra, err := tdmTypes.ExtractTendermintExtra(header)
-	if err != nil {
-		return errInvalidExtraDataFormat
-	}
-
 	epoch := sb.core.consensusState.Epoch
 	if epoch == nil || epoch.Validators == nil {
 		sb.logger.Errorf("verifyCommittedSeals error. Epoch %v", epoch)
 		return errInconsistentValidatorSet
 	}
 
-	epoch = epoch.GetEpochByBlockNumber(header.Number.Uint64())
-	if epoch == nil || epoch.Validators == nil {
-		sb.logger.Errorf("verifyCommittedSeals error. Epoch %v", epoch)
-		return errInconsistentValidatorSet
+	if sb.isFastSync() {
+		epoch = sb.syncedEpochByBlockNumber(header.Number.Uint64())
+	} else {
+		epoch = epoch.GetEpochByBlockNumber(header.Number.Uint64())
+	}
+
+	tdmExtra, err := VerifyCommittedSeals(header, epoch)
+	if err != nil {
+		sb.logger.Errorf("verifyCommittedSeals error %v. Epoch %v", err, epoch)
+		return err
 	}
 
-	valSet := epoch.Validators
+	if sb.isFastSync() {
+		sb.restoreEpoch(header, tdmExtra, epoch)
+	}
+
+	return nil
+}
+
+// VerifyCommittedSeals checks whether the committed seals of the header are signed
+// by the validators of the given epoch of the block, returning the extra data.
+func VerifyCommittedSeals(header *types.Header, ep *epoch.Epoch) (*tdmTypes.TendermintExtra, error) {
+
+	tdmExtra, err := tdmTypes.ExtractTendermintExtra(header)
+	if err != nil {
+		return nil, errInvalidExtraDataFormat
+	}
+
+	if ep == nil || ep.Validators == nil {
+		return nil, errInconsistentValidatorSet
+	}
+
+	valSet := ep.Validators
 	if !bytes.Equal(valSet.Hash(), tdmExtra.ValidatorsHash) {
-		sb.logger.Errorf("verifyCommittedSeals error. Our Validator Set %x, tdmExtra Valdiator %x", valSet.Hash(), tdmExtra.ValidatorsHash)
-		sb.logger.Errorf("verifyCommittedSeals error. epoch validator set %v, extra data %v", valSet.String(), tdmExtra.String())
-		return errInconsistentValidatorSet
+		log.Debugf("verifyCommittedSeals error. Our Validator Set %x, tdmExtra Valdiator %x", valSet.Hash(), tdmExtra.ValidatorsHash)
+		return nil, errInconsistentValidatorSet
 	}
 
 	seenCommit := tdmExtra.SeenCommit
 	if !bytes.Equal(tdmExtra.SeenCommitHash, seenCommit.Hash()) {
-		sb.logger.Errorf("verifyCommittedSeals SeenCommit is %#+v", seenCommit)
-		sb.logger.Errorf("verifyCommittedSeals error. Our SeenCommitHash %x, tdmExtra SeenCommitHash %x", seenCommit.Hash(), tdmExtra.SeenCommitHash)
-		return errInvalidCommittedSeals
+		log.Debugf("verifyCommittedSeals error. Our SeenCommitHash %x, tdmExtra SeenCommitHash %x", seenCommit.Hash(), tdmExtra.SeenCommitHash)
+		return nil, errInvalidCommittedSeals
 	}
 
 	if err = valSet.VerifyCommit(tdmExtra.ChainID, tdmExtra.Height, seenCommit); err != nil {
-		sb.logger.Errorf("verifyCommittedSeals verify commit err %v", err)
-		return errInvalidSignature
+		log.Debugf("verifyCommittedSeals verify commit err %v", err)
+		return nil, errInvalidSignature
 	}
 
-	return nil
+	return tdmExtra, nil
 }
 
 // VerifySeal checks whether the crypto seal on a header is valid according to
@@ -740,6 +771,10 @@ func writeCommittedSeals(h *types.Header, tdmExtra *tdmTypes.TendermintExtra) er
 // Child Chain:
 // The total reward consists of the static block reward of Owner setup and total tx gas fee.
 //
+// After the London fork the total tx gas fee only holds the tips of the transactions,
+// the base fee is burnt or paid to the base fee treasury of the chain by the state
+// transition.
+//
 // If the coinbase is Candidate, divide the rewards by weight
 func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header, ep *epoch.Epoch, totalGasFee *big.Int) {
 	halfGasFee := big.NewInt(0).Div(totalGasFee, big.NewInt(2))
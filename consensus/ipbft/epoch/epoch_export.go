@@ -0,0 +1,141 @@
+package epoch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/intfoundation/go-db"
+	tmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core/types"
+)
+
+// The helpers below copy the epoch db along with an exported state, so a node
+// initialized from the state file starts with the epochs of the exported block.
+
+var (
+	// errNoEpochOfBlock is returned if the exported block is not in any epoch of the db
+	errNoEpochOfBlock = errors.New("no epoch of the block")
+	// errInvalidEpochEntry is returned if an imported entry is not an epoch db entry
+	errInvalidEpochEntry = errors.New("invalid epoch db entry")
+	// errUntrustedEpoch is returned if the imported epoch doesn't match the trusted block
+	errUntrustedEpoch = errors.New("epoch not trusted by the block")
+)
+
+// EpochDBEntry is a raw key value entry of the epoch db
+type EpochDBEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// ExportEpochDB returns the raw epoch db as of the block: the reward scheme, the epochs
+// up to the one of the block with their vote sets, and the next epoch once decided.
+func ExportEpochDB(epochDB db.DB, blockNumber uint64) ([]EpochDBEntry, error) {
+	rs := epochDB.Get([]byte(rewardSchemeKey))
+	if len(rs) == 0 {
+		return nil, errors.New("reward scheme missing")
+	}
+	current, err := LoadEpochByBlockNumber(epochDB, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []EpochDBEntry{
+		{[]byte(rewardSchemeKey), rs},
+		{[]byte(latestEpochKey), []byte(strconv.FormatUint(current.Number, 10))},
+	}
+	add := func(key []byte) {
+		if value := epochDB.Get(key); len(value) > 0 {
+			entries = append(entries, EpochDBEntry{key, value})
+		}
+	}
+	for n := uint64(0); n <= current.Number; n++ {
+		add(calcEpochKeyWithHeight(n))
+		add(calcEpochValidatorVoteKey(n))
+	}
+	// The next epoch is decided at the block before the end block
+	if blockNumber+1 >= current.EndBlock {
+		add(calcEpochKeyWithHeight(current.Number + 1))
+	}
+	add(calcEpochValidatorVoteKey(current.Number + 1))
+	return entries, nil
+}
+
+// LoadEpochByBlockNumber returns the saved epoch of the block, searching from the
+// latest epoch backwards.
+func LoadEpochByBlockNumber(epochDB db.DB, blockNumber uint64) (*Epoch, error) {
+	latest, err := strconv.ParseUint(string(epochDB.Get([]byte(latestEpochKey))), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("latest epoch missing: %v", err)
+	}
+	// The next epoch is saved once decided, before the latest epoch key moves to it
+	for n := latest + 2; n > 0; n-- {
+		ep := loadOneEpoch(epochDB, n-1, nil)
+		if ep != nil && ep.StartBlock <= blockNumber && blockNumber <= ep.EndBlock {
+			return ep, nil
+		}
+	}
+	return nil, errNoEpochOfBlock
+}
+
+// VerifyEpochDB checks the raw entries exported by ExportEpochDB against the trusted
+// header of the exported block: the latest epoch must hold the block, and its
+// validators must be the ones committing the block. It returns the latest epoch.
+func VerifyEpochDB(entries []EpochDBEntry, header *types.Header) (*Epoch, error) {
+	var latest []byte
+	for _, entry := range entries {
+		key := string(entry.Key)
+		switch {
+		case key == latestEpochKey:
+			latest = entry.Value
+		case key == rewardSchemeKey,
+			strings.HasPrefix(key, "Epoch:"),
+			strings.HasPrefix(key, "EpochValidatorVote_"):
+		default:
+			return nil, fmt.Errorf("%v: %q", errInvalidEpochEntry, key)
+		}
+	}
+	number, err := strconv.ParseUint(string(latest), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%v: latest epoch %q", errInvalidEpochEntry, latest)
+	}
+
+	var ep *Epoch
+	for _, entry := range entries {
+		if string(entry.Key) == string(calcEpochKeyWithHeight(number)) {
+			ep = FromBytes(entry.Value)
+		}
+	}
+	if ep == nil {
+		return nil, fmt.Errorf("%v: epoch %d missing", errInvalidEpochEntry, number)
+	}
+
+	tdmExtra, err := tmTypes.ExtractTendermintExtra(header)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", errUntrustedEpoch, err)
+	}
+	blockNumber := header.Number.Uint64()
+	if ep.Number != tdmExtra.EpochNumber || blockNumber < ep.StartBlock || blockNumber > ep.EndBlock {
+		return nil, fmt.Errorf("%v: epoch %d, block %d in epoch %d", errUntrustedEpoch, ep.Number, blockNumber, tdmExtra.EpochNumber)
+	}
+	if ep.Validators == nil || !bytes.Equal(ep.Validators.Hash(), tdmExtra.ValidatorsHash) {
+		return nil, fmt.Errorf("%v: validators hash mismatch", errUntrustedEpoch)
+	}
+	return ep, nil
+}
+
+// ImportEpochDB writes the raw entries exported by ExportEpochDB into the epoch db,
+// once verified against the trusted header of the exported block. It returns the
+// latest epoch imported.
+func ImportEpochDB(epochDB db.DB, entries []EpochDBEntry, header *types.Header) (*Epoch, error) {
+	ep, err := VerifyEpochDB(entries, header)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		epochDB.SetSync(entry.Key, entry.Value)
+	}
+	return ep, nil
+}
@@ -0,0 +1,65 @@
+package epoch
+
+import (
+	"fmt"
+
+	"github.com/intfoundation/go-db"
+	"github.com/intfoundation/go-wire"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/log"
+)
+
+// The helpers below restore the epoch db of a node synced by fast sync. The epochs
+// are recovered from the headers, the vote set of the pivot epoch is received from
+// the sync peer, since it is not committed to the chain.
+
+// LoadEpoch loads the epoch data only, without the reward scheme, vote set and the previous/next epochs
+func LoadEpoch(epochDB db.DB, epochNumber uint64, logger log.Logger) *Epoch {
+	return loadOneEpoch(epochDB, epochNumber, logger)
+}
+
+// StoreEpoch saves the epoch data to the db without changing the latest epoch
+func StoreEpoch(epochDB db.DB, ep *Epoch) {
+	epochDB.SetSync(calcEpochKeyWithHeight(ep.Number), ep.Bytes())
+}
+
+// EpochVoteSetBytes returns the raw vote set of the epoch, nil if the epoch has no vote
+func EpochVoteSetBytes(epochDB db.DB, epochNumber uint64) []byte {
+	voteRWMutex.RLock()
+	defer voteRWMutex.RUnlock()
+
+	return epochDB.Get(calcEpochValidatorVoteKey(epochNumber))
+}
+
+// DecodeEpochVoteSet decodes the raw vote set of an epoch, it returns nil for empty data
+func DecodeEpochVoteSet(data []byte) (*EpochValidatorVoteSet, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var voteSet EpochValidatorVoteSet
+	if err := wire.ReadBinaryBytes(data, &voteSet); err != nil {
+		return nil, err
+	}
+	voteSet.votesByAddress = make(map[common.Address]*EpochValidatorVote)
+	for _, v := range voteSet.Votes {
+		if _, exist := voteSet.votesByAddress[v.Address]; exist {
+			return nil, fmt.Errorf("duplicate vote of %x", v.Address)
+		}
+		voteSet.votesByAddress[v.Address] = v
+	}
+	return &voteSet, nil
+}
+
+// LoadSyncedEpoch makes the restored epoch the latest one and loads it in full,
+// it returns nil if the epoch is not in the db
+func LoadSyncedEpoch(epochDB db.DB, epochNumber uint64, logger log.Logger) *Epoch {
+	if loadOneEpoch(epochDB, epochNumber, logger) == nil {
+		return nil
+	}
+
+	ep := LoadOneEpoch(epochDB, epochNumber, logger)
+	ep.Status = EPOCH_SAVED
+	ep.Save()
+	return ep
+}
@@ -0,0 +1,362 @@
+package ipbft
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	goCrypto "github.com/intfoundation/go-crypto"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+	tdmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+)
+
+var (
+	// errFastSyncUnavailable is returned if the sync peer has no epoch to fast sync to
+	errFastSyncUnavailable = errors.New("no epoch to fast sync")
+	// errInvalidEpochSyncData is returned if the epoch data from the sync peer can not be decoded
+	errInvalidEpochSyncData = errors.New("invalid epoch sync data")
+	// errInvalidPivotEpoch is returned if the pivot block is not the one before the epoch claimed by the sync peer
+	errInvalidPivotEpoch = errors.New("pivot block not followed by the sync epoch")
+	// errInvalidSyncVoteSet is returned if the vote set from the sync peer does not match the synced chain
+	errInvalidSyncVoteSet = errors.New("vote set not matching the synced chain")
+)
+
+// fastSyncState tracks the epochs restored from the headers during fast sync.
+//
+// The headers are verified ahead of the block processing, so the epoch switch can not
+// be waited for. The validators of the next epoch are final at the block before the
+// epoch end block, which carries the next epoch and is signed by the current validators,
+// so the epochs are restored one by one from the local epoch.
+type fastSyncState struct {
+	active int32 // atomic, set between PrepareFastSync and FinishFastSync
+
+	mu         sync.Mutex
+	pivotEpoch uint64                       // the epoch starting right after the pivot block
+	voteSet    *epoch.EpochValidatorVoteSet // vote set of the pivot epoch received from the sync peer, not verified yet
+	epochs     map[uint64]*epoch.Epoch      // epochs restored from the headers, by number
+	last       *epoch.Epoch                 // the epoch of the latest verified header
+}
+
+func (sb *backend) isFastSync() bool {
+	return atomic.LoadInt32(&sb.fastSync.active) == 1
+}
+
+// EpochSyncData returns the current epoch and its vote set served to the fast sync peers
+func (sb *backend) EpochSyncData() ([]byte, []byte) {
+	ep := sb.GetEpoch()
+	if ep == nil {
+		return nil, nil
+	}
+	return ep.Bytes(), epoch.EpochVoteSetBytes(sb.core.epochDB, ep.Number)
+}
+
+// PrepareFastSync keeps the vote set of the current epoch of the sync peer, the vote set
+// is not committed to the chain, it is verified and saved once the chain is synced up to
+// the pivot block. The state is synced at the block before the epoch.
+func (sb *backend) PrepareFastSync(epochBytes []byte, voteSetBytes []byte) (uint64, error) {
+	ep := epoch.FromBytes(epochBytes)
+	if ep == nil || ep.Validators == nil {
+		return 0, errInvalidEpochSyncData
+	}
+	if ep.Number <= sb.GetEpoch().Number || ep.StartBlock <= 1 {
+		return 0, errFastSyncUnavailable
+	}
+	voteSet, err := epoch.DecodeEpochVoteSet(voteSetBytes)
+	if err != nil {
+		return 0, errInvalidEpochSyncData
+	}
+
+	fs := &sb.fastSync
+	fs.mu.Lock()
+	fs.pivotEpoch = ep.Number
+	fs.voteSet = voteSet
+	fs.epochs = make(map[uint64]*epoch.Epoch)
+	fs.last = nil
+	fs.mu.Unlock()
+	atomic.StoreInt32(&fs.active, 1)
+
+	sb.logger.Info("IPBFT prepare fast sync", "epoch", ep.Number, "pivot", ep.StartBlock-1)
+	return ep.StartBlock - 1, nil
+}
+
+// FastSyncCommitHead saves the verified vote set and switches the engine to the epoch
+// starting after the pivot block
+func (sb *backend) FastSyncCommitHead(chain consensus.ChainReader, header *types.Header) error {
+	if !sb.isFastSync() {
+		return nil
+	}
+
+	fs := &sb.fastSync
+	fs.mu.Lock()
+	ep := fs.epochs[fs.pivotEpoch]
+	prev := fs.epochs[fs.pivotEpoch-1]
+	voteSet := fs.voteSet
+	fs.mu.Unlock()
+	if ep == nil || ep.StartBlock != header.Number.Uint64()+1 {
+		return errInvalidPivotEpoch
+	}
+	if local := sb.GetEpoch(); prev == nil && local.Number == ep.Number-1 {
+		prev = local
+	}
+	if prev == nil {
+		return errInvalidPivotEpoch
+	}
+	if voteSet != nil {
+		sr, ok := chain.(stateReader)
+		if !ok {
+			return errInvalidSyncVoteSet
+		}
+		statedb, err := sr.StateAt(header.Root)
+		if err != nil {
+			return err
+		}
+		if err := verifySyncVoteSet(chain, statedb, prev.StartBlock, header.Number.Uint64(), voteSet); err != nil {
+			return err
+		}
+		epoch.SaveEpochVoteSet(sb.core.epochDB, ep.Number, voteSet)
+	}
+
+	current := epoch.LoadSyncedEpoch(sb.core.epochDB, ep.Number, sb.logger)
+	if current == nil {
+		return errInvalidPivotEpoch
+	}
+	sb.SetEpoch(current)
+
+	sb.logger.Info("IPBFT fast sync switched epoch", "epoch", current.Number, "pivot", header.Number)
+	return nil
+}
+
+// FinishFastSync drops the restored epochs, the following epochs are switched by the block processing
+func (sb *backend) FinishFastSync() {
+	fs := &sb.fastSync
+	atomic.StoreInt32(&fs.active, 0)
+
+	fs.mu.Lock()
+	fs.voteSet = nil
+	fs.epochs = nil
+	fs.last = nil
+	fs.mu.Unlock()
+}
+
+// stateReader reads the state synced at the pivot block
+type stateReader interface {
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// syncVoteTx is a transaction of the synced blocks which may update the next epoch votes
+type syncVoteTx struct {
+	order     int                 // position of the tx in the synced blocks, starting from 1
+	function  intAbi.FunctionType // Unknown for the contract calls, which may delegate through the staking precompiled contract
+	candidate common.Address
+}
+
+// decodeSyncVoteTx decodes the staking transactions to the chain contract and the contract
+// calls, it returns nil for the other transactions.
+func decodeSyncVoteTx(tx *types.Transaction, statedb *state.StateDB) *syncVoteTx {
+	if tx.To() == nil || !intAbi.IsIntChainContractAddr(tx.To()) {
+		if tx.To() != nil && len(tx.Data()) == 0 && statedb.GetCodeSize(*tx.To()) == 0 {
+			return nil
+		}
+		return &syncVoteTx{function: intAbi.Unknown}
+	}
+
+	data := tx.Data()
+	if len(data) < 4 {
+		return nil
+	}
+	function, err := intAbi.FunctionTypeFromId(data[:4])
+	if err != nil {
+		return nil
+	}
+
+	vt := &syncVoteTx{function: function}
+	switch function {
+	case intAbi.Register, intAbi.UnRegister:
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil
+		}
+		vt.candidate = from
+	case intAbi.Delegate:
+		var args intAbi.DelegateArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.Delegate.String(), data[4:]); err != nil {
+			return nil
+		}
+		vt.candidate = args.Candidate
+	case intAbi.UnDelegate:
+		var args intAbi.UnDelegateArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.UnDelegate.String(), data[4:]); err != nil {
+			return nil
+		}
+		vt.candidate = args.Candidate
+	default:
+		// VoteNextEpoch and RevealVote don't update the votes any more, the votes are
+		// updated by the staking transactions with the net proxied amount
+		return nil
+	}
+	return vt
+}
+
+// verifySyncVoteSet checks the vote set of the pivot epoch received from the sync peer
+// against the vote set rebuilt from the transactions of the previous epoch, which are
+// the blocks from the start of the previous epoch to the pivot block.
+//
+// Every vote must be set by a staking transaction of its candidate, or by a contract
+// call through the staking precompiled contract, with the pubkey registered and the
+// net proxied amount of the candidate. A later Register or Delegate of the candidate
+// always updates its vote, so the vote must not be older than them. The amount is
+// checked against the synced state once no later transaction could have changed it.
+func verifySyncVoteSet(chain consensus.ChainReader, statedb *state.StateDB, start, pivot uint64, voteSet *epoch.EpochValidatorVoteSet) error {
+	var (
+		txs       = make(map[common.Hash]*syncVoteTx)
+		lastRaise = make(map[common.Address]int) // last Register or Delegate of the candidate
+		lastTouch = make(map[common.Address]int) // last staking transaction of the candidate
+		lastCall  int                            // last contract call
+		order     int
+	)
+	for number := start; number <= pivot; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			return errInvalidSyncVoteSet
+		}
+		for _, tx := range block.Transactions() {
+			order++
+			vt := decodeSyncVoteTx(tx, statedb)
+			if vt == nil {
+				continue
+			}
+			vt.order = order
+			txs[tx.Hash()] = vt
+
+			switch vt.function {
+			case intAbi.Unknown:
+				lastCall = order
+			case intAbi.Register, intAbi.Delegate:
+				lastRaise[vt.candidate] = order
+				fallthrough
+			default:
+				lastTouch[vt.candidate] = order
+			}
+		}
+	}
+
+	for _, v := range voteSet.Votes {
+		vt, ok := txs[v.TxHash]
+		if !ok || v.PubKey == nil || v.Amount == nil || v.Amount.Sign() < 0 {
+			return errInvalidSyncVoteSet
+		}
+		// the votes are updated with the fixed salt and without vote hash
+		if v.VoteHash != (common.Hash{}) || v.Salt != "intchain" {
+			return errInvalidSyncVoteSet
+		}
+
+		switch vt.function {
+		case intAbi.Unknown:
+			// the candidate delegated through the staking precompiled contract is not known from the tx
+		case intAbi.Register, intAbi.Delegate, intAbi.UnDelegate:
+			if vt.candidate != v.Address {
+				return errInvalidSyncVoteSet
+			}
+		default:
+			return errInvalidSyncVoteSet
+		}
+
+		// the update of a later Register or Delegate is omitted
+		if lastRaise[v.Address] > vt.order {
+			return errInvalidSyncVoteSet
+		}
+
+		if pubkey := common.FromHex(statedb.GetPubkey(v.Address)); len(pubkey) == 128 {
+			var blsPK goCrypto.BLSPubKey
+			copy(blsPK[:], pubkey)
+			if !v.PubKey.Equals(blsPK) {
+				return errInvalidSyncVoteSet
+			}
+		}
+
+		if lastTouch[v.Address] <= vt.order && lastCall <= vt.order {
+			netProxied := new(big.Int).Add(statedb.GetTotalProxiedBalance(v.Address), statedb.GetTotalDepositProxiedBalance(v.Address))
+			netProxied.Sub(netProxied, statedb.GetTotalPendingRefundBalance(v.Address))
+			if v.Amount.Cmp(netProxied) != 0 {
+				return errInvalidSyncVoteSet
+			}
+		}
+	}
+	return nil
+}
+
+// syncedEpochByBlockNumber returns the epoch of the block from the local epochs and the restored ones
+func (sb *backend) syncedEpochByBlockNumber(number uint64) *epoch.Epoch {
+	local := sb.GetEpoch()
+	if number <= local.EndBlock {
+		return local.GetEpochByBlockNumber(number)
+	}
+
+	fs := &sb.fastSync
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.last != nil && number >= fs.last.StartBlock && number <= fs.last.EndBlock {
+		return fs.last
+	}
+	for n := local.Number + 1; ; n++ {
+		ep, ok := fs.epochs[n]
+		if !ok || number < ep.StartBlock {
+			return nil
+		}
+		if number <= ep.EndBlock {
+			fs.last = ep
+			return ep
+		}
+	}
+}
+
+// restoreEpoch saves the epoch carried by the verified header.
+//
+// The block before the end block of the epoch carries the next epoch with its final
+// validators. The start block of an epoch carries the epoch start time, which is also
+// the end time of the previous epoch. The epochs up to the pivot epoch are saved to the
+// epoch db, the later ones are saved by the block processing.
+func (sb *backend) restoreEpoch(header *types.Header, tdmExtra *tdmTypes.TendermintExtra, ep *epoch.Epoch) {
+	if len(tdmExtra.EpochBytes) == 0 {
+		return
+	}
+	epochInBlock := epoch.FromBytes(tdmExtra.EpochBytes)
+	if epochInBlock == nil {
+		return
+	}
+
+	fs := &sb.fastSync
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.epochs == nil {
+		return
+	}
+
+	db := sb.core.epochDB
+	number := header.Number.Uint64()
+	if epochInBlock.Number == ep.Number+1 && number == ep.EndBlock-1 && epochInBlock.StartBlock == ep.EndBlock+1 {
+		epochInBlock.Status = epoch.EPOCH_SAVED
+		fs.epochs[epochInBlock.Number] = epochInBlock
+		if epochInBlock.Number <= fs.pivotEpoch {
+			epoch.StoreEpoch(db, epochInBlock)
+		}
+	} else if epochInBlock.Number == ep.Number && number == ep.StartBlock {
+		restored, ok := fs.epochs[ep.Number]
+		if !ok || ep.Number > fs.pivotEpoch {
+			return
+		}
+		restored.StartTime = epochInBlock.StartTime
+		epoch.StoreEpoch(db, restored)
+		if prev, ok := fs.epochs[ep.Number-1]; ok {
+			prev.EndTime = epochInBlock.StartTime
+		}
+		epoch.UpdateEpochEndTime(db, ep.Number-1, epochInBlock.StartTime)
+	}
+}
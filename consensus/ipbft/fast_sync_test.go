@@ -0,0 +1,146 @@
+package ipbft
+
+import (
+	"math/big"
+	"testing"
+
+	goCrypto "github.com/intfoundation/go-crypto"
+	"github.com/intfoundation/go-wire"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	intCrypto "github.com/intfoundation/intchain/crypto"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+)
+
+// syncTestChain serves the blocks of the synced chain by number
+type syncTestChain struct {
+	consensus.ChainReader
+	blocks map[uint64]*types.Block
+}
+
+func (c *syncTestChain) GetBlockByNumber(number uint64) *types.Block {
+	return c.blocks[number]
+}
+
+func newSyncTestChain(txs map[uint64][]*types.Transaction, head uint64) *syncTestChain {
+	c := &syncTestChain{blocks: make(map[uint64]*types.Block)}
+	for n := uint64(0); n <= head; n++ {
+		c.blocks[n] = types.NewBlock(&types.Header{Number: new(big.Int).SetUint64(n)}, txs[n], nil, nil)
+	}
+	return c
+}
+
+func syncTestVoteSet(t *testing.T, votes ...*epoch.EpochValidatorVote) *epoch.EpochValidatorVoteSet {
+	voteSet := epoch.NewEpochValidatorVoteSet()
+	voteSet.Votes = votes
+	decoded, err := epoch.DecodeEpochVoteSet(wire.BinaryBytes(*voteSet))
+	if err != nil {
+		t.Fatalf("failed to decode vote set: %v", err)
+	}
+	return decoded
+}
+
+func syncTestPubKey(b byte) goCrypto.BLSPubKey {
+	var pubkey goCrypto.BLSPubKey
+	for i := range pubkey {
+		pubkey[i] = b
+	}
+	return pubkey
+}
+
+func syncTestStakingTx(t *testing.T, nonce uint64, function intAbi.FunctionType, args ...interface{}) *types.Transaction {
+	input, err := intAbi.ChainABI.Pack(function.String(), args...)
+	if err != nil {
+		t.Fatalf("failed to pack %v: %v", function, err)
+	}
+	return types.NewTransaction(nonce, intAbi.ChainContractMagicAddr, big.NewInt(0), 0, big.NewInt(1), input)
+}
+
+func TestVerifySyncVoteSet(t *testing.T) {
+	key, _ := intCrypto.GenerateKey()
+	var (
+		candidate = intCrypto.PubkeyToAddress(key.PublicKey)
+		other     = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		third     = common.HexToAddress("0x1000000000000000000000000000000000000003")
+		contract  = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		delegator = common.HexToAddress("0x3000000000000000000000000000000000000001")
+
+		candidateKey = syncTestPubKey(1)
+		otherKey     = syncTestPubKey(2)
+		thirdKey     = syncTestPubKey(3)
+	)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.ApplyForCandidate(candidate, candidateKey.KeyString(), 10)
+	statedb.AddProxiedBalanceByUser(candidate, candidate, big.NewInt(1000))
+	statedb.ApplyForCandidate(other, otherKey.KeyString(), 10)
+	statedb.AddProxiedBalanceByUser(other, delegator, big.NewInt(400))
+	statedb.ApplyForCandidate(third, thirdKey.KeyString(), 10)
+	statedb.AddProxiedBalanceByUser(third, contract, big.NewInt(300))
+
+	registerTx, err := types.SignTx(syncTestStakingTx(t, 0, intAbi.Register, candidateKey[:], []byte{1}, uint8(10)), types.LatestSignerForChainID(big.NewInt(1)), key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	var (
+		delegateTx   = syncTestStakingTx(t, 1, intAbi.Delegate, other)
+		callTx       = types.NewTransaction(2, contract, big.NewInt(300), 0, big.NewInt(1), []byte{1})
+		plainTx      = types.NewTransaction(3, delegator, big.NewInt(1), 0, big.NewInt(1), nil)
+		undelegateTx = syncTestStakingTx(t, 4, intAbi.UnDelegate, other, big.NewInt(100))
+		lateTx       = syncTestStakingTx(t, 5, intAbi.Delegate, third)
+	)
+	chain := newSyncTestChain(map[uint64][]*types.Transaction{
+		3:  {callTx},
+		4:  {registerTx},
+		6:  {delegateTx, plainTx},
+		9:  {undelegateTx},
+		12: {lateTx},
+	}, 12)
+
+	var (
+		candidateVote = &epoch.EpochValidatorVote{Address: candidate, PubKey: candidateKey, Amount: big.NewInt(1000), Salt: "intchain", TxHash: registerTx.Hash()}
+		otherVote     = &epoch.EpochValidatorVote{Address: other, PubKey: otherKey, Amount: big.NewInt(400), Salt: "intchain", TxHash: delegateTx.Hash()}
+		thirdVote     = &epoch.EpochValidatorVote{Address: third, PubKey: thirdKey, Amount: big.NewInt(300), Salt: "intchain", TxHash: callTx.Hash()}
+	)
+	tests := []struct {
+		name  string
+		votes []*epoch.EpochValidatorVote
+		ok    bool
+	}{
+		{"whole vote set", []*epoch.EpochValidatorVote{candidateVote, otherVote, thirdVote}, true},
+		{"vote not updated by later undelegate", []*epoch.EpochValidatorVote{{Address: other, PubKey: otherKey, Amount: big.NewInt(500), Salt: "intchain", TxHash: delegateTx.Hash()}}, true},
+		{"vote updated by undelegate", []*epoch.EpochValidatorVote{{Address: other, PubKey: otherKey, Amount: big.NewInt(400), Salt: "intchain", TxHash: undelegateTx.Hash()}}, true},
+		{"wrong undelegate amount", []*epoch.EpochValidatorVote{{Address: other, PubKey: otherKey, Amount: big.NewInt(500), Salt: "intchain", TxHash: undelegateTx.Hash()}}, false},
+		{"candidate not the sender", []*epoch.EpochValidatorVote{{Address: other, PubKey: otherKey, Amount: big.NewInt(400), Salt: "intchain", TxHash: registerTx.Hash()}}, false},
+		{"candidate not delegated", []*epoch.EpochValidatorVote{{Address: third, PubKey: thirdKey, Amount: big.NewInt(300), Salt: "intchain", TxHash: delegateTx.Hash()}}, false},
+		{"wrong pubkey", []*epoch.EpochValidatorVote{{Address: candidate, PubKey: otherKey, Amount: big.NewInt(1000), Salt: "intchain", TxHash: registerTx.Hash()}}, false},
+		{"wrong amount", []*epoch.EpochValidatorVote{{Address: candidate, PubKey: candidateKey, Amount: big.NewInt(999), Salt: "intchain", TxHash: registerTx.Hash()}}, false},
+		{"wrong salt", []*epoch.EpochValidatorVote{{Address: candidate, PubKey: candidateKey, Amount: big.NewInt(1000), Salt: "salt", TxHash: registerTx.Hash()}}, false},
+		{"vote hash", []*epoch.EpochValidatorVote{{Address: candidate, PubKey: candidateKey, Amount: big.NewInt(1000), Salt: "intchain", VoteHash: common.HexToHash("0x01"), TxHash: registerTx.Hash()}}, false},
+		{"unknown tx", []*epoch.EpochValidatorVote{{Address: other, PubKey: otherKey, Amount: big.NewInt(400), Salt: "intchain", TxHash: common.HexToHash("0x01")}}, false},
+		{"plain transfer", []*epoch.EpochValidatorVote{{Address: other, PubKey: otherKey, Amount: big.NewInt(400), Salt: "intchain", TxHash: plainTx.Hash()}}, false},
+		{"tx after pivot", []*epoch.EpochValidatorVote{{Address: third, PubKey: thirdKey, Amount: big.NewInt(300), Salt: "intchain", TxHash: lateTx.Hash()}}, false},
+	}
+	for _, tt := range tests {
+		err := verifySyncVoteSet(chain, statedb, 3, 10, syncTestVoteSet(t, tt.votes...))
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.ok && err != errInvalidSyncVoteSet {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, errInvalidSyncVoteSet)
+		}
+	}
+}
+
+func TestDecodeEpochVoteSetDuplicate(t *testing.T) {
+	vote := &epoch.EpochValidatorVote{Address: common.HexToAddress("0x01"), Amount: big.NewInt(1)}
+	voteSet := epoch.NewEpochValidatorVoteSet()
+	voteSet.Votes = []*epoch.EpochValidatorVote{vote, vote}
+	if _, err := epoch.DecodeEpochVoteSet(wire.BinaryBytes(*voteSet)); err == nil {
+		t.Fatal("duplicate votes accepted")
+	}
+}
@@ -44,8 +44,8 @@ func (sb *backend) Protocol() consensus.Protocol {
 
 	return consensus.Protocol{
 		Name:     protocolName,
-		Versions: []uint{64},
-		Lengths:  []uint64{64},
+		Versions: []uint{consensus.Int66, consensus.Int65, consensus.Int64},
+		Lengths:  []uint64{64, 64, 64},
 	}
 }
 
@@ -0,0 +1,43 @@
+package ipbft
+
+import (
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus/ipbft/consensus"
+	"github.com/intfoundation/intchain/core/types"
+)
+
+// NextProposers returns the validators proposing the block on top of the given header.
+//
+// The round 0 proposer is picked by VRF from the header, the proposers of the later
+// rounds follow it in the validator set. The round 0 proposer is skipped if it also
+// proposed the header without signing the commit of its parent, so returning several
+// rounds covers that case as well.
+func (sb *backend) NextProposers(header *types.Header, rounds int) []common.Address {
+	ep := sb.GetEpoch()
+	if ep == nil {
+		return nil
+	}
+	number := header.Number.Uint64() + 1
+	if number > ep.EndBlock {
+		ep = ep.GetNextEpoch()
+	} else {
+		ep = ep.GetEpochByBlockNumber(number)
+	}
+	if ep == nil || ep.Validators == nil || ep.Validators.Size() == 0 {
+		return nil
+	}
+	validators := ep.Validators.Validators
+
+	idx := consensus.ProposerByVRF(header.HashWithoutTime(), validators)
+	if idx < 0 {
+		return nil
+	}
+	if rounds > len(validators) {
+		rounds = len(validators)
+	}
+	proposers := make([]common.Address, 0, rounds)
+	for round := 0; round < rounds; round++ {
+		proposers = append(proposers, common.BytesToAddress(validators[(idx+round)%len(validators)].Address))
+	}
+	return proposers
+}
@@ -0,0 +1,46 @@
+package ipbft
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/go-crypto"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus/ipbft/consensus"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+	tmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core/types"
+)
+
+// Tests that the proposers of the next block follow the VRF proposer of the round 0
+// in the validator set, so that the private transactions reach the proposers only.
+func TestNextProposers(t *testing.T) {
+	vals := make([]*tmTypes.Validator, 4)
+	for i := range vals {
+		vals[i] = tmTypes.NewValidator(common.BytesToAddress([]byte{byte(i + 1)}).Bytes(), crypto.GenPrivKeyEd25519().PubKey(), big.NewInt(100))
+	}
+	ep := &epoch.Epoch{Number: 1, StartBlock: 1, EndBlock: 100, Validators: tmTypes.NewValidatorSet(vals)}
+	sb := &backend{core: &Node{consensusState: &consensus.ConsensusState{Epoch: ep}}}
+
+	header := &types.Header{Number: big.NewInt(10)}
+	validators := ep.Validators.Validators
+	idx := consensus.ProposerByVRF(header.HashWithoutTime(), validators)
+	if idx < 0 {
+		t.Fatalf("no proposer picked")
+	}
+
+	proposers := sb.NextProposers(header, 3)
+	if len(proposers) != 3 {
+		t.Fatalf("proposer count mismatch: have %d, want %d", len(proposers), 3)
+	}
+	for round, proposer := range proposers {
+		want := common.BytesToAddress(validators[(idx+round)%len(validators)].Address)
+		if proposer != want {
+			t.Errorf("round %d proposer mismatch: have %x, want %x", round, proposer, want)
+		}
+	}
+	// The rounds are capped by the validator set
+	if proposers := sb.NextProposers(header, 10); len(proposers) != len(validators) {
+		t.Errorf("capped proposer count mismatch: have %d, want %d", len(proposers), len(validators))
+	}
+}
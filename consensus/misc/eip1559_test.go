@@ -0,0 +1,101 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/params"
+)
+
+// config returns a chain config with the London fork activated at block 5.
+func config() *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.LondonBlock = big.NewInt(5)
+	return &config
+}
+
+// TestVerifyEip1559Header tests the base fee verification across the fork boundary.
+func TestVerifyEip1559Header(t *testing.T) {
+	initial := new(big.Int).SetUint64(params.InitialBaseFee)
+
+	for i, tc := range []struct {
+		pNum    int64
+		pFee    *big.Int
+		pUsed   uint64
+		hFee    *big.Int
+		wantErr bool
+	}{
+		// First London block takes the initial base fee
+		{4, nil, 10000000, initial, false},
+		{4, nil, 10000000, big.NewInt(1), true},
+		{4, nil, 10000000, nil, true},
+		// Parent at the gas target keeps the base fee
+		{5, initial, 10000000, initial, false},
+		// Parent above the target raises the base fee
+		{5, initial, 20000000, big.NewInt(1125000000), false},
+		{5, initial, 20000000, initial, true},
+	} {
+		parent := &types.Header{
+			Number:   big.NewInt(tc.pNum),
+			GasLimit: 20000000,
+			GasUsed:  tc.pUsed,
+			BaseFee:  tc.pFee,
+		}
+		header := &types.Header{
+			Number:   big.NewInt(tc.pNum + 1),
+			GasLimit: 20000000,
+			BaseFee:  tc.hFee,
+		}
+		err := VerifyEip1559Header(config(), parent, header)
+		if tc.wantErr && err == nil {
+			t.Errorf("test %d: expected error", i)
+		}
+		if !tc.wantErr && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+// TestCalcBaseFee assumes all blocks are 1559-blocks
+func TestCalcBaseFee(t *testing.T) {
+	tests := []struct {
+		parentBaseFee   int64
+		parentGasLimit  uint64
+		parentGasUsed   uint64
+		expectedBaseFee int64
+	}{
+		{params.InitialBaseFee, 20000000, 10000000, params.InitialBaseFee}, // usage == target
+		{params.InitialBaseFee, 20000000, 9000000, 987500000},              // usage below target
+		{params.InitialBaseFee, 20000000, 11000000, 1012500000},            // usage above target
+		{params.InitialBaseFee, 20000000, 0, 875000000},                    // empty block
+		{params.InitialBaseFee, 1, 1, params.InitialBaseFee},               // zero gas target
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   big.NewInt(32),
+			GasLimit: test.parentGasLimit,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(test.parentBaseFee),
+		}
+		if have, want := CalcBaseFee(config(), parent), big.NewInt(test.expectedBaseFee); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d  want %d, ", i, have, want)
+		}
+	}
+}
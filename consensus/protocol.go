@@ -27,6 +27,10 @@ import (
 const (
 	Eth62 = 62
 	Eth63 = 63
+
+	Int64 = 64
+	Int65 = 65 // adds the epoch sync data for the fast sync of IPBFT chains
+	Int66 = 66 // adds the private transactions relayed to the proposers
 )
 
 var (
@@ -0,0 +1,177 @@
+// Package bridge implements the storage level access to the token bridge
+// contracts used by the MintTokenInChildChain, BurnTokenInChildChain and
+// UnlockTokenInMainChain special transactions.
+//
+// Tokens are locked in the MainChainBridge contract on the main chain and
+// minted in the ChildChainToken contract on the child chain. Burned tokens are
+// credited back to the MainChainBridge contract, where the user claims them.
+package bridge
+
+//go:generate abigen --abi contract/MainChainBridge.abi --pkg contract --type MainChainBridge --out contract/mainchainbridge.go
+//go:generate abigen --abi contract/ChildChainToken.abi --pkg contract --type ChildChainToken --out contract/childchaintoken.go
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/intfoundation/intchain/accounts/abi"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/contracts/bridge/contract"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/crypto"
+)
+
+// Storage slots of the state variables, see contract/*.sol
+var (
+	tokenBalanceSlot     = common.BigToHash(big.NewInt(0))
+	tokenTotalSupplySlot = common.BigToHash(big.NewInt(1))
+
+	bridgeWithdrawableSlot = common.BigToHash(big.NewInt(1))
+	bridgeChildTokensSlot  = common.BigToHash(big.NewInt(2))
+	bridgeMainTokensSlot   = common.BigToHash(big.NewInt(3))
+
+	// tokenMintedSlot is not a state variable of the token contract, it keeps the
+	// minted lock logs away from the slots the contract uses
+	tokenMintedSlot = crypto.Keccak256Hash([]byte("intchain.bridge.minted"))
+)
+
+var (
+	ErrInsufficientTokenBalance = errors.New("insufficient token balance")
+	ErrInvalidLockLog           = errors.New("invalid token locked log")
+)
+
+var (
+	bridgeABI abi.ABI
+	tokenABI  abi.ABI
+
+	// TokenLockedTopic is the topic of the MainChainBridge TokenLocked event
+	TokenLockedTopic common.Hash
+	// TransferTopic is the topic of the ChildChainToken Transfer event
+	TransferTopic common.Hash
+)
+
+func init() {
+	var err error
+	bridgeABI, err = abi.JSON(strings.NewReader(contract.MainChainBridgeABI))
+	if err != nil {
+		panic("fail to create the bridge ABI: " + err.Error())
+	}
+	tokenABI, err = abi.JSON(strings.NewReader(contract.ChildChainTokenABI))
+	if err != nil {
+		panic("fail to create the token ABI: " + err.Error())
+	}
+	TokenLockedTopic = bridgeABI.Events["TokenLocked"].ID()
+	TransferTopic = tokenABI.Events["Transfer"].ID()
+}
+
+// TokenLocked is the decoded TokenLocked event emitted by the MainChainBridge contract.
+type TokenLocked struct {
+	Token      common.Address
+	From       common.Address
+	ChildToken common.Address
+	ChainId    string
+	Amount     *big.Int
+}
+
+// ParseTokenLocked decodes the TokenLocked event from the log of the given bridge contract.
+func ParseTokenLocked(bridge common.Address, log *types.Log) (*TokenLocked, error) {
+	if log.Address != bridge || len(log.Topics) != 3 || log.Topics[0] != TokenLockedTopic {
+		return nil, ErrInvalidLockLog
+	}
+
+	var event TokenLocked
+	if err := bridgeABI.Unpack(&event, "TokenLocked", log.Data); err != nil {
+		return nil, err
+	}
+	event.Token = common.BytesToAddress(log.Topics[1].Bytes())
+	event.From = common.BytesToAddress(log.Topics[2].Bytes())
+
+	return &event, nil
+}
+
+// mappingSlot calculates the storage slot of mapping[key] as solidity does.
+func mappingSlot(key []byte, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(key, 32), slot.Bytes())
+}
+
+func chainKey(chainId string) []byte {
+	return crypto.Keccak256([]byte(chainId))
+}
+
+// TokenBalance returns the child chain token balance of the holder.
+func TokenBalance(statedb *state.StateDB, token, holder common.Address) *big.Int {
+	return statedb.GetState(token, mappingSlot(holder.Bytes(), tokenBalanceSlot)).Big()
+}
+
+// MintToken mints the amount of child chain token to the recipient.
+func MintToken(statedb *state.StateDB, token, to common.Address, amount *big.Int) {
+	balanceKey := mappingSlot(to.Bytes(), tokenBalanceSlot)
+	balance := new(big.Int).Add(statedb.GetState(token, balanceKey).Big(), amount)
+	statedb.SetState(token, balanceKey, common.BigToHash(balance))
+
+	supply := new(big.Int).Add(statedb.GetState(token, tokenTotalSupplySlot).Big(), amount)
+	statedb.SetState(token, tokenTotalSupplySlot, common.BigToHash(supply))
+
+	addTransferLog(statedb, token, common.Address{}, to, amount)
+}
+
+// mintedSlot calculates the storage slot marking the log of the lock tx as minted.
+func mintedSlot(txHash common.Hash, logIndex uint) common.Hash {
+	return mappingSlot(new(big.Int).SetUint64(uint64(logIndex)).Bytes(), mappingSlot(txHash.Bytes(), tokenMintedSlot))
+}
+
+// IsLockMinted reports whether the TokenLocked log of the lock tx has been minted,
+// the log index is the index of the log in the receipt of the lock tx.
+func IsLockMinted(statedb *state.StateDB, token common.Address, txHash common.Hash, logIndex uint) bool {
+	return statedb.GetState(token, mintedSlot(txHash, logIndex)) != (common.Hash{})
+}
+
+// MarkLockMinted marks the TokenLocked log of the lock tx as minted.
+func MarkLockMinted(statedb *state.StateDB, token common.Address, txHash common.Hash, logIndex uint) {
+	statedb.SetState(token, mintedSlot(txHash, logIndex), common.BigToHash(common.Big1))
+}
+
+// BurnToken burns the amount of child chain token from the holder.
+func BurnToken(statedb *state.StateDB, token, from common.Address, amount *big.Int) error {
+	balanceKey := mappingSlot(from.Bytes(), tokenBalanceSlot)
+	balance := statedb.GetState(token, balanceKey).Big()
+	if balance.Cmp(amount) < 0 {
+		return ErrInsufficientTokenBalance
+	}
+	statedb.SetState(token, balanceKey, common.BigToHash(balance.Sub(balance, amount)))
+
+	supply := statedb.GetState(token, tokenTotalSupplySlot).Big()
+	statedb.SetState(token, tokenTotalSupplySlot, common.BigToHash(supply.Sub(supply, amount)))
+
+	addTransferLog(statedb, token, from, common.Address{}, amount)
+	return nil
+}
+
+func addTransferLog(statedb *state.StateDB, token, from, to common.Address, amount *big.Int) {
+	statedb.AddLog(&types.Log{
+		Address: token,
+		Topics:  []common.Hash{TransferTopic, from.Hash(), to.Hash()},
+		Data:    common.LeftPadBytes(amount.Bytes(), 32),
+	})
+}
+
+// MainToken returns the main chain token mapped to the child chain token, zero address if not mapped.
+func MainToken(statedb *state.StateDB, bridge common.Address, chainId string, childToken common.Address) common.Address {
+	slot := mappingSlot(childToken.Bytes(), crypto.Keccak256Hash(chainKey(chainId), bridgeMainTokensSlot.Bytes()))
+	return common.BytesToAddress(statedb.GetState(bridge, slot).Bytes())
+}
+
+// ChildToken returns the child chain token mapped to the main chain token, zero address if not mapped.
+func ChildToken(statedb *state.StateDB, bridge common.Address, chainId string, token common.Address) common.Address {
+	slot := mappingSlot(token.Bytes(), crypto.Keccak256Hash(chainKey(chainId), bridgeChildTokensSlot.Bytes()))
+	return common.BytesToAddress(statedb.GetState(bridge, slot).Bytes())
+}
+
+// CreditWithdrawable credits the amount of main chain token to the user, it can be claimed from the bridge contract.
+func CreditWithdrawable(statedb *state.StateDB, bridge, token, user common.Address, amount *big.Int) {
+	slot := mappingSlot(user.Bytes(), mappingSlot(token.Bytes(), bridgeWithdrawableSlot))
+	withdrawable := new(big.Int).Add(statedb.GetState(bridge, slot).Big(), amount)
+	statedb.SetState(bridge, slot, common.BigToHash(withdrawable))
+}
@@ -0,0 +1,89 @@
+package bridge
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+)
+
+var (
+	testToken  = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	testHolder = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+func newTestState() *state.StateDB {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	return statedb
+}
+
+func TestMintAndBurnToken(t *testing.T) {
+	statedb := newTestState()
+
+	MintToken(statedb, testToken, testHolder, big.NewInt(100))
+	if balance := TokenBalance(statedb, testToken, testHolder); balance.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("balance mismatch after mint: have %v, want %v", balance, 100)
+	}
+	if supply := statedb.GetState(testToken, tokenTotalSupplySlot).Big(); supply.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("total supply mismatch after mint: have %v, want %v", supply, 100)
+	}
+
+	if err := BurnToken(statedb, testToken, testHolder, big.NewInt(101)); err != ErrInsufficientTokenBalance {
+		t.Fatalf("burn more than balance: have %v, want %v", err, ErrInsufficientTokenBalance)
+	}
+	if err := BurnToken(statedb, testToken, testHolder, big.NewInt(40)); err != nil {
+		t.Fatalf("failed to burn: %v", err)
+	}
+	if balance := TokenBalance(statedb, testToken, testHolder); balance.Cmp(big.NewInt(60)) != 0 {
+		t.Fatalf("balance mismatch after burn: have %v, want %v", balance, 60)
+	}
+	if supply := statedb.GetState(testToken, tokenTotalSupplySlot).Big(); supply.Cmp(big.NewInt(60)) != 0 {
+		t.Fatalf("total supply mismatch after burn: have %v, want %v", supply, 60)
+	}
+}
+
+func TestLockMinted(t *testing.T) {
+	statedb := newTestState()
+	txHash := common.HexToHash("0x01")
+
+	MarkLockMinted(statedb, testToken, txHash, 1)
+	if !IsLockMinted(statedb, testToken, txHash, 1) {
+		t.Fatalf("minted lock log not marked")
+	}
+	if IsLockMinted(statedb, testToken, txHash, 0) || IsLockMinted(statedb, testToken, common.HexToHash("0x02"), 1) {
+		t.Fatalf("other lock log marked as minted")
+	}
+	if balance := TokenBalance(statedb, testToken, testHolder); balance.Sign() != 0 {
+		t.Fatalf("balance changed by the minted mark: %v", balance)
+	}
+}
+
+func TestParseTokenLocked(t *testing.T) {
+	bridge := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	child := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+
+	data, err := bridgeABI.Events["TokenLocked"].Inputs.NonIndexed().Pack(child, "child_0", big.NewInt(7))
+	if err != nil {
+		t.Fatalf("failed to pack event: %v", err)
+	}
+	log := &types.Log{
+		Address: bridge,
+		Topics:  []common.Hash{TokenLockedTopic, testToken.Hash(), testHolder.Hash()},
+		Data:    data,
+	}
+
+	locked, err := ParseTokenLocked(bridge, log)
+	if err != nil {
+		t.Fatalf("failed to parse event: %v", err)
+	}
+	if locked.Token != testToken || locked.From != testHolder || locked.ChildToken != child || locked.ChainId != "child_0" || locked.Amount.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("event mismatch: %+v", locked)
+	}
+
+	if _, err := ParseTokenLocked(child, log); err != ErrInvalidLockLog {
+		t.Fatalf("log from other contract: have %v, want %v", err, ErrInvalidLockLog)
+	}
+}
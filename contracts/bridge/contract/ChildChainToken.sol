@@ -0,0 +1,55 @@
+pragma solidity ^0.4.24;
+
+/// @title Child chain side of the INT Chain token bridge
+/// @notice Balances are minted by the MintTokenInChildChain special transaction
+/// and burned by the BurnTokenInChildChain special transaction, there is no
+/// mint or burn method on the contract itself.
+///
+/// The chain reads and writes the storage of this contract directly, so the
+/// order of the state variables must not be changed.
+contract ChildChainToken {
+    // Token balance per holder (slot 0)
+    mapping (address => uint256) public balanceOf;
+    // Total minted and not yet burned supply (slot 1)
+    uint256 public totalSupply;
+    mapping (address => mapping (address => uint256)) public allowance;
+
+    string public name;
+    string public symbol;
+    uint8 public decimals;
+
+    event Transfer(address indexed from, address indexed to, uint256 value);
+    event Approval(address indexed owner, address indexed spender, uint256 value);
+
+    constructor(string _name, string _symbol, uint8 _decimals) public {
+        name = _name;
+        symbol = _symbol;
+        decimals = _decimals;
+    }
+
+    function transfer(address to, uint256 value) public returns (bool) {
+        _transfer(msg.sender, to, value);
+        return true;
+    }
+
+    function transferFrom(address from, address to, uint256 value) public returns (bool) {
+        require(allowance[from][msg.sender] >= value);
+        allowance[from][msg.sender] -= value;
+        _transfer(from, to, value);
+        return true;
+    }
+
+    function approve(address spender, uint256 value) public returns (bool) {
+        allowance[msg.sender][spender] = value;
+        emit Approval(msg.sender, spender, value);
+        return true;
+    }
+
+    function _transfer(address from, address to, uint256 value) internal {
+        require(to != address(0));
+        require(balanceOf[from] >= value);
+        balanceOf[from] -= value;
+        balanceOf[to] += value;
+        emit Transfer(from, to, value);
+    }
+}
@@ -0,0 +1,7 @@
+pragma solidity ^0.4.24;
+
+/// @title Minimal ERC20 interface used by the bridge
+contract ERC20 {
+    function transfer(address to, uint256 value) public returns (bool);
+    function transferFrom(address from, address to, uint256 value) public returns (bool);
+}
@@ -0,0 +1,56 @@
+pragma solidity ^0.4.24;
+
+import "./ERC20.sol";
+
+/// @title Main chain side of the INT Chain token bridge
+/// @notice Tokens are locked here and minted on the child chain by the
+/// MintTokenInChildChain special transaction. Tokens burned on the child chain
+/// are credited back by the UnlockTokenInMainChain special transaction.
+///
+/// The chain reads and writes the storage of this contract directly, so the
+/// order of the state variables must not be changed.
+contract MainChainBridge {
+    // Owner which is allowed to map the tokens
+    address public owner;
+    // Claimable amount per main chain token and user (slot 1)
+    mapping (address => mapping (address => uint256)) public withdrawable;
+    // Child chain token per keccak256(chainId) and main chain token (slot 2)
+    mapping (bytes32 => mapping (address => address)) public childTokens;
+    // Main chain token per keccak256(chainId) and child chain token (slot 3)
+    mapping (bytes32 => mapping (address => address)) public mainTokens;
+
+    event TokenMapped(string chainId, address indexed token, address indexed childToken);
+    event TokenLocked(address indexed token, address indexed from, address childToken, string chainId, uint256 amount);
+    event TokenClaimed(address indexed token, address indexed to, uint256 amount);
+
+    constructor() public {
+        owner = msg.sender;
+    }
+
+    /// @notice Map a main chain token to its mintable counterpart on a child chain
+    function mapToken(string chainId, address token, address childToken) public {
+        require(msg.sender == owner);
+        bytes32 chain = keccak256(bytes(chainId));
+        childTokens[chain][token] = childToken;
+        mainTokens[chain][childToken] = token;
+        emit TokenMapped(chainId, token, childToken);
+    }
+
+    /// @notice Lock the tokens, the same address receives them on the child chain
+    function lock(string chainId, address token, uint256 amount) public {
+        address childToken = childTokens[keccak256(bytes(chainId))][token];
+        require(childToken != address(0));
+        require(amount > 0);
+        require(ERC20(token).transferFrom(msg.sender, this, amount));
+        emit TokenLocked(token, msg.sender, childToken, chainId, amount);
+    }
+
+    /// @notice Claim the tokens credited by UnlockTokenInMainChain
+    function claim(address token) public {
+        uint256 amount = withdrawable[token][msg.sender];
+        require(amount > 0);
+        withdrawable[token][msg.sender] = 0;
+        require(ERC20(token).transfer(msg.sender, amount));
+        emit TokenClaimed(token, msg.sender, amount);
+    }
+}
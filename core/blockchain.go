@@ -34,6 +34,7 @@ import (
 	"github.com/intfoundation/intchain/common/prque"
 	"github.com/intfoundation/intchain/consensus"
 	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/state/snapshot"
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/core/vm"
 	"github.com/intfoundation/intchain/crypto"
@@ -61,6 +62,11 @@ const (
 	badBlockLimit       = 10
 	triesInMemory       = 128
 
+	// minTxLookupLimit is the least number of recent blocks whose receipts and
+	// transaction indexes are retained, the child chains look up the transactions
+	// and receipts of the pending cross-chain operations on the main chain.
+	minTxLookupLimit = 90000
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	BlockChainVersion = 3
 )
@@ -73,6 +79,8 @@ type CacheConfig struct {
 	TrieDirtyLimit    int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieDirtyDisabled bool          // Whether to disable trie write caching and GC altogether (archive node)
 	TrieTimeLimit     time.Duration // Time limit after which to flush the current in-memory trie to disk
+	SnapshotLimit     int           // Memory allowance (MB) to use for caching snapshot entries in memory, 0 disables the snapshot
+	TxLookupLimit     uint64        // Number of recent blocks to retain the receipts and transaction indexes of, 0 retains the entire history
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -116,6 +124,7 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache    state.Database // State database to reuse between imports (contains state cache)
+	snaps         *snapshot.Tree // Snapshot tree for fast trie leaf access
 	bodyCache     *lru.Cache     // Cache for the most recent block bodies
 	bodyRLPCache  *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
 	receiptsCache *lru.Cache     // Cache for the most recent receipts per block
@@ -203,6 +212,18 @@ func NewBlockChain(db intdb.Database, cacheConfig *CacheConfig, chainConfig *par
 			}
 		}
 	}
+	// Load any existing snapshot, regenerating it if loading failed
+	if bc.cacheConfig.SnapshotLimit > 0 {
+		bc.snaps = snapshot.New(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.SnapshotLimit, bc.CurrentBlock().Root())
+	}
+	// Retain the receipts and transaction indexes of the configured history
+	if limit := bc.cacheConfig.TxLookupLimit; limit != 0 && limit < minTxLookupLimit {
+		bc.logger.Warn("Transaction history limit too low, raised", "provided", limit, "updated", minTxLookupLimit)
+		bc.cacheConfig.TxLookupLimit = minTxLookupLimit
+	}
+	bc.wg.Add(1)
+	go bc.maintainTxIndex()
+
 	// Take ownership of this particular state
 	go bc.update()
 	return bc, nil
@@ -283,7 +304,18 @@ func (bc *BlockChain) SetHead(head uint64) error {
 
 	// Rewind the header chain, deleting all block bodies until then
 	delFn := func(db intdb.Writer, hash common.Hash, num uint64) {
-		rawdb.DeleteBody(db, hash, num)
+		// The database without freezer reports no ancients
+		if frozen, _ := bc.db.Ancients(); num+1 <= frozen {
+			// Truncate all relative data(header, total difficulty, body, receipt
+			// and canonical hash) from ancient store.
+			if err := bc.db.TruncateAncients(num); err != nil {
+				bc.logger.Crit("Failed to truncate ancient data", "number", num, "err", err)
+			}
+			// Remove the hash <-> number mapping from the active store.
+			rawdb.DeleteHeaderNumber(db, hash)
+		} else {
+			rawdb.DeleteBody(db, hash, num)
+		}
 	}
 	bc.hc.SetHead(head, delFn)
 	currentHeader := bc.hc.CurrentHeader()
@@ -317,6 +349,12 @@ func (bc *BlockChain) SetHead(head uint64) error {
 		bc.currentFastBlock.Store(bc.genesisBlock)
 	}
 	currentBlock := bc.CurrentBlock()
+
+	// The snapshot layers above the new head are useless, rebuild the snapshot if
+	// the new head is deeper than the layers kept
+	if bc.snaps != nil && bc.snaps.Snapshot(currentBlock.Root()) == nil {
+		bc.snaps.Rebuild(currentBlock.Root())
+	}
 	currentFastBlock := bc.CurrentFastBlock()
 
 	rawdb.WriteHeadBlockHash(bc.db, currentBlock.Hash())
@@ -336,11 +374,22 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	if _, err := trie.NewSecure(block.Root(), bc.stateCache.TrieDB()); err != nil {
 		return err
 	}
+	// Let the consensus engine restore its own data at the new head
+	if fs, ok := bc.engine.(consensus.FastSyncer); ok {
+		if err := fs.FastSyncCommitHead(bc, block.Header()); err != nil {
+			return err
+		}
+	}
 	// If all checks out, manually set the head block
 	bc.chainmu.Lock()
 	bc.currentBlock.Store(block)
 	bc.chainmu.Unlock()
 
+	// Destroy any existing state snapshot and regenerate it in the background
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(block.Root())
+	}
+
 	bc.logger.Info("Committed new head block", "number", block.Number(), "hash", hash)
 	return nil
 }
@@ -379,7 +428,12 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.stateCache)
+	return state.NewWithSnapshot(root, bc.stateCache, bc.snaps)
+}
+
+// Snapshots returns the blockchain snapshot tree, nil if the snapshot is disabled.
+func (bc *BlockChain) Snapshots() *snapshot.Tree {
+	return bc.snaps
 }
 
 // StateCache returns the caching database underpinning the blockchain instance.
@@ -673,7 +727,7 @@ func (bc *BlockChain) ValidateBlock(block *types.Block) (*state.StateDB, types.R
 
 	var parent *types.Block
 	parent = bc.GetBlock(block.ParentHash(), block.NumberU64()-1)
-	state, err := state.New(parent.Root(), bc.stateCache)
+	state, err := state.NewWithSnapshot(parent.Root(), bc.stateCache, bc.snaps)
 	if err != nil {
 		log.Debugf("ValidateBlock-state.New return with error: %v", err)
 		return nil, nil, nil, err
@@ -715,6 +769,15 @@ func (bc *BlockChain) Stop() {
 
 	bc.wg.Wait()
 
+	// Journal the snapshot diff layers, the trie of the disk layer is persisted
+	// along if the snapshot is still being generated from it
+	var snapBase common.Hash
+	if bc.snaps != nil {
+		var err error
+		if snapBase, err = bc.snaps.Journal(bc.CurrentBlock().Root()); err != nil {
+			bc.logger.Error("Failed to journal state snapshot", "err", err)
+		}
+	}
 	// Ensure the state of a recent block is also stored to disk before exiting.
 	// We're writing three different states to catch different restart scenarios:
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
@@ -733,6 +796,14 @@ func (bc *BlockChain) Stop() {
 				}
 			}
 		}
+		if snapBase != (common.Hash{}) {
+			if _, generating := bc.snaps.DiskRoot(); generating {
+				bc.logger.Info("Writing snapshot state to disk", "root", snapBase)
+				if err := triedb.Commit(snapBase, true); err != nil {
+					bc.logger.Error("Failed to commit snapshot state trie", "err", err)
+				}
+			}
+		}
 		for !bc.triegc.Empty() {
 			triedb.Dereference(bc.triegc.PopItem().(common.Hash))
 		}
@@ -1261,7 +1332,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		if parent == nil {
 			parent = bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
 		}
-		statedb, err := state.New(parent.Root, bc.stateCache)
+		statedb, err := state.NewWithSnapshot(parent.Root, bc.stateCache, bc.snaps)
 		if err != nil {
 			return it.index, events, coalescedLogs, err
 		}
@@ -1628,6 +1699,71 @@ func (bc *BlockChain) update() {
 	}
 }
 
+// indexBlocks moves the tail of the retained receipts and transaction indexes
+// to the configured history limit below the head, reindexing the transactions
+// if the limit was raised. The done channel is closed once finished.
+func (bc *BlockChain) indexBlocks(tail *uint64, head uint64, done chan struct{}, interrupt chan struct{}) {
+	defer close(done)
+
+	limit := bc.cacheConfig.TxLookupLimit
+	switch {
+	case tail == nil && (limit == 0 || head < limit):
+		// The entire history is retained and should be
+		return
+	case tail == nil:
+		// The entire history is retained, drop the blocks below the limit
+		rawdb.UnindexTransactions(bc.db, 0, head-limit+1, interrupt)
+	case limit == 0 || head < limit:
+		// The limit was removed or is beyond the genesis, reindex everything
+		rawdb.IndexTransactions(bc.db, 0, *tail, interrupt)
+	case head-limit+1 < *tail:
+		// The limit was raised, reindex the blocks below the tail
+		rawdb.IndexTransactions(bc.db, head-limit+1, *tail, interrupt)
+	default:
+		// The head moved forward, drop the blocks beyond the limit
+		rawdb.UnindexTransactions(bc.db, *tail, head-limit+1, interrupt)
+	}
+}
+
+// maintainTxIndex is responsible for the retention of the receipts and the
+// transaction indexes, dropping the ones beyond the configured history limit as
+// the chain head progresses, and reindexing the transactions if the limit was
+// raised since the last run. The indexing runs in the background, only one at a
+// time.
+func (bc *BlockChain) maintainTxIndex() {
+	defer bc.wg.Done()
+
+	var (
+		done      chan struct{}
+		interrupt = make(chan struct{})
+		headCh    = make(chan ChainHeadEvent, 1)
+	)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	if head := bc.CurrentBlock(); head != nil {
+		done = make(chan struct{})
+		go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), head.NumberU64(), done, interrupt)
+	}
+	for {
+		select {
+		case head := <-headCh:
+			if done == nil {
+				done = make(chan struct{})
+				go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), head.Block.NumberU64(), done, interrupt)
+			}
+		case <-done:
+			done = nil
+		case <-bc.quit:
+			if done != nil {
+				close(interrupt)
+				<-done
+			}
+			return
+		}
+	}
+}
+
 // BadBlockArgs represents the entries in the list returned when bad blocks are queried.
 type BadBlockArgs struct {
 	Hash   common.Hash   `json:"hash"`
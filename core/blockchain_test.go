@@ -1019,6 +1019,8 @@ func TestCanonicalBlockRetrieval(t *testing.T) {
 	var pend sync.WaitGroup
 	pend.Add(len(chain))
 
+	// The goroutines report the failures back, t.Fatalf must be called from the test goroutine
+	errs := make(chan error, len(chain))
 	for i := range chain {
 		go func(block *types.Block) {
 			defer pend.Done()
@@ -1030,14 +1032,16 @@ func TestCanonicalBlockRetrieval(t *testing.T) {
 					continue // busy wait for canonical hash to be written
 				}
 				if ch != block.Hash() {
-					t.Fatalf("unknown canonical hash, want %s, got %s", block.Hash().Hex(), ch.Hex())
+					errs <- fmt.Errorf("unknown canonical hash, want %s, got %s", block.Hash().Hex(), ch.Hex())
+					return
 				}
 				fb := rawdb.ReadBlock(blockchain.db, ch, block.NumberU64())
 				if fb == nil {
-					t.Fatalf("unable to retrieve block %d for canonical hash: %s", block.NumberU64(), ch.Hex())
+					errs <- fmt.Errorf("unable to retrieve block %d for canonical hash: %s", block.NumberU64(), ch.Hex())
+					return
 				}
 				if fb.Hash() != block.Hash() {
-					t.Fatalf("invalid block hash for block %d, want %s, got %s", block.NumberU64(), block.Hash().Hex(), fb.Hash().Hex())
+					errs <- fmt.Errorf("invalid block hash for block %d, want %s, got %s", block.NumberU64(), block.Hash().Hex(), fb.Hash().Hex())
 				}
 				return
 			}
@@ -1048,6 +1052,11 @@ func TestCanonicalBlockRetrieval(t *testing.T) {
 		}
 	}
 	pend.Wait()
+
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
 }
 
 func TestEIP155Transition(t *testing.T) {
@@ -1227,7 +1236,7 @@ func TestEIP161AccountRemoval(t *testing.T) {
 // https://github.com/intfoundation/intchain/pull/15941
 func TestBlockchainHeaderchainReorgConsistency(t *testing.T) {
 	// Generate a canonical chain to act as the main dataset
-	engine := nil
+	var engine consensus.Engine
 
 	db := rawdb.NewMemoryDatabase()
 	genesis := new(Genesis).MustCommit(db)
@@ -1272,7 +1281,7 @@ func TestBlockchainHeaderchainReorgConsistency(t *testing.T) {
 // cache (which would eventually cause memory issues).
 func TestTrieForkGC(t *testing.T) {
 	// Generate a canonical chain to act as the main dataset
-	engine := nil
+	var engine consensus.Engine
 
 	db := rawdb.NewMemoryDatabase()
 	genesis := new(Genesis).MustCommit(db)
@@ -1318,7 +1327,7 @@ func TestTrieForkGC(t *testing.T) {
 // forking point is not available any more.
 func TestLargeReorgTrieGC(t *testing.T) {
 	// Generate the original common chain segment and the two competing forks
-	engine := nil
+	var engine consensus.Engine
 
 	db := rawdb.NewMemoryDatabase()
 	genesis := new(Genesis).MustCommit(db)
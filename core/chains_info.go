@@ -69,6 +69,8 @@ const (
 	chainInfoKey  = "CHAIN"
 	ethGenesisKey = "ETH_GENESIS"
 	tdmGenesisKey = "TDM_GENESIS"
+
+	genesisTemplateKey = "GENESIS_TEMPLATE"
 )
 
 var allChainKey = []byte("AllChainID")
@@ -93,6 +95,10 @@ func calcTDMGenesisKey(chainId string) []byte {
 	return []byte(tdmGenesisKey + ":" + chainId)
 }
 
+func calcGenesisTemplateKey(chainId string) []byte {
+	return []byte(genesisTemplateKey + ":" + chainId)
+}
+
 func GetChainInfo(db dbm.DB, chainId string) *ChainInfo {
 	mtx.RLock()
 	defer mtx.RUnlock()
@@ -325,6 +331,27 @@ func LoadChainGenesis(db dbm.DB, chainId string) (ethGenesis, tdmGenesis []byte)
 	return
 }
 
+// SaveChildChainGenesisTemplate save the raw genesis template set by the SetChildChainGenesis tx
+func SaveChildChainGenesisTemplate(db dbm.DB, chainId string, template []byte) {
+	if len(template) == 0 {
+		return
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	db.SetSync(calcGenesisTemplateKey(chainId), template)
+}
+
+// LoadChildChainGenesisTemplate load the genesis template for child chain, nil if the chain has no template
+func LoadChildChainGenesisTemplate(db dbm.DB, chainId string) (*ChildChainGenesisTemplate, error) {
+	mtx.RLock()
+	data := db.Get(calcGenesisTemplateKey(chainId))
+	mtx.RUnlock()
+
+	return DecodeChildChainGenesisTemplate(chainId, data)
+}
+
 // ---------------------
 // Pending Chain
 var pendingChainMtx sync.Mutex
@@ -474,6 +501,7 @@ func ProcessPostPendingData(db dbm.DB, newPendingIdxBytes []byte, deleteChildCha
 	// Remove the Child Chain
 	for _, id := range deleteChildChainIds {
 		db.DeleteSync(calcPendingChainInfoKey(id))
+		db.DeleteSync(calcGenesisTemplateKey(id))
 	}
 
 	// Update the Idx Bytes
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	tmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/crypto"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"github.com/intfoundation/intchain/params"
+)
+
+// MaxGenesisTemplateSize is the max size of the genesis template carried by the SetChildChainGenesis tx
+const MaxGenesisTemplateSize = 64 * 1024
+
+// ChildChainGenesisTemplate customizes the genesis of the child chain.
+// It is carried as json by the SetChildChainGenesis tx the owner sends before
+// the child chain launches, all fields are optional,
+// the default child chain genesis is used for the missing ones.
+type ChildChainGenesisTemplate struct {
+	// Hard fork config, the chain id is always derived from the child chain id
+	Config *params.ChainConfig `json:"config,omitempty"`
+	// Gas limit of the genesis block
+	GasLimit uint64 `json:"gasLimit,omitempty"`
+	// Extra balance allocation, the validators' deposit is allocated by the launch
+	Alloc GenesisAlloc `json:"alloc,omitempty"`
+
+	// Reward scheme of the tendermint genesis
+	RewardScheme   *tmTypes.RewardSchemeDoc `json:"rewardScheme,omitempty"`
+	RewardPerBlock *big.Int                 `json:"rewardPerBlock,omitempty"`
+	// Number of blocks of the first epoch
+	EpochLength uint64 `json:"epochLength,omitempty"`
+}
+
+// DecodeChildChainGenesisTemplate decodes and validates the genesis template of the child chain,
+// empty data means no template (nil template) and the default genesis is used.
+func DecodeChildChainGenesisTemplate(chainId string, data []byte) (*ChildChainGenesisTemplate, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if len(data) > MaxGenesisTemplateSize {
+		return nil, ErrGenesisTemplateTooLarge
+	}
+
+	var template ChildChainGenesisTemplate
+	if err := json.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidGenesisTemplate, err)
+	}
+
+	if err := template.Validate(chainId); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Validate checks the genesis template against the child chain
+func (t *ChildChainGenesisTemplate) Validate(chainId string) error {
+	if t.Config != nil {
+		if t.Config.IntChainId != "" && t.Config.IntChainId != chainId {
+			return invalidTemplate("config chain id %s not match the child chain %s", t.Config.IntChainId, chainId)
+		}
+		if t.Config.ChainId != nil && t.Config.ChainId.Cmp(new(big.Int).SetBytes(crypto.Keccak256([]byte(chainId)))) != 0 {
+			return invalidTemplate("config numeric chain id %v not derived from the child chain %s", t.Config.ChainId, chainId)
+		}
+		if t.Config.TokenBridge != nil {
+			return invalidTemplate("token bridge is only configurable in main chain")
+		}
+		if err := checkForkOrder(t.Config); err != nil {
+			return err
+		}
+	}
+
+	if t.GasLimit != 0 && (t.GasLimit < params.MinGasLimit || t.GasLimit > math.MaxInt64) {
+		return invalidTemplate("gas limit %d out of range", t.GasLimit)
+	}
+
+	for addr, account := range t.Alloc {
+		if addr == intAbi.ChainContractMagicAddr || addr == intAbi.ChildChainTokenIncentiveAddr {
+			return invalidTemplate("alloc to reserved address %x", addr)
+		}
+		if account.Balance == nil || account.Balance.Sign() < 0 {
+			return invalidTemplate("alloc balance of %x must be non-negative", addr)
+		}
+		// Staking state only comes from the joined validators
+		if account.Amount != nil || account.DelegateBalance != nil || len(account.DepositProxiedDetail) > 0 || account.Candidate {
+			return invalidTemplate("alloc of %x contains staking state", addr)
+		}
+	}
+
+	if rs := t.RewardScheme; rs != nil {
+		if rs.TotalReward == nil || rs.TotalReward.Sign() < 0 || rs.RewardFirstYear == nil || rs.RewardFirstYear.Sign() < 0 {
+			return invalidTemplate("reward must be non-negative")
+		}
+		if rs.RewardFirstYear.Cmp(rs.TotalReward) > 0 {
+			return invalidTemplate("first year reward %v exceeds the total reward %v", rs.RewardFirstYear, rs.TotalReward)
+		}
+		if rs.EpochNumberPerYear == 0 {
+			return invalidTemplate("epoch number per year must be positive")
+		}
+	}
+
+	if t.RewardPerBlock != nil && t.RewardPerBlock.Sign() < 0 {
+		return invalidTemplate("reward per block must be non-negative")
+	}
+
+	return nil
+}
+
+// ChainConfig returns the chain config of the child chain with the template applied
+func (t *ChildChainGenesisTemplate) ChainConfig(chainId string) *params.ChainConfig {
+	config := params.NewChildChainConfig(chainId)
+	if t == nil || t.Config == nil {
+		return config
+	}
+
+	custom := *t.Config
+	custom.IntChainId = config.IntChainId
+	custom.ChainId = config.ChainId
+	if custom.IPBFT == nil {
+		custom.IPBFT = config.IPBFT
+	}
+	return &custom
+}
+
+func checkForkOrder(config *params.ChainConfig) error {
+	forks := []struct {
+		name  string
+		block *big.Int
+	}{
+		{"homesteadBlock", config.HomesteadBlock},
+		{"eip150Block", config.EIP150Block},
+		{"eip155Block", config.EIP155Block},
+		{"eip158Block", config.EIP158Block},
+		{"byzantiumBlock", config.ByzantiumBlock},
+		{"constantinopleBlock", config.ConstantinopleBlock},
+		{"petersburgBlock", config.PetersburgBlock},
+		{"istanbulBlock", config.IstanbulBlock},
+	}
+
+	var last *big.Int
+	var lastName string
+	for _, fork := range forks {
+		if fork.block == nil {
+			continue
+		}
+		if fork.block.Sign() < 0 {
+			return invalidTemplate("%s must be non-negative", fork.name)
+		}
+		if last != nil && fork.block.Cmp(last) < 0 {
+			return invalidTemplate("%s %v is before %s %v", fork.name, fork.block, lastName, last)
+		}
+		last, lastName = fork.block, fork.name
+	}
+	return nil
+}
+
+func invalidTemplate(format string, args ...interface{}) error {
+	return fmt.Errorf("%v: %s", ErrInvalidGenesisTemplate, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestDecodeChildChainGenesisTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		valid    bool
+	}{
+		{``, true},
+		{`{}`, true},
+		{`{"gasLimit": 100000000, "alloc": {"0x00000000000000000000000000000000000000aa": {"balance": "0x10"}}}`, true},
+		{`{"config": {"intChainId": "child_0", "homesteadBlock": 0, "byzantiumBlock": 10, "istanbulBlock": 20}}`, true},
+		{`{"rewardScheme": {"total_reward": "0x64", "reward_first_year": "0x14", "epoch_no_per_year": "0xc", "total_year": "0x5"}, "rewardPerBlock": 1, "epochLength": 1000}`, true},
+		// broken json
+		{`{"gasLimit": }`, false},
+		// config for other chain
+		{`{"config": {"intChainId": "child_1"}}`, false},
+		{`{"config": {"chainId": 1}}`, false},
+		// fork order
+		{`{"config": {"byzantiumBlock": 10, "istanbulBlock": 5}}`, false},
+		// gas limit too low
+		{`{"gasLimit": 1}`, false},
+		// reserved address
+		{`{"alloc": {"0x0000000000000000000000000000000000001001": {"balance": "0x10"}}}`, false},
+		// staking state
+		{`{"alloc": {"0x00000000000000000000000000000000000000aa": {"balance": "0x10", "amount": "0x10"}}}`, false},
+		// reward
+		{`{"rewardScheme": {"total_reward": "0x14", "reward_first_year": "0x64", "epoch_no_per_year": "0xc", "total_year": "0x5"}}`, false},
+		{`{"rewardScheme": {"total_reward": "0x64", "reward_first_year": "0x14", "epoch_no_per_year": "0x0", "total_year": "0x5"}}`, false},
+		{`{"rewardPerBlock": -1}`, false},
+	}
+
+	for i, test := range tests {
+		_, err := DecodeChildChainGenesisTemplate("child_0", []byte(test.template))
+		if test.valid && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("test %d: expected error, got nil", i)
+		}
+	}
+
+	large := `{"alloc": {}, "extra": "` + strings.Repeat("0", MaxGenesisTemplateSize) + `"}`
+	if _, err := DecodeChildChainGenesisTemplate("child_0", []byte(large)); err != ErrGenesisTemplateTooLarge {
+		t.Errorf("large template: have %v, want %v", err, ErrGenesisTemplateTooLarge)
+	}
+}
+
+func TestChildChainGenesisTemplateChainConfig(t *testing.T) {
+	var nilTemplate *ChildChainGenesisTemplate
+	if config := nilTemplate.ChainConfig("child_0"); config.IntChainId != "child_0" || config.IPBFT == nil {
+		t.Fatalf("default config mismatch: %v", config)
+	}
+
+	template, err := DecodeChildChainGenesisTemplate("child_0", []byte(`{"config": {"homesteadBlock": 0, "istanbulBlock": 100}}`))
+	if err != nil {
+		t.Fatalf("failed to decode template: %v", err)
+	}
+	config := template.ChainConfig("child_0")
+	if config.IntChainId != "child_0" || config.ChainId == nil || config.IPBFT == nil {
+		t.Fatalf("chain id or engine not filled: %v", config)
+	}
+	if config.IstanbulBlock.Cmp(big.NewInt(100)) != 0 || config.ByzantiumBlock != nil {
+		t.Fatalf("fork config not applied: %v", config)
+	}
+}
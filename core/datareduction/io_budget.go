@@ -0,0 +1,58 @@
+package datareduction
+
+import (
+	"time"
+)
+
+// ioBudget throttles the data reduction to a number of database operations per
+// second, so the pruning in background doesn't starve the block import of disk I/O.
+type ioBudget struct {
+	limit int       // operations allowed per second, 0 for unlimited
+	used  int       // operations spent since start, minus the budget of the elapsed seconds
+	start time.Time // start of the current budget window
+
+	quit chan struct{}
+}
+
+func newIOBudget(limit int, quit chan struct{}) *ioBudget {
+	return &ioBudget{
+		limit: limit,
+		start: time.Now(),
+		quit:  quit,
+	}
+}
+
+// spend records the database operations done.
+func (b *ioBudget) spend(ops int) {
+	b.used += ops
+}
+
+// wait blocks until the spent operations are within the budget again, it returns
+// false if the data reduction is stopped meanwhile.
+func (b *ioBudget) wait() bool {
+	for {
+		select {
+		case <-b.quit:
+			return false
+		default:
+		}
+		if b.limit <= 0 {
+			return true
+		}
+		if elapsed := time.Since(b.start); elapsed >= time.Second {
+			b.used -= b.limit * int(elapsed/time.Second)
+			if b.used < 0 {
+				b.used = 0
+			}
+			b.start = b.start.Add(elapsed.Truncate(time.Second))
+		}
+		if b.used < b.limit {
+			return true
+		}
+		select {
+		case <-time.After(time.Second - time.Since(b.start)):
+		case <-b.quit:
+			return false
+		}
+	}
+}
@@ -1,17 +1,17 @@
 package datareduction
 
 import (
-	"bytes"
-	"fmt"
+	"errors"
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/core"
 	"github.com/intfoundation/intchain/core/rawdb"
 	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/state/snapshot"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/event"
 	"github.com/intfoundation/intchain/intdb"
 	"github.com/intfoundation/intchain/log"
-	"github.com/intfoundation/intchain/rlp"
-	"github.com/intfoundation/intchain/trie"
-	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -21,24 +21,62 @@ var (
 	max_count_trie uint64 = 1000
 	// max retain trie height
 	max_remain_trie uint64 = 1000
-	// emptyRoot is the known root hash of an empty trie.
-	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
 
 	pruning int32 // indicate pruning is running or not
+
+	// errPruneStopped is returned if the data reduction is stopped in the middle of a pass
+	errPruneStopped = errors.New("data reduction stopped")
+	// errMissingHeader is returned if the header of a block to scan or mark is not found
+	errMissingHeader = errors.New("missing header")
+)
+
+const (
+	// pruneChunkSize is the number of trie nodes deleted under one hold of the chain lock.
+	pruneChunkSize = 10000
 )
 
-type NodeCount map[common.Hash]uint64
+// pruneChain provides the chain access required by the data reduction.
+type pruneChain interface {
+	CurrentBlock() *types.Block
+	GetHeaderByNumber(number uint64) *types.Header
+	StateCache() state.Database
+	Snapshots() *snapshot.Tree
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+
+	MuLock()
+	MuUnLock()
+}
 
+// PruneProcessor prunes the state tries of the old blocks in background, while
+// the chain keeps importing blocks.
+//
+// The blocks are pruned a window of max_count_trie blocks at a time, keeping the
+// state of the latest max_remain_trie blocks at least:
+//
+//  1. scan:  the trie nodes of the window are recorded as prune candidates.
+//  2. mark:  the trie nodes of the retained blocks are marked as kept, dropping the candidates.
+//  3. prune: the candidates left are deleted from the chain db, under the chain lock and
+//     after marking the blocks imported meanwhile, in chunks of pruneChunkSize.
+//
+// The candidates and the markers are stored in the prune db, so the memory is bounded.
+// The scan number is committed after step 1 and the prune number after step 3, a
+// window in progress is resumed from the mark step after a restart. The markers
+// are dropped before every mark step, a stopped pass may leave the marker of a
+// node whose sub trie is not marked yet.
 type PruneProcessor struct {
-	db      intdb.Database // Low level persistent database to store prune counting statistics
-	prunedb PruneDatabase
+	db intdb.Database // Low level persistent database to store the prune progress, candidates and markers
 
-	bc      *core.BlockChain
+	bc      pruneChain
 	chainDb intdb.Database // database instance to delete the state/block data
 
 	pruneBodyData bool
+	limit         uint64 // the last block number to prune up to, 0 to follow the chain head
 
-	nodeCount NodeCount
+	walker *state.StateWalker // remembers the trie nodes visited by the current pass
+	budget *ioBudget
+
+	quit chan struct{}
+	wg   sync.WaitGroup
 }
 
 type PruneStatus struct {
@@ -48,8 +86,6 @@ type PruneStatus struct {
 	LatestPruneNumber uint64 `json:"latest_prune_number"`
 }
 
-type processLeafTrie func(addr common.Address, account state.Account)
-
 func StartPruning() bool {
 	return atomic.CompareAndSwapInt32(&pruning, 0, 1)
 }
@@ -58,268 +94,365 @@ func StopPruning() bool {
 	return atomic.CompareAndSwapInt32(&pruning, 1, 0)
 }
 
-func NewPruneProcessor(chaindb, prunedb intdb.Database, bc *core.BlockChain, pruneBodyData bool) *PruneProcessor {
+// NewPruneProcessor creates the data reduction of the chain, limit is the last block
+// number to prune up to (0 to follow the chain head) and ioBudget the database
+// operations allowed per second (0 for unlimited).
+func NewPruneProcessor(chaindb, prunedb intdb.Database, bc *core.BlockChain, pruneBodyData bool, limit uint64, ioBudget int) *PruneProcessor {
+	return newPruneProcessor(chaindb, prunedb, bc, pruneBodyData, limit, ioBudget)
+}
+
+func newPruneProcessor(chaindb, prunedb intdb.Database, bc pruneChain, pruneBodyData bool, limit uint64, ioBudget int) *PruneProcessor {
+	quit := make(chan struct{})
 	return &PruneProcessor{
 		db:            prunedb,
-		prunedb:       NewDatabase(prunedb),
 		bc:            bc,
 		chainDb:       chaindb,
 		pruneBodyData: pruneBodyData,
-		nodeCount:     make(NodeCount),
+		limit:         limit,
+		walker:        state.NewStateWalker(bc.StateCache(), true),
+		budget:        newIOBudget(ioBudget, quit),
+		quit:          quit,
 	}
 }
 
-func (p *PruneProcessor) Process(blockNumber, scanNumber, pruneNumber uint64) (uint64, uint64) {
-
-	var needScan bool
-	var scanStart, scanEnd uint64
-	for {
-		// Step 1. determine the scan height
-		needScan, scanStart, scanEnd = calculateScan(scanNumber, blockNumber)
-
-		log.Infof("Data Reduction - scan ? %v , %d - %d", needScan, scanStart, scanEnd)
+// Start runs the data reduction in background.
+func (p *PruneProcessor) Start() {
+	p.wg.Add(1)
+	go p.loop()
+}
 
-		if needScan {
+// Stop terminates the data reduction, the window in progress is resumed on the next start.
+func (p *PruneProcessor) Stop() {
+	select {
+	case <-p.quit:
+	default:
+		close(p.quit)
+	}
+	p.wg.Wait()
+}
 
-			// Step 2. Read Latest Node Count
-			pruneBodyStart := uint64(0)
+func (p *PruneProcessor) loop() {
+	defer p.wg.Done()
+	defer StopPruning()
 
-			if pruneNumber > 0 {
+	headCh := make(chan core.ChainHeadEvent, 10)
+	headSub := p.bc.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
 
-				pruneBodyStart = pruneNumber + 1
+	for {
+		blockNumber := p.bc.CurrentBlock().NumberU64()
+		if p.limit != 0 && p.limit < blockNumber {
+			blockNumber = p.limit
+		}
 
-				// Add previous state root for prune
-				for i := pruneNumber + 1; i <= scanNumber; i++ {
-					header := p.bc.GetHeaderByNumber(i)
-					p.countBlockChainTrie(header.Root, false)
-					log.Infof("countBlockChainTrie for block %d", i)
-				}
-			} else {
-				pruneBodyStart = scanStart
+		scanNumber, pruneNumber, scanned, pruned := p.readLastNumber()
+		var (
+			needScan       bool
+			scanStart      uint64
+			scanEnd        uint64
+			scanInProgress = scanned && (!pruned || scanNumber > pruneNumber)
+		)
+		if scanInProgress {
+			// Resume the window scanned before the restart
+			needScan, scanEnd = true, scanNumber
+			if pruned {
+				scanStart = pruneNumber + 1
 			}
+		} else {
+			if pruned {
+				scanStart = pruneNumber + 1
+			}
+			needScan, scanStart, scanEnd = calculateScan(scanStart, blockNumber)
+		}
 
-			for i := scanStart; i <= scanEnd+1; i++ {
+		if !needScan {
+			if p.limit != 0 && blockNumber == p.limit {
+				log.Info("Data Reduction - Reached the user defined last block number", "number", p.limit)
+				return
+			}
+			// Wait more blocks to prune
+			select {
+			case <-headCh:
+			case <-headSub.Err():
+				return
+			case <-p.quit:
+				return
+			}
+			continue
+		}
 
-				//TODO Cache the header
-				header := p.bc.GetHeaderByNumber(i)
-				p.countBlockChainTrie(header.Root, false)
+		log.Infof("Data Reduction - scan %d - %d, resume %v", scanStart, scanEnd, scanInProgress)
+		if err := p.processWindow(scanStart, scanEnd, scanInProgress); err != nil {
+			if err != errPruneStopped {
+				log.Error("Data Reduction - Failed to prune", "from", scanStart, "to", scanEnd, "err", err)
+			}
+			return
+		}
+	}
+}
 
-				//log.Printf("Block: %v, Root %x", i, header.Root)
-				if i%max_count_trie == max_count_trie-1 || i == scanEnd {
+// calculateScan returns the window of blocks to prune, starting from the given block
+// and leaving the latest max_remain_trie blocks.
+func calculateScan(from, latestBlockHeight uint64) (scanOrNot bool, start, end uint64) {
+	start = from
+	if latestBlockHeight < from || latestBlockHeight-from <= max_remain_trie {
+		return false, start, 0
+	}
 
-					p.bc.MuLock()
+	end = latestBlockHeight - max_remain_trie
+	if end-start >= max_count_trie {
+		end = start + max_count_trie - 1
+	}
+	return true, start, end
+}
 
-					header := p.bc.CurrentBlock().Header()
-					log.Infof("lastest block number is %v\n", header.Number.Uint64())
-					p.countBlockChainTrie(header.Root, true)
-					p.processScanData(i)
+// processWindow prunes the state of the blocks from scanStart to scanEnd.
+func (p *PruneProcessor) processWindow(scanStart, scanEnd uint64, scanned bool) error {
+	start := time.Now()
 
-					p.bc.MuUnLock()
+	// Step 1. record the trie nodes of the window as candidates
+	if !scanned {
+		if err := p.clearPass(rawdb.IteratePruneCandidates, rawdb.DeletePruneCandidate); err != nil {
+			return err
+		}
+		if err := p.scan(scanStart, scanEnd); err != nil {
+			return err
+		}
+		rawdb.WriteHeadScanNumber(p.db, scanEnd)
+	}
 
-					if p.pruneBodyData {
-						for j := pruneBodyStart; j <= i; j++ {
-							rawdb.DeleteBody(p.chainDb, rawdb.ReadCanonicalHash(p.chainDb, i), i)
-						}
-						log.Infof("deleted block from %v to %v", pruneBodyStart, i-1)
-						pruneBodyStart = i
-					}
-				}
-				//log.Infof("countBlockChainTrie for block %d", i)
-			}
+	// Step 2. mark the trie nodes of the retained blocks, from scratch as the markers
+	// left by a stopped pass may hide the unmarked nodes below them
+	if err := p.clearPass(rawdb.IteratePruneMarkers, rawdb.DeletePruneMarker); err != nil {
+		return err
+	}
+	marked, err := p.mark(scanEnd)
+	if err != nil {
+		return err
+	}
 
-			blockNumber = p.bc.CurrentBlock().NumberU64()
-			scanNumber = scanEnd + 1
-			pruneNumber = scanEnd
+	// Step 3. delete the candidates left
+	count, err := p.prune(marked)
+	if err != nil {
+		return err
+	}
 
-		} else {
-			time.Sleep(5 * time.Second) //sleep 5 seconds to wait more blocks to prune
-			blockNumber = p.bc.CurrentBlock().NumberU64()
+	if p.pruneBodyData {
+		for i := scanStart; i <= scanEnd; i++ {
+			rawdb.DeleteBody(p.chainDb, rawdb.ReadCanonicalHash(p.chainDb, i), i)
 		}
+		log.Infof("Data Reduction - deleted block body from %v to %v", scanStart, scanEnd)
 	}
 
-	return scanEnd + 1, scanEnd
+	p.writeLastNumber(scanEnd, scanEnd)
+	log.Info("Data Reduction - Scan/Prune Completed", "from", scanStart, "to", scanEnd, "nodes", count, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
 }
 
-func calculateScan(scan, latestBlockHeight uint64) (scanOrNot bool, from, to uint64) {
-
-	from = scan
-	to = 0
-
-	unscanHeight := latestBlockHeight - scan
-	if unscanHeight > max_remain_trie {
-		to = latestBlockHeight - max_remain_trie
+// clearPass removes the candidates or the markers left by the previous pass.
+func (p *PruneProcessor) clearPass(iterate func(intdb.Iteratee, func(common.Hash) bool), del func(intdb.Writer, common.Hash)) error {
+	batch := p.db.NewBatch()
+	iterate(p.db, func(hash common.Hash) bool {
+		del(batch, hash)
+		p.budget.spend(1)
+		if batch.ValueSize() >= intdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Error("Data Reduction - Error when write the clear batch", "err", err)
+			}
+			batch.Reset()
+		}
+		return p.budget.wait()
+	})
+	if err := batch.Write(); err != nil {
+		return err
 	}
-
-	if to != 0 {
-		scanOrNot = true
+	if !p.budget.wait() {
+		return errPruneStopped
 	}
-
-	return
+	return nil
 }
 
-func (p *PruneProcessor) readLatestNodeCount(scanNumber, pruneNumber uint64) NodeCount {
-	nodeCount := make(NodeCount)
+// scan records the trie nodes of the blocks as prune candidates.
+func (p *PruneProcessor) scan(from, to uint64) error {
+	p.walker.Reset()
+	batch := p.db.NewBatch()
 
-	lastHash := rawdb.ReadDataPruneTrieRootHash(p.db, scanNumber, pruneNumber)
-	if (lastHash != common.Hash{}) {
-		lastPruneTrie, openErr := p.prunedb.OpenPruneTrie(lastHash)
-		if openErr != nil {
-			log.Error("Data Reduction - Unable read the last Prune Trie.", "err", openErr)
-		} else {
-			it := trie.NewIterator(lastPruneTrie.NodeIterator(nil))
-			for it.Next() {
-				nodeHash := common.BytesToHash(lastPruneTrie.GetKey(it.Key))
-				var nodeHashCount uint64
-				rlp.DecodeBytes(it.Value, &nodeHashCount)
-				nodeCount[nodeHash] = nodeHashCount
-			}
+	visit := func(hash common.Hash, code bool) (bool, error) {
+		// The contract codes are not pruned
+		if code || rawdb.HasPruneCandidate(p.db, hash) {
+			return false, nil
+		}
+		rawdb.WritePruneCandidate(batch, hash)
+		p.budget.spend(2) // candidate lookup and write
+
+		if err := p.flush(batch, true); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	for i := from; i <= to; i++ {
+		header := p.bc.GetHeaderByNumber(i)
+		if header == nil {
+			return errMissingHeader
+		}
+		if err := p.walker.Walk(header.Root, visit); err != nil {
+			return err
 		}
 	}
-	return nodeCount
+	return p.flush(batch, false)
 }
 
-func (p *PruneProcessor) countBlockChainTrie(root common.Hash, markNoPrune bool) (skip bool) {
-	t, openErr := p.bc.StateCache().OpenTrie(root)
-	if openErr != nil {
-		if _, ok := openErr.(*trie.MissingNodeError); ok {
-			// Missing Node Error means the root node of the trie has been removed earlier, so skip the trie and return
-			skip = true
-		} else {
-			log.Error("Data Reduction - Error when open the Main Trie", "err", openErr, "stateroot", root)
+// mark marks the trie nodes of the blocks after the window as kept, it returns
+// the last block marked.
+func (p *PruneProcessor) mark(scanEnd uint64) (uint64, error) {
+	p.walker.Reset()
+	batch := p.db.NewBatch()
+
+	marked := scanEnd
+	for {
+		head := p.bc.CurrentBlock().NumberU64()
+		if marked >= head {
+			break
 		}
-		return
+		if err := p.markBlocks(batch, marked+1, head, true); err != nil {
+			return marked, err
+		}
+		marked = head
 	}
+	return marked, p.flush(batch, false)
+}
 
-	countTrie(t, p.nodeCount, markNoPrune, func(addr common.Address, account state.Account) {
-		if account.Root != emptyRoot {
-			if storageTrie, stErr := p.bc.StateCache().OpenStorageTrie(common.Hash{}, account.Root); stErr == nil {
-				countTrie(storageTrie, p.nodeCount, markNoPrune, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the Storage Trie", "err", stErr, "storageroot", account.Root, "account", addr)
-			}
+// markBlocks marks the trie nodes of the blocks as kept, dropping the candidates.
+func (p *PruneProcessor) markBlocks(batch intdb.Batch, from, to uint64, throttle bool) error {
+	visit := func(hash common.Hash, code bool) (bool, error) {
+		if code || rawdb.HasPruneMarker(p.db, hash) {
+			return false, nil
 		}
+		rawdb.WritePruneMarker(batch, hash)
+		rawdb.DeletePruneCandidate(batch, hash)
+		p.budget.spend(3) // marker lookup and write, candidate delete
 
-		if account.TX1Root != emptyRoot {
-			if tx1Trie, tx1Err := p.bc.StateCache().OpenTX1Trie(common.Hash{}, account.TX1Root); tx1Err == nil {
-				countTrie(tx1Trie, p.nodeCount, markNoPrune, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the TX1 Trie", "err", tx1Err, "tx1root", account.TX1Root, "account", addr)
-			}
+		if err := p.flush(batch, throttle); err != nil {
+			return false, err
 		}
-
-		if account.TX3Root != emptyRoot {
-			if tx3Trie, tx3Err := p.bc.StateCache().OpenTX3Trie(common.Hash{}, account.TX3Root); tx3Err == nil {
-				countTrie(tx3Trie, p.nodeCount, markNoPrune, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the TX3 Trie", "err", tx3Err, "tx3root", account.TX3Root, "account", addr)
-			}
+		return true, nil
+	}
+	for i := from; i <= to; i++ {
+		header := p.bc.GetHeaderByNumber(i)
+		if header == nil {
+			return errMissingHeader
 		}
-
-		if account.ProxiedRoot != emptyRoot {
-			if proxiedTrie, proxiedErr := p.bc.StateCache().OpenProxiedTrie(common.Hash{}, account.ProxiedRoot); proxiedErr == nil {
-				countTrie(proxiedTrie, p.nodeCount, markNoPrune, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the Proxied Trie", "err", proxiedErr, "proxiedroot", account.ProxiedRoot, "account", addr)
-			}
+		if err := p.walker.Walk(header.Root, visit); err != nil {
+			return err
 		}
-
-		if account.RewardRoot != emptyRoot {
-			if rewardTrie, rewardErr := p.bc.StateCache().OpenRewardTrie(common.Hash{}, account.RewardRoot); rewardErr == nil {
-				countTrie(rewardTrie, p.nodeCount, markNoPrune, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the Reward Trie", "err", rewardErr, "rewardroot", account.RewardRoot, "account", addr)
-			}
+	}
+	// The snapshot generator reads the trie of the disk layer, keep it until the
+	// snapshot is generated
+	if snaps := p.bc.Snapshots(); snaps != nil {
+		if root, generating := snaps.DiskRoot(); generating {
+			return p.walker.Walk(root, visit)
 		}
-	})
-	return
+	}
+	return nil
 }
 
-func countTrie(t state.Trie, nodeCount NodeCount, markNoPrune bool, processLeaf processLeafTrie) {
-
-	child := true
-	if !markNoPrune {
-		for it := t.NodeIterator(nil); it.Next(child); {
-			if !it.Leaf() {
-				nodeHash := it.Hash()
-				if _, exist := nodeCount[nodeHash]; exist {
-					child = false
-				} else {
-					nodeCount[nodeHash] = 0 //this node occurs, may need prune
-					child = true
-				}
-			} else {
-				// Process the Account -> Inner Trie
-				if processLeaf != nil {
-					addr := t.GetKey(it.LeafKey())
-					if len(addr) == 20 {
-						var data state.Account
-						rlp.DecodeBytes(it.LeafBlob(), &data)
-
-						processLeaf(common.BytesToAddress(addr), data)
-					}
-				}
+// prune deletes the candidates from the chain db. The blocks imported since the
+// last marked one are marked first, under the chain lock, so the deleted nodes are
+// never referenced by the new blocks.
+func (p *PruneProcessor) prune(marked uint64) (int, error) {
+	var (
+		count   int
+		err     error
+		chunk   []common.Hash
+		stopped bool
+	)
+	deleteChunk := func() {
+		markBatch := p.db.NewBatch()
+		deleteBatch := p.chainDb.NewBatch()
+
+		p.bc.MuLock()
+		defer p.bc.MuUnLock()
+
+		if head := p.bc.CurrentBlock().NumberU64(); head > marked {
+			if err = p.markBlocks(markBatch, marked+1, head, false); err != nil {
+				return
 			}
+			marked = head
+			if err = markBatch.Write(); err != nil {
+				return
+			}
+			markBatch.Reset()
 		}
-	} else {
-		for it := t.NodeIterator(nil); it.Next(child); {
-			if !it.Leaf() {
-				nodeHash := it.Hash()
-				nodeCount[nodeHash] = 1 //this node occurs in the latest block, mark no prune
-			} else {
-				// Process the Account -> Inner Trie
-				if processLeaf != nil {
-					addr := t.GetKey(it.LeafKey())
-					if len(addr) == 20 {
-						var data state.Account
-						rlp.DecodeBytes(it.LeafBlob(), &data)
-
-						processLeaf(common.BytesToAddress(addr), data)
-					}
-				}
+		for _, hash := range chunk {
+			// The candidate may be marked as kept after the iteration started
+			if !rawdb.HasPruneCandidate(p.db, hash) {
+				continue
+			}
+			if err = deleteBatch.Delete(hash.Bytes()); err != nil {
+				return
 			}
+			rawdb.DeletePruneCandidate(markBatch, hash)
+			count++
+		}
+		if err = deleteBatch.Write(); err != nil {
+			return
 		}
+		err = markBatch.Write()
+		p.budget.spend(3 * len(chunk)) // candidate lookup and delete, node delete
 	}
-}
-
-func (p *PruneProcessor) processScanData(latestScanNumber uint64) uint64 {
 
-	log.Infof("Data Reduction - After Scan, lastest scan number: %d", latestScanNumber)
-
-	// Prune State Data
-	p.pruneData()
-
-	newPruneNumber := latestScanNumber
-
-	// Commit the new scaned/pruned node count to trie
-	p.writeLastNumber(latestScanNumber, newPruneNumber)
-
-	log.Infof("Data Reduction - Scan/Prune Completed for trie %d %d", latestScanNumber, newPruneNumber)
-	return newPruneNumber
+	rawdb.IteratePruneCandidates(p.db, func(hash common.Hash) bool {
+		chunk = append(chunk, hash)
+		if len(chunk) < pruneChunkSize {
+			return true
+		}
+		deleteChunk()
+		chunk = chunk[:0]
+		if err == nil && !p.budget.wait() {
+			stopped = true
+		}
+		return err == nil && !stopped
+	})
+	if err == nil && !stopped && len(chunk) > 0 {
+		deleteChunk()
+	}
+	if err != nil {
+		return count, err
+	}
+	if stopped {
+		return count, errPruneStopped
+	}
+	return count, nil
 }
 
-func (p *PruneProcessor) pruneData() {
-
-	count := 0
-
-	batch := p.chainDb.NewBatch()
-	for node, latest := range p.nodeCount {
-		if latest == 0 {
-			if batchDeleteError := batch.Delete(node.Bytes()); batchDeleteError != nil {
-				log.Error("Data Reduction - Error when delete the hash from chaindb", "err", batchDeleteError, "hash", node)
-			}
-			delete(p.nodeCount, node)
-			count++
-		} else {
-			p.nodeCount[node] = 0
+// flush writes the batch once it grows large enough, throttled by the I/O budget if required.
+func (p *PruneProcessor) flush(batch intdb.Batch, throttle bool) error {
+	if batch.ValueSize() < intdb.IdealBatchSize && throttle {
+		if !p.budget.wait() {
+			return errPruneStopped
 		}
+		return nil
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	batch.Reset()
+	if throttle && !p.budget.wait() {
+		return errPruneStopped
 	}
+	return nil
+}
 
-	log.Infof("Data Reduction - %d hashes will be deleted from chaindb", count)
-	if writeErr := batch.Write(); writeErr != nil {
-		log.Error("Data Reduction - Error when write the deletion batch", "err", writeErr)
-	} else {
-		log.Infof("Data Reduction - write the deletion batch success, delete %v hashes", count)
+func (p *PruneProcessor) readLastNumber() (scanNumber, pruneNumber uint64, scanned, pruned bool) {
+	if ps := rawdb.ReadHeadScanNumber(p.db); ps != nil {
+		scanNumber = *ps
+		scanned = true
+	}
+	if pp := rawdb.ReadHeadPruneNumber(p.db); pp != nil {
+		pruneNumber = *pp
+		pruned = true
 	}
+	return
 }
 
 func (p *PruneProcessor) writeLastNumber(lastScanNumber, lastPruneNumber uint64) {
@@ -327,22 +460,6 @@ func (p *PruneProcessor) writeLastNumber(lastScanNumber, lastPruneNumber uint64)
 	rawdb.WriteHeadPruneNumber(p.db, lastPruneNumber)
 }
 
-func (nc NodeCount) String() string {
-	list := make([]common.Hash, 0, len(nc))
-	for key := range nc {
-		list = append(list, key)
-	}
-	sort.Slice(list, func(i, j int) bool {
-		return bytes.Compare(list[i].Bytes(), list[j].Bytes()) == 1
-	})
-
-	result := ""
-	for _, key := range list {
-		result += fmt.Sprintf("%v: %d \n", key.Hex(), nc[key])
-	}
-	return result
-}
-
 func GetLatestStatus(prunedb intdb.Database) *PruneStatus {
 	var scanNo, pruneNo uint64
 	if ps := rawdb.ReadHeadScanNumber(prunedb); ps != nil {
@@ -358,108 +475,3 @@ func GetLatestStatus(prunedb intdb.Database) *PruneStatus {
 		LatestPruneNumber: pruneNo,
 	}
 }
-
-/*
-func (p *PruneProcessor) pruneBlockChainTrie(root common.Hash, nodeCount NodeCount) {
-	t, openErr := p.bc.StateCache().OpenTrie(root)
-	if openErr != nil {
-		log.Error("Data Reduction - Error when open the Main Trie", "err", openErr, "stateroot", root)
-		return
-	}
-
-	pruneTrie(t, nodeCount, &p.pendingDeleteHashList, func(addr common.Address, account state.Account) {
-		if account.Root != emptyRoot {
-			if storageTrie, stErr := p.bc.StateCache().OpenStorageTrie(common.Hash{}, account.Root); stErr == nil {
-				pruneTrie(storageTrie, nodeCount, &p.pendingDeleteHashList, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the Storage Trie", "err", stErr, "storageroot", account.Root, "account", addr)
-			}
-		}
-
-		if account.TX1Root != emptyRoot {
-			if tx1Trie, tx1Err := p.bc.StateCache().OpenTX1Trie(common.Hash{}, account.TX1Root); tx1Err == nil {
-				pruneTrie(tx1Trie, nodeCount, &p.pendingDeleteHashList, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the TX1 Trie", "err", tx1Err, "tx1root", account.TX1Root, "account", addr)
-			}
-		}
-
-		if account.TX3Root != emptyRoot {
-			if tx3Trie, tx3Err := p.bc.StateCache().OpenTX3Trie(common.Hash{}, account.TX3Root); tx3Err == nil {
-				pruneTrie(tx3Trie, nodeCount, &p.pendingDeleteHashList, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the TX3 Trie", "err", tx3Err, "tx3root", account.TX3Root, "account", addr)
-			}
-		}
-
-		if account.ProxiedRoot != emptyRoot {
-			if proxiedTrie, proxiedErr := p.bc.StateCache().OpenProxiedTrie(common.Hash{}, account.ProxiedRoot); proxiedErr == nil {
-				pruneTrie(proxiedTrie, nodeCount, &p.pendingDeleteHashList, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the Proxied Trie", "err", proxiedErr, "proxiedroot", account.ProxiedRoot, "account", addr)
-			}
-		}
-
-		if account.RewardRoot != emptyRoot {
-			if rewardTrie, rewardErr := p.bc.StateCache().OpenRewardTrie(common.Hash{}, account.RewardRoot); rewardErr == nil {
-				pruneTrie(rewardTrie, nodeCount, &p.pendingDeleteHashList, nil)
-			} else {
-				log.Error("Data Reduction - Error when open the Reward Trie", "err", rewardErr, "rewardroot", account.RewardRoot, "account", addr)
-			}
-		}
-	})
-
-}
-
-func pruneTrie(t state.Trie, nodeCount NodeCount, pendingDeleteHashList *[]common.Hash, processLeaf processLeafTrie) {
-	child := true
-	for it := t.NodeIterator(nil); it.Next(child); {
-		if !it.Leaf() {
-			nodeHash := it.Hash()
-			if nodeCount[nodeHash] > 0 {
-				nodeCount[nodeHash]--
-			}
-
-			if nodeCount[nodeHash] == 0 {
-				child = true
-				*pendingDeleteHashList = append(*pendingDeleteHashList, nodeHash)
-				delete(nodeCount, nodeHash)
-			} else {
-				child = false
-			}
-		} else {
-			// Process the Account -> Inner Trie
-			if processLeaf != nil {
-				addr := t.GetKey(it.LeafKey())
-				if len(addr) == 20 {
-					var data state.Account
-					rlp.DecodeBytes(it.LeafBlob(), &data)
-
-					processLeaf(common.BytesToAddress(addr), data)
-				}
-			}
-		}
-	}
-}
-
-func (p *PruneProcessor) commitDataPruneTrie(nodeCount NodeCount, lastScanNumber, lastPruneNumber uint64) {
-	// Store the Node Count into data prune trie
-	// Commit the Prune Trie
-	pruneTrie, _ := p.prunedb.OpenPruneTrie(common.Hash{})
-
-	for key, count := range nodeCount {
-		value, _ := rlp.EncodeToBytes(count)
-		pruneTrie.TryUpdate(key[:], value)
-	}
-	pruneTrieRoot, commit_err := pruneTrie.Commit(nil)
-	log.Info("Data Reduction - Commit Prune Trie", "hash", pruneTrieRoot.Hex(), "err", commit_err)
-	// Commit to Prune DB
-	db_commit_err := p.prunedb.TrieDB().Commit(pruneTrieRoot, true)
-	log.Info("Data Reduction - Write to Prune DB", "err", db_commit_err)
-
-	// Write the Root Hash of Prune Trie
-	rawdb.WriteDataPruneTrieRootHash(p.db, pruneTrieRoot, lastScanNumber, lastPruneNumber)
-	// Write the last number
-	p.writeLastNumber(lastScanNumber, lastPruneNumber)
-}
-*/
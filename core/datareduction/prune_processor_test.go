@@ -0,0 +1,260 @@
+package datareduction
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/state/snapshot"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/event"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/trie"
+)
+
+func TestCalculateScan(t *testing.T) {
+	tests := []struct {
+		from, latest uint64
+		scan         bool
+		start, end   uint64
+	}{
+		{0, 0, false, 0, 0},
+		{0, max_remain_trie, false, 0, 0},
+		{0, max_remain_trie + 1, true, 0, 1},
+		{500, 300, false, 500, 0},
+		{10, max_remain_trie + 500, true, 10, 500},
+		{10, 5 * max_remain_trie, true, 10, 10 + max_count_trie - 1},
+	}
+	for i, tt := range tests {
+		scan, start, end := calculateScan(tt.from, tt.latest)
+		if scan != tt.scan || start != tt.start || end != tt.end {
+			t.Errorf("test %d: have (%v, %d, %d), want (%v, %d, %d)", i, scan, start, end, tt.scan, tt.start, tt.end)
+		}
+	}
+}
+
+func TestIOBudgetThrottle(t *testing.T) {
+	quit := make(chan struct{})
+	budget := newIOBudget(100, quit)
+
+	budget.spend(99)
+	start := time.Now()
+	if !budget.wait() {
+		t.Fatal("wait aborted")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("throttled within budget: %v", elapsed)
+	}
+
+	budget.spend(50)
+	if !budget.wait() {
+		t.Fatal("wait aborted")
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("not throttled over budget: %v", elapsed)
+	}
+	if budget.used != 49 {
+		t.Fatalf("used mismatch: have %d, want %d", budget.used, 49)
+	}
+
+	budget.spend(1000)
+	close(quit)
+	if budget.wait() {
+		t.Fatal("wait not aborted on quit")
+	}
+}
+
+func TestIOBudgetUnlimited(t *testing.T) {
+	budget := newIOBudget(0, make(chan struct{}))
+	budget.spend(1 << 20)
+
+	start := time.Now()
+	if !budget.wait() {
+		t.Fatal("wait aborted")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("unlimited budget throttled: %v", elapsed)
+	}
+}
+
+// pruneTestChain serves the headers of a chain of states, the state of every
+// block is kept in the chain db.
+type pruneTestChain struct {
+	headers []*types.Header
+	cache   state.Database
+	feed    event.Feed
+}
+
+func (c *pruneTestChain) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(c.headers[len(c.headers)-1])
+}
+
+func (c *pruneTestChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+func (c *pruneTestChain) StateCache() state.Database { return c.cache }
+func (c *pruneTestChain) Snapshots() *snapshot.Tree  { return nil }
+func (c *pruneTestChain) MuLock()                    {}
+func (c *pruneTestChain) MuUnLock()                  {}
+
+func (c *pruneTestChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+// stopDB invokes a callback after every batch written to the database. The batches
+// are flushed every hundred or so markers, the prune keys are stored without values.
+type stopDB struct {
+	intdb.Database
+	written func()
+}
+
+func (db *stopDB) NewBatch() intdb.Batch {
+	return &stopBatch{Batch: db.Database.NewBatch(), db: db}
+}
+
+type stopBatch struct {
+	intdb.Batch
+	db *stopDB
+}
+
+func (b *stopBatch) ValueSize() int {
+	return b.Batch.ValueSize() * 1024
+}
+
+func (b *stopBatch) Write() error {
+	err := b.Batch.Write()
+	if b.db.written != nil {
+		b.db.written()
+	}
+	return err
+}
+
+// newPruneTestChain creates a chain of five blocks, the blocks 0 and 1 are pruned
+// and the blocks 2 to 4 share a single retained state.
+func newPruneTestChain(t *testing.T) (intdb.Database, *pruneTestChain, []common.Hash) {
+	chaindb := rawdb.NewMemoryDatabase()
+	chain := &pruneTestChain{cache: state.NewDatabase(chaindb)}
+
+	statedb, _ := state.New(common.Hash{}, chain.cache)
+	commit := func() common.Hash {
+		root, err := statedb.Commit(false)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		if err := chain.cache.TrieDB().Commit(root, false); err != nil {
+			t.Fatalf("failed to commit trie: %v", err)
+		}
+		statedb, _ = state.New(root, chain.cache)
+		return root
+	}
+	for i := 0; i < 1000; i++ {
+		statedb.AddBalance(common.BigToAddress(big.NewInt(int64(i+1))), big.NewInt(int64(i+1)))
+	}
+	roots := []common.Hash{commit()}
+	statedb.AddBalance(common.BigToAddress(big.NewInt(1)), big.NewInt(1))
+	roots = append(roots, commit())
+	statedb.AddBalance(common.BigToAddress(big.NewInt(2)), big.NewInt(1))
+	retained := commit()
+	roots = append(roots, retained, retained, retained)
+	for i, root := range roots {
+		chain.headers = append(chain.headers, &types.Header{Number: big.NewInt(int64(i)), Root: root})
+	}
+	return chaindb, chain, roots
+}
+
+// Tests that a data reduction stopped in the middle of the mark step, with the
+// markers of a partially marked trie written, keeps the retained state complete
+// once resumed.
+func TestPruneResumeMark(t *testing.T) {
+	defer func(count, remain uint64) { max_count_trie, max_remain_trie = count, remain }(max_count_trie, max_remain_trie)
+	max_count_trie, max_remain_trie = 2, 2
+
+	chaindb, chain, roots := newPruneTestChain(t)
+	retained := roots[2]
+
+	// Stop the pass right after the first markers are written
+	prunedb := &stopDB{Database: rawdb.NewMemoryDatabase()}
+	p := newPruneProcessor(chaindb, prunedb, chain, false, 0, 0)
+	prunedb.written = func() {
+		var marked bool
+		rawdb.IteratePruneMarkers(prunedb, func(common.Hash) bool {
+			marked = true
+			return false
+		})
+		if marked {
+			p.Stop()
+		}
+	}
+	if err := p.processWindow(0, 1, false); err != errPruneStopped {
+		t.Fatalf("error mismatch: have %v, want %v", err, errPruneStopped)
+	}
+	// Resume the window
+	prunedb.written = nil
+	p = newPruneProcessor(chaindb, prunedb, chain, false, 0, 0)
+	if err := p.processWindow(0, 1, true); err != nil {
+		t.Fatalf("failed to resume the window: %v", err)
+	}
+
+	if ok, _ := chaindb.Has(roots[0].Bytes()); ok {
+		t.Errorf("pruned state root %x left", roots[0])
+	}
+	tr, err := trie.New(retained, trie.NewDatabase(chaindb))
+	if err != nil {
+		t.Fatalf("retained state root missing: %v", err)
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("retained state incomplete: %v", err)
+	}
+}
+
+// Tests that a node missing from a retained state aborts the data reduction, the
+// nodes after it would be left unmarked and deleted.
+func TestPruneMissingNode(t *testing.T) {
+	defer func(count, remain uint64) { max_count_trie, max_remain_trie = count, remain }(max_count_trie, max_remain_trie)
+	max_count_trie, max_remain_trie = 2, 2
+
+	chaindb, chain, roots := newPruneTestChain(t)
+
+	// Delete a node of the retained state only, below its root
+	pruned := make(map[common.Hash]bool)
+	for _, root := range roots[:2] {
+		tr, _ := trie.New(root, trie.NewDatabase(chaindb))
+		for it := tr.NodeIterator(nil); it.Next(true); {
+			pruned[it.Hash()] = true
+		}
+	}
+	var missing common.Hash
+	tr, _ := trie.New(roots[2], trie.NewDatabase(chaindb))
+	for it := tr.NodeIterator(nil); it.Next(true); {
+		if hash := it.Hash(); hash != (common.Hash{}) && hash != roots[2] && !pruned[hash] {
+			missing = hash
+			break
+		}
+	}
+	if missing == (common.Hash{}) {
+		t.Fatal("no node unique to the retained state")
+	}
+	if err := chaindb.Delete(missing.Bytes()); err != nil {
+		t.Fatalf("failed to delete node: %v", err)
+	}
+	chain.cache = state.NewDatabase(chaindb)
+
+	p := newPruneProcessor(chaindb, rawdb.NewMemoryDatabase(), chain, false, 0, 0)
+	if err := p.processWindow(0, 1, false); err == nil {
+		t.Fatal("data reduction succeeded with a missing node")
+	}
+	if ok, _ := chaindb.Has(roots[0].Bytes()); !ok {
+		t.Errorf("state root %x deleted by the aborted data reduction", roots[0])
+	}
+}
@@ -33,6 +33,22 @@ var (
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrTipAboveFeeCap is a sanity error to ensure no one is able to specify a
+	// transaction with a tip higher than the total fee cap.
+	ErrTipAboveFeeCap = errors.New("max priority fee per gas higher than max fee per gas")
+
+	// ErrTipVeryHigh is a sanity error to avoid extremely big numbers specified
+	// in the tip field.
+	ErrTipVeryHigh = errors.New("max priority fee per gas higher than 2^256-1")
+
+	// ErrFeeCapVeryHigh is a sanity error to avoid extremely big numbers specified
+	// in the fee cap field.
+	ErrFeeCapVeryHigh = errors.New("max fee per gas higher than 2^256-1")
+
+	// ErrFeeCapTooLow is returned if the transaction fee cap is less than the
+	// the base fee of the block.
+	ErrFeeCapTooLow = errors.New("max fee per gas less than block base fee")
+
 	// ErrInvalidTx4 is returned if the tx4 has been checked during execution
 	ErrInvalidTx4 = errors.New("invalid Tx4")
 
@@ -93,4 +109,43 @@ var (
 
 	// ErrNotAllowedInChildChain is returned if the transaction with child flag = false be sent to child chain
 	ErrNotAllowedInChildChain = errors.New("transaction not allowed in child chain")
+
+	// Token Bridge Error
+	// ErrTokenBridgeDisabled is returned if the token bridge is not configured on the main chain
+	ErrTokenBridgeDisabled = errors.New("token bridge disabled")
+
+	// ErrInvalidBridgeChainId is returned if the chain id of the bridge tx not match the current chain
+	ErrInvalidBridgeChainId = errors.New("bridge chain id not match")
+
+	// ErrInvalidTokenAmount is returned if the bridged token amount is not positive
+	ErrInvalidTokenAmount = errors.New("token amount must be positive")
+
+	// ErrTokenLockNotFound is returned if the lock tx not found or not locked for the sender
+	ErrTokenLockNotFound = errors.New("token lock not found in main chain")
+
+	// ErrTokenAlreadyMinted is returned if the lock tx has been minted in child chain
+	ErrTokenAlreadyMinted = errors.New("token already minted")
+
+	// ErrTokenBurnNotFound is returned if the burn tx (tx3) not found in local cache
+	ErrTokenBurnNotFound = errors.New("token burn not found in child chain")
+
+	// ErrTokenAlreadyUnlocked is returned if the burn tx has been unlocked in main chain
+	ErrTokenAlreadyUnlocked = errors.New("token already unlocked")
+
+	// ErrTokenNotMapped is returned if the child chain token not mapped in the bridge contract
+	ErrTokenNotMapped = errors.New("token not mapped in bridge")
+
+	// Child Chain Genesis Template Error
+	// ErrGenesisTemplateTooLarge is returned if the genesis template carried by the SetChildChainGenesis tx is too large
+	ErrGenesisTemplateTooLarge = errors.New("child chain genesis template too large")
+
+	// ErrInvalidGenesisTemplate is returned if the genesis template can't be decoded or conflicts with the child chain
+	ErrInvalidGenesisTemplate = errors.New("invalid child chain genesis template")
+
+	// Staking Precompiled Contract Error
+	// ErrStakingUnavailable is returned if the staking precompiled contract is called without the staking callbacks
+	ErrStakingUnavailable = errors.New("staking functions unavailable")
+
+	// ErrEpochNotFound is returned if the epoch precompiled contract queries a block without a known epoch
+	ErrEpochNotFound = errors.New("epoch of the block not found")
 )
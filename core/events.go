@@ -21,8 +21,33 @@ import (
 	"github.com/intfoundation/intchain/core/types"
 )
 
-// TxPreEvent is posted when a transaction enters the transaction pool.
-type TxPreEvent struct{ Tx *types.Transaction }
+// TxPreEvent is posted when a transaction enters the transaction pool. Private
+// transactions must not be propagated to the network.
+type TxPreEvent struct {
+	Tx      *types.Transaction
+	Private bool
+}
+
+// TxDropEvent is posted when a transaction is removed from the transaction pool
+// for any other reason than its promotion.
+type TxDropEvent struct {
+	Tx          *types.Transaction
+	Reason      TxDropReason
+	Replacement *types.Transaction // Transaction taking the place of the dropped one, if any
+}
+
+// TxDropReason describes why a transaction was removed from the transaction pool.
+type TxDropReason string
+
+const (
+	TxDropReplaced    TxDropReason = "replaced"    // Replaced by a better priced transaction with the same nonce
+	TxDropUnderpriced TxDropReason = "underpriced" // Evicted by better priced transactions or below the price threshold
+	TxDropStale       TxDropReason = "stale"       // Nonce too low, usually due to inclusion in a block
+	TxDropUnpayable   TxDropReason = "unpayable"   // Balance too low or gas above the block gas limit
+	TxDropRateLimit   TxDropReason = "ratelimit"   // Exceeding the account or global slot allowances
+	TxDropExpired     TxDropReason = "expired"     // Queued for longer than the configured lifetime
+	TxDropInvalid     TxDropReason = "invalid"     // Failed to execute during block production
+)
 
 //Tx3ProofDataEvent is posted when a tx3ProofData enters
 type Tx3ProofDataEvent struct{ Tx3PrfDt *types.TX3ProofData }
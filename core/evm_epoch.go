@@ -0,0 +1,66 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+)
+
+// EVMEpoch provides the epoch attached to the IPBFT consensus engine to the
+// epoch precompiled contract.
+type EVMEpoch struct {
+	chain ChainContext
+}
+
+// NewEVMEpoch returns the epoch backend of the EVM.
+func NewEVMEpoch(chain ChainContext) *EVMEpoch {
+	return &EVMEpoch{chain: chain}
+}
+
+func (e *EVMEpoch) GetEpoch(blockNumber uint64) (uint64, uint64, uint64, *big.Int, error) {
+	ep, err := e.epoch(blockNumber)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	rewardPerBlock := new(big.Int)
+	if ep.RewardPerBlock != nil {
+		rewardPerBlock.Set(ep.RewardPerBlock)
+	}
+	return ep.Number, ep.StartBlock, ep.EndBlock, rewardPerBlock, nil
+}
+
+func (e *EVMEpoch) IsValidator(blockNumber uint64, addr common.Address) (bool, error) {
+	ep, err := e.epoch(blockNumber)
+	if err != nil {
+		return false, err
+	}
+	if ep.Validators == nil {
+		return false, nil
+	}
+	return ep.Validators.HasAddress(addr.Bytes()), nil
+}
+
+// epoch returns the epoch containing the block, the next epoch is checked as
+// well since it is already proposed when the last blocks of an epoch are executed.
+func (e *EVMEpoch) epoch(blockNumber uint64) (*epoch.Epoch, error) {
+	if e.chain == nil {
+		return nil, ErrEpochNotFound
+	}
+	ipbft, ok := e.chain.Engine().(consensus.IPBFT)
+	if !ok {
+		return nil, ErrEpochNotFound
+	}
+	ep := ipbft.GetEpoch()
+	if ep == nil {
+		return nil, ErrEpochNotFound
+	}
+	if next := ep.GetNextEpoch(); next != nil && blockNumber >= next.StartBlock && blockNumber <= next.EndBlock {
+		return next, nil
+	}
+	if ep = ep.GetEpochByBlockNumber(blockNumber); ep == nil {
+		return nil, ErrEpochNotFound
+	}
+	return ep, nil
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/core/vm"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+)
+
+// EVMStaking performs the staking functions of the staking precompiled contract
+// with the callbacks registered for the staking transactions. It collects the
+// candidates whose proxied balance changed during the transaction, their next
+// epoch votes are updated from the final state once the transaction succeeded,
+// so the votes are never taken from a call which was reverted afterwards.
+type EVMStaking struct {
+	bc         *BlockChain
+	candidates []common.Address
+	touched    map[common.Address]struct{}
+}
+
+// NewEVMStaking returns the staking backend of the EVM for a single transaction.
+func NewEVMStaking(bc *BlockChain) *EVMStaking {
+	return &EVMStaking{
+		bc:      bc,
+		touched: make(map[common.Address]struct{}),
+	}
+}
+
+func (s *EVMStaking) Delegate(db vm.StateDB, delegator, candidate common.Address, amount *big.Int) error {
+	if err := s.run(intAbi.Delegate, db, delegator, candidate, amount); err != nil {
+		return err
+	}
+	s.touch(candidate)
+	return nil
+}
+
+func (s *EVMStaking) UnDelegate(db vm.StateDB, delegator, candidate common.Address, amount *big.Int) error {
+	if err := s.run(intAbi.UnDelegate, db, delegator, candidate, amount); err != nil {
+		return err
+	}
+	s.touch(candidate)
+	return nil
+}
+
+func (s *EVMStaking) WithdrawReward(db vm.StateDB, delegator, candidate common.Address, amount *big.Int) error {
+	return s.run(intAbi.WithdrawReward, db, delegator, candidate, amount)
+}
+
+// UpdateNextEpoch updates the next epoch votes of the candidates touched by the transaction.
+func (s *EVMStaking) UpdateNextEpoch(txHash common.Hash, statedb *state.StateDB, ops *types.PendingOps) error {
+	if len(s.candidates) == 0 {
+		return nil
+	}
+	cb := GetUpdateNextEpochCb()
+	if cb == nil {
+		return ErrStakingUnavailable
+	}
+	for _, candidate := range s.candidates {
+		if err := cb(candidate, txHash, statedb, s.bc, ops); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EVMStaking) run(function intAbi.FunctionType, db vm.StateDB, from, candidate common.Address, amount *big.Int) error {
+	statedb, ok := db.(*state.StateDB)
+	cb := GetEVMStakingCb(function)
+	if !ok || cb == nil || s.bc == nil {
+		return ErrStakingUnavailable
+	}
+
+	// check Function main/child flag
+	if s.bc.Config().IsMainChain() && !function.AllowInMainChain() {
+		return ErrNotAllowedInMainChain
+	} else if !s.bc.Config().IsMainChain() && !function.AllowInChildChain() {
+		return ErrNotAllowedInChildChain
+	}
+	return cb(from, candidate, amount, statedb, s.bc)
+}
+
+func (s *EVMStaking) touch(candidate common.Address) {
+	if _, ok := s.touched[candidate]; ok {
+		return
+	}
+	s.touched[candidate] = struct{}{}
+	s.candidates = append(s.candidates, candidate)
+}
@@ -12,6 +12,8 @@ func ApplyOp(op types.PendingOp, bc *BlockChain, cch CrossChainHelper) error {
 	switch op := op.(type) {
 	case *types.CreateChildChainOp:
 		return cch.CreateChildChain(op.From, op.ChainId, op.MinValidators, op.MinDepositAmount, op.StartBlock, op.EndBlock)
+	case *types.SetChildChainGenesisOp:
+		return cch.SetChildChainGenesis(op.ChainId, op.GenesisTemplate)
 	case *types.JoinChildChainOp:
 		return cch.JoinChildChain(op.From, op.PubKey, op.ChainId, op.DepositAmount)
 	case *types.LaunchChildChainsOp:
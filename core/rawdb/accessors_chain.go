@@ -28,9 +28,39 @@ import (
 	"github.com/intfoundation/intchain/rlp"
 )
 
+// readAncient retrieves the frozen item of the block number, nil if the database
+// has no freezer or the block is not frozen yet.
+func readAncient(db intdb.Reader, kind string, number uint64) []byte {
+	adb, ok := db.(intdb.AncientReader)
+	if !ok {
+		return nil
+	}
+	data, _ := adb.Ancient(kind, number)
+	return data
+}
+
+// isFrozen reports whether the block is the frozen canonical one, all of its
+// data is in the freezer then.
+func isFrozen(db intdb.Reader, hash common.Hash, number uint64) bool {
+	frozen := readAncient(db, freezerHashTable, number)
+	return len(frozen) != 0 && common.BytesToHash(frozen) == hash
+}
+
+// readAncientByHash retrieves the frozen item of the block, nil if the block is
+// not the frozen canonical one.
+func readAncientByHash(db intdb.Reader, kind string, hash common.Hash, number uint64) []byte {
+	if !isFrozen(db, hash, number) {
+		return nil
+	}
+	return readAncient(db, kind, number)
+}
+
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db intdb.Reader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
+	if len(data) == 0 {
+		data = readAncient(db, freezerHashTable, number)
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
@@ -51,6 +81,23 @@ func DeleteCanonicalHash(db intdb.Writer, number uint64) {
 	}
 }
 
+// ReadAllHashes retrieves all the hashes assigned to blocks at a certain heights,
+// both canonical and reorged forks included. The frozen blocks are not included.
+func ReadAllHashes(db intdb.Iteratee, number uint64) []common.Hash {
+	prefix := headerKeyPrefix(number)
+
+	hashes := make([]common.Hash, 0, 1)
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+32 {
+			hashes = append(hashes, common.BytesToHash(key[len(key)-32:]))
+		}
+	}
+	return hashes
+}
+
 // ReadHeaderNumber returns the header number assigned to a hash.
 func ReadHeaderNumber(db intdb.Reader, hash common.Hash) *uint64 {
 	data, _ := db.Get(headerNumberKey(hash))
@@ -130,15 +177,18 @@ func WriteFastTrieProgress(db intdb.Writer, count uint64) {
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db intdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db intdb.Reader, hash common.Hash, number uint64) bool {
-	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+	if has, err := db.Has(headerKey(number, hash)); has && err == nil {
+		return true
 	}
-	return true
+	return isFrozen(db, hash, number)
 }
 
 // ReadHeader retrieves the block header corresponding to the hash.
@@ -179,6 +229,13 @@ func WriteHeader(db intdb.Writer, header *types.Header) {
 	}
 }
 
+// DeleteHeaderNumber removes hash to number mapping.
+func DeleteHeaderNumber(db intdb.Writer, hash common.Hash) {
+	if err := db.Delete(headerNumberKey(hash)); err != nil {
+		log.Crit("Failed to delete hash to number mapping", "err", err)
+	}
+}
+
 // DeleteHeader removes all block header data associated with a hash.
 func DeleteHeader(db intdb.Writer, hash common.Hash, number uint64) {
 	deleteHeaderWithoutNumber(db, hash, number)
@@ -198,6 +255,9 @@ func deleteHeaderWithoutNumber(db intdb.Writer, hash common.Hash, number uint64)
 // ReadBodyRLP retrieves the block body (transactions and uncles) in RLP encoding.
 func ReadBodyRLP(db intdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -210,10 +270,10 @@ func WriteBodyRLP(db intdb.Writer, hash common.Hash, number uint64, rlp rlp.RawV
 
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db intdb.Reader, hash common.Hash, number uint64) bool {
-	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+	if has, err := db.Has(blockBodyKey(number, hash)); has && err == nil {
+		return true
 	}
-	return true
+	return isFrozen(db, hash, number)
 }
 
 // ReadBody retrieves the block body corresponding to the hash.
@@ -249,6 +309,9 @@ func DeleteBody(db intdb.Writer, hash common.Hash, number uint64) {
 // ReadTdRLP retrieves a block's total difficulty corresponding to the hash in RLP encoding.
 func ReadTdRLP(db intdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerTDKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerDifficultyTable, hash, number)
+	}
 	return data
 }
 
@@ -287,15 +350,18 @@ func DeleteTd(db intdb.Writer, hash common.Hash, number uint64) {
 // HasReceipts verifies the existence of all the transaction receipts belonging
 // to a block.
 func HasReceipts(db intdb.Reader, hash common.Hash, number uint64) bool {
-	if has, err := db.Has(blockReceiptsKey(number, hash)); !has || err != nil {
-		return false
+	if has, err := db.Has(blockReceiptsKey(number, hash)); has && err == nil {
+		return true
 	}
-	return true
+	return isFrozen(db, hash, number)
 }
 
 // ReadReceiptsRLP retrieves all the transaction receipts belonging to a block in RLP encoding.
 func ReadReceiptsRLP(db intdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientByHash(db, freezerReceiptTable, hash, number)
+	}
 	return data
 }
 
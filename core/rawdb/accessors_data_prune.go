@@ -63,3 +63,67 @@ func WriteHeadPruneNumber(db intdb.Writer, prune uint64) {
 		log.Crit("Failed to store last prune number", "err", err)
 	}
 }
+
+// HasPruneCandidate checks if the trie node is a prune candidate.
+func HasPruneCandidate(db intdb.Reader, hash common.Hash) bool {
+	ok, _ := db.Has(dataPruneCandidateKey(hash))
+	return ok
+}
+
+// WritePruneCandidate records the trie node of a scanned trie as a prune candidate.
+func WritePruneCandidate(db intdb.Writer, hash common.Hash) {
+	if err := db.Put(dataPruneCandidateKey(hash), nil); err != nil {
+		log.Crit("Failed to store prune candidate", "err", err)
+	}
+}
+
+// DeletePruneCandidate removes the prune candidate of the trie node.
+func DeletePruneCandidate(db intdb.Writer, hash common.Hash) {
+	if err := db.Delete(dataPruneCandidateKey(hash)); err != nil {
+		log.Crit("Failed to delete prune candidate", "err", err)
+	}
+}
+
+// HasPruneMarker checks if the trie node is marked as kept.
+func HasPruneMarker(db intdb.Reader, hash common.Hash) bool {
+	ok, _ := db.Has(dataPruneMarkerKey(hash))
+	return ok
+}
+
+// WritePruneMarker marks the trie node of a retained trie as kept.
+func WritePruneMarker(db intdb.Writer, hash common.Hash) {
+	if err := db.Put(dataPruneMarkerKey(hash), nil); err != nil {
+		log.Crit("Failed to store prune marker", "err", err)
+	}
+}
+
+// DeletePruneMarker removes the kept marker of the trie node.
+func DeletePruneMarker(db intdb.Writer, hash common.Hash) {
+	if err := db.Delete(dataPruneMarkerKey(hash)); err != nil {
+		log.Crit("Failed to delete prune marker", "err", err)
+	}
+}
+
+// IteratePruneCandidates calls fn with the hash of every prune candidate until fn returns false.
+func IteratePruneCandidates(db intdb.Iteratee, fn func(hash common.Hash) bool) {
+	iteratePruneHashes(db, dataPruneCandidatePrefix, fn)
+}
+
+// IteratePruneMarkers calls fn with the hash of every kept trie node until fn returns false.
+func IteratePruneMarkers(db intdb.Iteratee, fn func(hash common.Hash) bool) {
+	iteratePruneHashes(db, dataPruneMarkerPrefix, fn)
+}
+
+func iteratePruneHashes(db intdb.Iteratee, prefix []byte, fn func(hash common.Hash) bool) {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		// The prune trie nodes share the database, skip the keys of other length
+		if key := it.Key(); len(key) == len(prefix)+common.HashLength {
+			if !fn(common.BytesToHash(key[len(prefix):])) {
+				return
+			}
+		}
+	}
+}
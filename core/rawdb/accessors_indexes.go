@@ -17,6 +17,8 @@
 package rawdb
 
 import (
+	"encoding/binary"
+
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/intdb"
@@ -58,6 +60,25 @@ func DeleteTxLookupEntry(db intdb.Writer, hash common.Hash) {
 	db.Delete(txLookupKey(hash))
 }
 
+// ReadTxIndexTail retrieves the number of the oldest block whose receipts and
+// transaction indexes are retained, nil if the entire history is.
+func ReadTxIndexTail(db intdb.Reader) *uint64 {
+	data, _ := db.Get(txIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteTxIndexTail stores the number of the oldest block whose receipts and
+// transaction indexes are retained.
+func WriteTxIndexTail(db intdb.Writer, number uint64) {
+	if err := db.Put(txIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the transaction index tail", "err", err)
+	}
+}
+
 // ReadTransaction retrieves a specific transaction from the database, along with
 // its added positional metadata.
 func ReadTransaction(db intdb.Reader, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
@@ -88,3 +88,52 @@ func TestLookupStorage(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the receipts and transaction indexes of a block range can be dropped
+// and the transactions reindexed, tracking the retained history tail.
+func TestTxIndexRetention(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var blocks []*types.Block
+	for i := uint64(0); i < 10; i++ {
+		tx := types.NewTransaction(i, common.BytesToAddress([]byte{0x11}), big.NewInt(111), 1111, big.NewInt(11111), nil)
+		block := types.NewBlock(&types.Header{Number: new(big.Int).SetUint64(i)}, []*types.Transaction{tx}, nil, nil)
+
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), i)
+		WriteReceipts(db, block.Hash(), i, types.Receipts{{TxHash: tx.Hash(), Logs: []*types.Log{}}})
+		WriteTxLookupEntries(db, block)
+		blocks = append(blocks, block)
+	}
+	if tail := ReadTxIndexTail(db); tail != nil {
+		t.Fatalf("tail of the entire history: have %d, want nil", *tail)
+	}
+	check := func(tail uint64) {
+		if have := ReadTxIndexTail(db); have == nil || *have != tail {
+			t.Fatalf("tail mismatch: have %v, want %d", have, tail)
+		}
+		for i, block := range blocks {
+			tx := block.Transactions()[0]
+			if txn, _, _, _ := ReadTransaction(db, tx.Hash()); (txn != nil) != (uint64(i) >= tail) {
+				t.Fatalf("block %d: transaction index mismatch, tail %d", i, tail)
+			}
+			if ReadBody(db, block.Hash(), block.NumberU64()) == nil {
+				t.Fatalf("block %d: body dropped", i)
+			}
+		}
+	}
+	UnindexTransactions(db, 0, 6, nil)
+	check(6)
+	for i := 0; i < 6; i++ {
+		if receipts := ReadReceipts(db, blocks[i].Hash(), uint64(i)); receipts != nil {
+			t.Fatalf("block %d: receipts retained", i)
+		}
+	}
+	if receipts := ReadReceipts(db, blocks[6].Hash(), 6); len(receipts) != 1 {
+		t.Fatalf("block 6: receipts dropped")
+	}
+	IndexTransactions(db, 3, 6, nil)
+	check(3)
+	IndexTransactions(db, 0, 3, nil)
+	check(0)
+}
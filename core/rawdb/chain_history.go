@@ -0,0 +1,126 @@
+package rawdb
+
+import (
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+)
+
+// IndexTransactions writes the transaction indexes of the canonical blocks in
+// [from, to), from the newest block to the oldest one so that the retained
+// history stays contiguous, moving the tail down to from. The receipts of the
+// blocks are not restored, they can only be regenerated by re-executing them.
+func IndexTransactions(db intdb.Database, from uint64, to uint64, interrupt chan struct{}) {
+	if from >= to {
+		return
+	}
+	var (
+		batch  = db.NewBatch()
+		start  = time.Now()
+		logged = time.Now()
+		blocks uint64
+		txs    int
+	)
+	for number := to; number > from; number-- {
+		hash := ReadCanonicalHash(db, number-1)
+		if body := ReadBody(db, hash, number-1); body != nil {
+			for _, tx := range body.Transactions {
+				if err := batch.Put(txLookupKey(tx.Hash()), hash.Bytes()); err != nil {
+					log.Crit("Failed to store transaction lookup entry", "err", err)
+				}
+			}
+			txs += len(body.Transactions)
+		} else {
+			log.Warn("Missing body of canonical block", "number", number-1, "hash", hash)
+		}
+		blocks++
+
+		if batch.ValueSize() > intdb.IdealBatchSize {
+			WriteTxIndexTail(batch, number-1)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed writing batch to db", "error", err)
+			}
+			batch.Reset()
+
+			select {
+			case <-interrupt:
+				log.Info("Transaction indexing interrupted", "blocks", blocks, "txs", txs, "tail", number-1, "elapsed", common.PrettyDuration(time.Since(start)))
+				return
+			default:
+			}
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Indexing transactions", "blocks", blocks, "txs", txs, "tail", number-1, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+	}
+	WriteTxIndexTail(batch, from)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	// Keep quiet about the few blocks of a chain head update
+	logger := log.Debug
+	if blocks > 1024 {
+		logger = log.Info
+	}
+	logger("Indexed transactions", "blocks", blocks, "txs", txs, "tail", from, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// UnindexTransactions removes the receipts and the transaction indexes of the
+// canonical blocks in [from, to), from the oldest block to the newest one, moving
+// the tail up to to. The block bodies are kept, the TX3 proofs and the headers
+// of the cross-chain operations are built from them.
+func UnindexTransactions(db intdb.Database, from uint64, to uint64, interrupt chan struct{}) {
+	if from >= to {
+		return
+	}
+	var (
+		batch  = db.NewBatch()
+		start  = time.Now()
+		logged = time.Now()
+		blocks uint64
+		txs    int
+	)
+	for number := from; number < to; number++ {
+		hash := ReadCanonicalHash(db, number)
+		if body := ReadBody(db, hash, number); body != nil {
+			for _, tx := range body.Transactions {
+				DeleteTxLookupEntry(batch, tx.Hash())
+			}
+			txs += len(body.Transactions)
+		}
+		DeleteReceipts(batch, hash, number)
+		blocks++
+
+		if batch.ValueSize() > intdb.IdealBatchSize {
+			WriteTxIndexTail(batch, number+1)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed writing batch to db", "error", err)
+			}
+			batch.Reset()
+
+			select {
+			case <-interrupt:
+				log.Info("Transaction unindexing interrupted", "blocks", blocks, "txs", txs, "tail", number+1, "elapsed", common.PrettyDuration(time.Since(start)))
+				return
+			default:
+			}
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Unindexing transactions", "blocks", blocks, "txs", txs, "tail", number+1, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+	}
+	WriteTxIndexTail(batch, to)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	// Keep quiet about the few blocks of a chain head update
+	logger := log.Debug
+	if blocks > 1024 {
+		logger = log.Info
+	}
+	logger("Unindexed transactions", "blocks", blocks, "txs", txs, "tail", to, "elapsed", common.PrettyDuration(time.Since(start)))
+}
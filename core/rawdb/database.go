@@ -17,15 +17,137 @@
 package rawdb
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/intfoundation/intchain/intdb"
 	"github.com/intfoundation/intchain/intdb/leveldb"
 	"github.com/intfoundation/intchain/intdb/memorydb"
+	"github.com/intfoundation/intchain/log"
 )
 
+// errNotSupported is returned if the database doesn't support the required operation.
+var errNotSupported = errors.New("this operation is not supported")
+
+// freezerdb is a database wrapper that enabled freezer data retrievals.
+type freezerdb struct {
+	intdb.KeyValueStore
+	intdb.AncientStore
+}
+
+// Close implements io.Closer, closing both the fast key-value store as well as
+// the slow ancient tables.
+func (frdb *freezerdb) Close() error {
+	var errs []error
+	if err := frdb.AncientStore.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := frdb.KeyValueStore.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// nofreezedb is a database wrapper that disables freezer data retrievals.
+type nofreezedb struct {
+	intdb.KeyValueStore
+}
+
+// HasAncient returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) HasAncient(kind string, number uint64) (bool, error) {
+	return false, errNotSupported
+}
+
+// Ancient returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) Ancient(kind string, number uint64) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// Ancients returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) Ancients() (uint64, error) {
+	return 0, errNotSupported
+}
+
+// AncientSize returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) AncientSize(kind string) (uint64, error) {
+	return 0, errNotSupported
+}
+
+// AppendAncient returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) AppendAncient(number uint64, hash, header, body, receipts, td []byte) error {
+	return errNotSupported
+}
+
+// TruncateAncients returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) TruncateAncients(items uint64) error {
+	return errNotSupported
+}
+
+// Sync returns an error as we don't have a backing chain freezer.
+func (db *nofreezedb) Sync() error {
+	return errNotSupported
+}
+
 // NewDatabase creates a high level database on top of a given key-value data
 // store without a freezer moving immutable chain segments into cold storage.
 func NewDatabase(db intdb.KeyValueStore) intdb.Database {
-	return db
+	return &nofreezedb{
+		KeyValueStore: db,
+	}
+}
+
+// NewDatabaseWithFreezer creates a high level database on top of a given key-
+// value data store with a freezer moving immutable chain segments into cold
+// storage. The blocks of an existing database are migrated into the freezer by
+// the background freezing thread.
+func NewDatabaseWithFreezer(db intdb.KeyValueStore, freezer string, namespace string) (intdb.Database, error) {
+	// Create the idle freezer instance
+	frdb, err := newFreezer(freezer, namespace)
+	if err != nil {
+		return nil, err
+	}
+	// Since the freezer can be stored separately from the user's key-value database,
+	// there's a fairly high probability that the user requests invalid combinations
+	// of the freezer and database. Ensure that we don't shoot ourselves in the foot
+	// by serving up conflicting data, leading to both datastores getting corrupted.
+	if kvgenesis, _ := db.Get(headerHashKey(0)); len(kvgenesis) > 0 {
+		if frozen, _ := frdb.Ancients(); frozen > 0 {
+			// If the freezer already contains something, ensure that the genesis blocks
+			// match, otherwise we might mix up freezers across chains and destroy both
+			// the freezer and the key-value store.
+			if frgenesis, _ := frdb.Ancient(freezerHashTable, 0); !bytes.Equal(kvgenesis, frgenesis) {
+				frdb.Close()
+				return nil, fmt.Errorf("genesis mismatch: %#x (leveldb) != %#x (ancients)", kvgenesis, frgenesis)
+			}
+			// Key-value store and freezer belong to the same network. Ensure that they
+			// are contiguous, otherwise we might end up with a non-functional freezer.
+			if kvhash, _ := db.Get(headerHashKey(frozen)); len(kvhash) == 0 {
+				// Subsequent header after the freezer limit is missing from the database.
+				// Reject startup is the database has a more recent head.
+				if number := ReadHeaderNumber(db, ReadHeadHeaderHash(db)); number != nil && *number > frozen-1 {
+					frdb.Close()
+					return nil, fmt.Errorf("gap (#%d) in the chain between ancients and leveldb", frozen)
+				}
+			}
+		} else if number := ReadHeaderNumber(db, ReadHeadBlockHash(db)); number != nil && *number > 0 {
+			// Existing database without frozen blocks, the background thread moves them
+			log.Info("Migrating chain segments into the ancient database", "head", *number)
+		}
+	}
+	// Freezer is consistent with the key-value database, permit combining the two
+	frdb.wg.Add(1)
+	go frdb.freeze(db)
+
+	return &freezerdb{
+		KeyValueStore: db,
+		AncientStore:  frdb,
+	}, nil
 }
 
 // NewMemoryDatabase creates an ephemeral in-memory key-value database without a
@@ -50,3 +172,71 @@ func NewLevelDBDatabase(file string, cache int, handles int, namespace string) (
 	}
 	return NewDatabase(db), nil
 }
+
+// NewLevelDBDatabaseWithFreezer creates a persistent key-value database with a
+// freezer moving immutable chain segments into cold storage.
+func NewLevelDBDatabaseWithFreezer(file string, cache int, handles int, freezer string, namespace string) (intdb.Database, error) {
+	kvdb, err := leveldb.New(file, cache, handles, namespace)
+	if err != nil {
+		return nil, err
+	}
+	frdb, err := NewDatabaseWithFreezer(kvdb, freezer, namespace)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
+const (
+	// DBLeveldb is the name of the leveldb database engine.
+	DBLeveldb = "leveldb"
+
+	// DBPebble is the name of the pebble database engine.
+	DBPebble = "pebble"
+)
+
+// PreexistingDatabase checks the given data directory whether a database is
+// already instantiated at that location, and if so, returns the type of the
+// database engine (or the empty string).
+func PreexistingDatabase(path string) string {
+	if _, err := os.Stat(filepath.Join(path, "CURRENT")); err != nil {
+		return "" // No pre-existing db
+	}
+	if matches, err := filepath.Glob(filepath.Join(path, "OPTIONS*")); len(matches) > 0 || err != nil {
+		if err != nil {
+			panic(err) // only possible if the pattern is malformed
+		}
+		return DBPebble
+	}
+	return DBLeveldb
+}
+
+// OpenDatabase opens a persistent key-value database with the requested engine,
+// and a freezer if the freezer path is given. An existing database is always
+// opened with the engine it was created with, a mismatching request errors.
+func OpenDatabase(engine string, file string, cache int, handles int, freezer string, namespace string) (intdb.Database, error) {
+	existing := PreexistingDatabase(file)
+	if engine == "" {
+		engine = existing
+	}
+	if existing != "" && engine != existing {
+		return nil, fmt.Errorf("db.engine choice was %v but found pre-existing %v database in specified data directory", engine, existing)
+	}
+	switch engine {
+	case DBPebble:
+		log.Info("Using pebble as the backing database")
+		if freezer == "" {
+			return NewPebbleDBDatabase(file, cache, handles, namespace)
+		}
+		return NewPebbleDBDatabaseWithFreezer(file, cache, handles, freezer, namespace)
+	case DBLeveldb, "":
+		log.Info("Using leveldb as the backing database")
+		if freezer == "" {
+			return NewLevelDBDatabase(file, cache, handles, namespace)
+		}
+		return NewLevelDBDatabaseWithFreezer(file, cache, handles, freezer, namespace)
+	default:
+		return nil, fmt.Errorf("unknown db.engine %v", engine)
+	}
+}
@@ -0,0 +1,33 @@
+// +build arm64 amd64
+
+package rawdb
+
+import (
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/intdb/pebble"
+)
+
+// NewPebbleDBDatabase creates a persistent key-value database without a freezer
+// moving immutable chain segments into cold storage.
+func NewPebbleDBDatabase(file string, cache int, handles int, namespace string) (intdb.Database, error) {
+	db, err := pebble.New(file, cache, handles, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return NewDatabase(db), nil
+}
+
+// NewPebbleDBDatabaseWithFreezer creates a persistent key-value database with a
+// freezer moving immutable chain segments into cold storage.
+func NewPebbleDBDatabaseWithFreezer(file string, cache int, handles int, freezer string, namespace string) (intdb.Database, error) {
+	kvdb, err := pebble.New(file, cache, handles, namespace)
+	if err != nil {
+		return nil, err
+	}
+	frdb, err := NewDatabaseWithFreezer(kvdb, freezer, namespace)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
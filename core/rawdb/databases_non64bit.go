@@ -0,0 +1,23 @@
+// +build !arm64,!amd64
+
+package rawdb
+
+import (
+	"errors"
+
+	"github.com/intfoundation/intchain/intdb"
+)
+
+// errPebbleNotSupported is returned if the pebble engine is requested on a
+// platform it doesn't build on.
+var errPebbleNotSupported = errors.New("pebble is not supported on this platform")
+
+// NewPebbleDBDatabase is not supported on 32 bit platforms.
+func NewPebbleDBDatabase(file string, cache int, handles int, namespace string) (intdb.Database, error) {
+	return nil, errPebbleNotSupported
+}
+
+// NewPebbleDBDatabaseWithFreezer is not supported on 32 bit platforms.
+func NewPebbleDBDatabaseWithFreezer(file string, cache int, handles int, freezer string, namespace string) (intdb.Database, error) {
+	return nil, errPebbleNotSupported
+}
@@ -0,0 +1,380 @@
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/params"
+)
+
+var (
+	// errUnknownTable is returned if the user attempts to read from a table that is
+	// not tracked by the freezer.
+	errUnknownTable = errors.New("unknown table")
+
+	// errOutOrderBlock is returned if the user attempts to freeze a block which
+	// is not right after the frozen ones.
+	errOutOrderBlock = errors.New("the ancient block is out-order")
+)
+
+const (
+	// freezerRecheckInterval is the frequency to check the key-value database for
+	// chain progression that might permit new blocks to be frozen into immutable
+	// storage.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks to freeze in one batch
+	// before doing an fsync and deleting it from the key-value store.
+	freezerBatchLimit = 30000
+)
+
+// freezer is an append-only database to store immutable chain data into flat files:
+//
+//   - The append only nature ensures that disk writes are minimized.
+//   - The blocks are committed by IPBFT and never reorg, so the frozen segment is
+//     only truncated by an explicit rewind of the chain.
+type freezer struct {
+	frozen uint64 // Number of blocks already frozen (atomic, keep 64 bit aligned)
+
+	tables map[string]*freezerTable // Data tables for storing everything
+	quit   chan struct{}
+	wg     sync.WaitGroup // Tracks the background freezing thread
+	logger log.Logger
+}
+
+// newFreezer creates a chain freezer that moves ancient chain data into
+// append-only flat file containers.
+func newFreezer(datadir string, namespace string) (*freezer, error) {
+	if info, err := os.Lstat(datadir); !os.IsNotExist(err) {
+		if info.Mode()&os.ModeSymlink != 0 {
+			log.Warn("Symbolic link ancient database is not supported", "path", datadir)
+			return nil, errors.New("symbolic link datadir is not supported")
+		}
+	}
+	freezer := &freezer{
+		tables: make(map[string]*freezerTable),
+		quit:   make(chan struct{}),
+		logger: log.New("database", datadir),
+	}
+	for name, disableSnappy := range freezerNoSnappy {
+		table, err := newTable(datadir, name, disableSnappy)
+		if err != nil {
+			for _, table := range freezer.tables {
+				table.Close()
+			}
+			return nil, err
+		}
+		freezer.tables[name] = table
+	}
+	if err := freezer.repair(); err != nil {
+		for _, table := range freezer.tables {
+			table.Close()
+		}
+		return nil, err
+	}
+	freezer.logger.Info("Opened ancient database", "namespace", namespace, "frozen", freezer.frozen)
+	return freezer, nil
+}
+
+// Close terminates the chain freezer, closing all the data files.
+func (f *freezer) Close() error {
+	select {
+	case <-f.quit:
+	default:
+		close(f.quit)
+	}
+	f.wg.Wait()
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists
+// in the freezer.
+func (f *freezer) HasAncient(kind string, number uint64) (bool, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.has(number), nil
+	}
+	return false, nil
+}
+
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
+func (f *freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.Retrieve(number)
+	}
+	return nil, errUnknownTable
+}
+
+// Ancients returns the length of the frozen items.
+func (f *freezer) Ancients() (uint64, error) {
+	return atomic.LoadUint64(&f.frozen), nil
+}
+
+// AncientSize returns the ancient size of the specified category.
+func (f *freezer) AncientSize(kind string) (uint64, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.Size()
+	}
+	return 0, errUnknownTable
+}
+
+// AppendAncient injects all binary blobs belong to block at the end of the
+// append-only immutable table files.
+//
+// Notably, this function is lock free but kind of thread-safe. All out-of-order
+// injection will be rejected. But if two injections with same number happen at
+// the same time, we can get into the trouble.
+func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, td []byte) (err error) {
+	// Ensure the binary blobs we are appending is continuous with freezer.
+	if atomic.LoadUint64(&f.frozen) != number {
+		return errOutOrderBlock
+	}
+	// Rollback all inserted data if any insertion below failed to ensure
+	// the tables won't out of sync.
+	defer func() {
+		if err != nil {
+			rerr := f.repair()
+			if rerr != nil {
+				f.logger.Crit("Failed to repair freezer", "err", rerr)
+			}
+			f.logger.Info("Append ancient failed", "number", number, "err", err)
+		}
+	}()
+	blobs := []struct {
+		table string
+		blob  []byte
+	}{
+		{freezerHashTable, hash},
+		{freezerHeaderTable, header},
+		{freezerBodiesTable, body},
+		{freezerReceiptTable, receipts},
+		{freezerDifficultyTable, td},
+	}
+	for _, b := range blobs {
+		if err := f.tables[b.table].Append(number, b.blob); err != nil {
+			f.logger.Error("Failed to append ancient", "table", b.table, "number", number, "hash", common.BytesToHash(hash), "err", err)
+			return err
+		}
+	}
+	atomic.AddUint64(&f.frozen, 1) // Only modify atomically
+	return nil
+}
+
+// TruncateAncients discards any recent data above the provided threshold number.
+func (f *freezer) TruncateAncients(items uint64) error {
+	if atomic.LoadUint64(&f.frozen) <= items {
+		return nil
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, items)
+	return nil
+}
+
+// Sync flushes all data tables to disk.
+func (f *freezer) Sync() error {
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// freeze is a background thread that periodically checks the blockchain for any
+// import progress and moves ancient data from the fast database into the freezer.
+//
+// This functionality is deliberately broken off from block importing to avoid
+// incurring additional data shuffling delays on block propagation. It also
+// migrates the blocks of a database created before the freezer, a batch at a time.
+func (f *freezer) freeze(db intdb.KeyValueStore) {
+	defer f.wg.Done()
+
+	nfdb := &nofreezedb{KeyValueStore: db}
+
+	for {
+		select {
+		case <-f.quit:
+			f.logger.Info("Freezer shutting down")
+			return
+		default:
+		}
+		// Retrieve the freezing threshold.
+		hash := ReadHeadBlockHash(nfdb)
+		if hash == (common.Hash{}) {
+			f.logger.Debug("Current full block hash unavailable") // new chain, empty database
+			f.wait(freezerRecheckInterval)
+			continue
+		}
+		number := ReadHeaderNumber(nfdb, hash)
+		frozen := atomic.LoadUint64(&f.frozen)
+		switch {
+		case number == nil:
+			f.logger.Error("Current full block number unavailable", "hash", hash)
+			f.wait(freezerRecheckInterval)
+			continue
+
+		case *number < params.ImmutabilityThreshold:
+			f.logger.Debug("Current full block not old enough", "number", *number, "hash", hash, "delay", params.ImmutabilityThreshold)
+			f.wait(freezerRecheckInterval)
+			continue
+
+		case *number-params.ImmutabilityThreshold <= frozen:
+			f.logger.Debug("Ancient blocks frozen already", "number", *number, "hash", hash, "frozen", frozen)
+			f.wait(freezerRecheckInterval)
+			continue
+		}
+		// Seems we have data ready to be frozen, process in usable batches
+		limit := *number - params.ImmutabilityThreshold
+		if limit-frozen > freezerBatchLimit {
+			limit = frozen + freezerBatchLimit
+		}
+		var (
+			start    = time.Now()
+			first    = frozen
+			ancients = make([]common.Hash, 0, limit-frozen)
+			gap      bool // whether the chain data of a block is missing, e.g. pruned
+		)
+		for n := first; n < limit; n++ {
+			// Retrieves all the components of the canonical block
+			hash := ReadCanonicalHash(nfdb, n)
+			if hash == (common.Hash{}) {
+				f.logger.Error("Canonical hash missing, can't freeze", "number", n)
+				gap = true
+				break
+			}
+			header := ReadHeaderRLP(nfdb, hash, n)
+			if len(header) == 0 {
+				f.logger.Error("Block header missing, can't freeze", "number", n, "hash", hash)
+				gap = true
+				break
+			}
+			body := ReadBodyRLP(nfdb, hash, n)
+			if len(body) == 0 {
+				f.logger.Error("Block body missing, can't freeze", "number", n, "hash", hash)
+				gap = true
+				break
+			}
+			receipts := ReadReceiptsRLP(nfdb, hash, n)
+			if len(receipts) == 0 {
+				f.logger.Error("Block receipts missing, can't freeze", "number", n, "hash", hash)
+				gap = true
+				break
+			}
+			td := ReadTdRLP(nfdb, hash, n)
+			if len(td) == 0 {
+				f.logger.Error("Total difficulty missing, can't freeze", "number", n, "hash", hash)
+				gap = true
+				break
+			}
+			// Inject all the components into the relevant data tables
+			if err := f.AppendAncient(n, hash[:], header, body, receipts, td); err != nil {
+				break
+			}
+			ancients = append(ancients, hash)
+		}
+		// Batch of blocks have been frozen, flush them before wiping from leveldb
+		if err := f.Sync(); err != nil {
+			f.logger.Crit("Failed to flush frozen tables", "err", err)
+		}
+		// Wipe out all data from the active database
+		batch := db.NewBatch()
+		for i := 0; i < len(ancients); i++ {
+			// Always keep the genesis block in active database
+			if first+uint64(i) != 0 {
+				deleteBlockWithoutNumber(batch, ancients[i], first+uint64(i))
+				DeleteCanonicalHash(batch, first+uint64(i))
+			}
+		}
+		if err := batch.Write(); err != nil {
+			f.logger.Crit("Failed to delete frozen canonical blocks", "err", err)
+		}
+		batch.Reset()
+
+		// Wipe out side chains also
+		frozen = atomic.LoadUint64(&f.frozen)
+		for n := first; n < frozen; n++ {
+			// Always keep the genesis block in active database
+			if n != 0 {
+				for _, hash := range ReadAllHashes(db, n) {
+					DeleteBlock(batch, hash, n)
+				}
+			}
+		}
+		if err := batch.Write(); err != nil {
+			f.logger.Crit("Failed to delete frozen side blocks", "err", err)
+		}
+		// Log something friendly for the user
+		context := []interface{}{
+			"blocks", frozen - first, "elapsed", common.PrettyDuration(time.Since(start)), "number", frozen - 1,
+		}
+		if n := len(ancients); n > 0 {
+			context = append(context, []interface{}{"hash", ancients[n-1]}...)
+		}
+		if remaining := *number - params.ImmutabilityThreshold - frozen; remaining > 0 {
+			context = append(context, []interface{}{"remaining", remaining}...)
+		}
+		f.logger.Info("Deep froze chain segment", context...)
+
+		// The ancient tables can't skip a block, the data below the head block
+		// is never filled again once missing
+		if gap {
+			f.logger.Error("Chain data not contiguous, freezer stopped", "frozen", frozen)
+			return
+		}
+
+		// Avoid database thrashing with tiny writes
+		if frozen-first < freezerBatchLimit {
+			f.wait(freezerRecheckInterval)
+		}
+	}
+}
+
+// wait sleeps for the given duration, or returns early if the freezer is closed.
+func (f *freezer) wait(d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-f.quit:
+	}
+}
+
+// repair truncates all data tables to the same length.
+func (f *freezer) repair() error {
+	min := uint64(math.MaxUint64)
+	for _, table := range f.tables {
+		items := atomic.LoadUint64(&table.items)
+		if min > items {
+			min = items
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(min); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, min)
+	return nil
+}
@@ -0,0 +1,298 @@
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/snappy"
+	"github.com/intfoundation/intchain/log"
+)
+
+var (
+	// errClosed is returned if an operation attempts to read from or write to the
+	// freezer table after it has already been closed.
+	errClosed = errors.New("closed")
+
+	// errOutOfBounds is returned if the item requested is not contained within the
+	// freezer table.
+	errOutOfBounds = errors.New("out of bounds")
+
+	// errOutOrderInsertion is returned if the user attempts to inject out-of-order
+	// binary blobs into the freezer.
+	errOutOrderInsertion = errors.New("the append operation is out-order")
+)
+
+// indexEntrySize is the size of one index entry, the big endian end offset of the
+// item in the data file. The item n is stored at [end(n-1), end(n)).
+const indexEntrySize = 8
+
+// freezerTable represents a single chained data table within the freezer (e.g. blocks).
+// It consists of a data file (snappy encoded arbitrary data blobs) and an index file
+// (uncompressed 64 bit offsets into the data file).
+type freezerTable struct {
+	items    uint64 // Number of items stored in the table (atomic, keep 64 bit aligned)
+	noSnappy bool   // Whether the blobs are stored without snappy compression
+
+	index *os.File // File descriptor for the indexEntry file of the table
+	head  *os.File // File descriptor for the data file of the table
+	size  uint64   // Size of the data file, the end offset of the last item
+
+	lock   sync.RWMutex // Mutex protecting the data file descriptors
+	logger log.Logger
+}
+
+// newTable opens a freezer table, creating the data and index files if they are
+// non existent. Both files are truncated to the shortest common length to ensure
+// they don't go out of sync.
+func newTable(path string, name string, noSnappy bool) (*freezerTable, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	ext := ".cdat"
+	if noSnappy {
+		ext = ".rdat"
+	}
+	index, err := os.OpenFile(filepath.Join(path, name+".ridx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	head, err := os.OpenFile(filepath.Join(path, name+ext), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		index.Close()
+		return nil, err
+	}
+	tab := &freezerTable{
+		noSnappy: noSnappy,
+		index:    index,
+		head:     head,
+		logger:   log.New("database", path, "table", name),
+	}
+	if err := tab.repair(); err != nil {
+		tab.Close()
+		return nil, err
+	}
+	return tab, nil
+}
+
+// repair cross checks the head and the index file and truncates them to be in
+// sync with each other after a potential crash / data loss.
+func (t *freezerTable) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	// Drop the partially written index entry
+	items := uint64(stat.Size()) / indexEntrySize
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if stat, err = t.head.Stat(); err != nil {
+		return err
+	}
+	size := uint64(stat.Size())
+
+	// Drop the index entries pointing beyond the data file, then the data
+	// not referenced by the index
+	for items > 0 {
+		end, err := t.offset(items - 1)
+		if err != nil {
+			return err
+		}
+		if end <= size {
+			break
+		}
+		items--
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	end := uint64(0)
+	if items > 0 {
+		if end, err = t.offset(items - 1); err != nil {
+			return err
+		}
+	}
+	if end != size {
+		t.logger.Warn("Truncating dangling freezer data", "indexed", end, "stored", size)
+		if err := t.head.Truncate(int64(end)); err != nil {
+			return err
+		}
+	}
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	if err := t.head.Sync(); err != nil {
+		return err
+	}
+	t.size = end
+	atomic.StoreUint64(&t.items, items)
+	return nil
+}
+
+// offset returns the end offset of the item in the data file.
+func (t *freezerTable) offset(item uint64) (uint64, error) {
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], int64(item*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// truncate discards any recent data above the provided threshold number.
+func (t *freezerTable) truncate(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil || t.head == nil {
+		return errClosed
+	}
+	if atomic.LoadUint64(&t.items) <= items {
+		return nil
+	}
+	end := uint64(0)
+	if items > 0 {
+		var err error
+		if end, err = t.offset(items - 1); err != nil {
+			return err
+		}
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.head.Truncate(int64(end)); err != nil {
+		return err
+	}
+	t.size = end
+	atomic.StoreUint64(&t.items, items)
+	return nil
+}
+
+// Close closes all opened files.
+func (t *freezerTable) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var errs []error
+	if t.index != nil {
+		if err := t.index.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		t.index = nil
+	}
+	if t.head != nil {
+		if err := t.head.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		t.head = nil
+	}
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// Append injects a binary blob at the end of the freezer table. The item number
+// is a precautionary parameter to ensure data correctness, but the table will
+// reject already existing data.
+//
+// Note, this method will *not* flush any data to disk so be sure to explicitly
+// fsync before irreversibly deleting data from the database.
+func (t *freezerTable) Append(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil || t.head == nil {
+		return errClosed
+	}
+	if atomic.LoadUint64(&t.items) != item {
+		return errOutOrderInsertion
+	}
+	if !t.noSnappy {
+		blob = snappy.Encode(nil, blob)
+	}
+	if _, err := t.head.WriteAt(blob, int64(t.size)); err != nil {
+		return err
+	}
+	var entry [indexEntrySize]byte
+	binary.BigEndian.PutUint64(entry[:], t.size+uint64(len(blob)))
+	if _, err := t.index.WriteAt(entry[:], int64(item*indexEntrySize)); err != nil {
+		return err
+	}
+	t.size += uint64(len(blob))
+	atomic.AddUint64(&t.items, 1)
+	return nil
+}
+
+// Retrieve looks up the data offset of an item with the given number and retrieves
+// the raw binary blob from the data file.
+func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil || t.head == nil {
+		return nil, errClosed
+	}
+	if atomic.LoadUint64(&t.items) <= item {
+		return nil, errOutOfBounds
+	}
+	start := uint64(0)
+	if item > 0 {
+		var err error
+		if start, err = t.offset(item - 1); err != nil {
+			return nil, err
+		}
+	}
+	end, err := t.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.head.ReadAt(blob, int64(start)); err != nil {
+		return nil, err
+	}
+	if t.noSnappy {
+		return blob, nil
+	}
+	return snappy.Decode(nil, blob)
+}
+
+// has returns an indicator whether the specified number data
+// exists in the freezer table.
+func (t *freezerTable) has(number uint64) bool {
+	return atomic.LoadUint64(&t.items) > number
+}
+
+// Size returns the total data size in the freezer table.
+func (t *freezerTable) Size() (uint64, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil || t.head == nil {
+		return 0, errClosed
+	}
+	stat, err := t.index.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return t.size + uint64(stat.Size()), nil
+}
+
+// Sync pushes any pending data from memory out to disk. This is an expensive
+// operation, so use it with care.
+func (t *freezerTable) Sync() error {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil || t.head == nil {
+		return errClosed
+	}
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	return t.head.Sync()
+}
@@ -0,0 +1,151 @@
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func getChunk(size int, b int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(b)
+	}
+	return data
+}
+
+// TestFreezerBasics test initializing a freezertable from scratch, writing to the table,
+// and reading it back.
+func TestFreezerBasics(t *testing.T) {
+	for _, noSnappy := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "freezer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		f, err := newTable(dir, fmt.Sprintf("basics-%v", noSnappy), noSnappy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Write 15 items, the empty one included
+		for x := 0; x < 15; x++ {
+			if err := f.Append(uint64(x), getChunk(x*3, x)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.Append(20, getChunk(5, 20)); err != errOutOrderInsertion {
+			t.Fatalf("out of order append error mismatch: have %v, want %v", err, errOutOrderInsertion)
+		}
+		for y := 0; y < 15; y++ {
+			got, err := f.Retrieve(uint64(y))
+			if err != nil {
+				t.Fatalf("item %d: %v", y, err)
+			}
+			if exp := getChunk(y*3, y); !bytes.Equal(got, exp) {
+				t.Fatalf("item %d: have %x, want %x", y, got, exp)
+			}
+		}
+		if _, err := f.Retrieve(15); err != errOutOfBounds {
+			t.Fatalf("out of bounds error mismatch: have %v, want %v", err, errOutOfBounds)
+		}
+		f.Close()
+	}
+}
+
+// TestFreezerRepairDanglingData tests that the data not referenced by the index,
+// and the index entries pointing beyond the data, are dropped on open.
+func TestFreezerRepairDanglingData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f, err := newTable(dir, "dangling", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 10; x++ {
+		f.Append(uint64(x), getChunk(10, x))
+	}
+	f.Close()
+
+	// Crop the data file in the middle of the last item, and append a partial index entry
+	data, err := os.OpenFile(filepath.Join(dir, "dangling.rdat"), os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.Truncate(95)
+	data.Close()
+
+	index, err := os.OpenFile(filepath.Join(dir, "dangling.ridx"), os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index.Write([]byte{0, 0, 0})
+	index.Close()
+
+	f, err = newTable(dir, "dangling", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if f.items != 9 {
+		t.Fatalf("items mismatch: have %d, want %d", f.items, 9)
+	}
+	if _, err := f.Retrieve(9); err != errOutOfBounds {
+		t.Fatalf("out of bounds error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+	// The table continues from the repaired end
+	if err := f.Append(9, getChunk(10, 0xff)); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := f.Retrieve(9); !bytes.Equal(got, getChunk(10, 0xff)) {
+		t.Fatalf("item 9: have %x, want %x", got, getChunk(10, 0xff))
+	}
+}
+
+// TestFreezerTruncate tests truncating the table and appending after it.
+func TestFreezerTruncate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f, err := newTable(dir, "truncation", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for x := 0; x < 30; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	if err := f.truncate(10); err != nil {
+		t.Fatal(err)
+	}
+	if f.items != 10 {
+		t.Fatalf("items mismatch: have %d, want %d", f.items, 10)
+	}
+	if _, err := f.Retrieve(10); err != errOutOfBounds {
+		t.Fatalf("out of bounds error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+	if err := f.Append(10, getChunk(15, 0xaa)); err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < 11; y++ {
+		exp := getChunk(15, y)
+		if y == 10 {
+			exp = getChunk(15, 0xaa)
+		}
+		if got, err := f.Retrieve(uint64(y)); err != nil || !bytes.Equal(got, exp) {
+			t.Fatalf("item %d: have %x (%v), want %x", y, got, err, exp)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package rawdb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+)
+
+// InspectCategory is a class of database entries, told apart by their keys.
+type InspectCategory struct {
+	Name  string
+	Match func(key []byte) bool
+}
+
+// InspectStat is the number and the total size of the entries of a category.
+type InspectStat struct {
+	Name  string
+	Count uint64
+	Size  common.StorageSize
+}
+
+// matchPrefix matches the keys of the given prefix, and of the given length if
+// it's not zero.
+func matchPrefix(prefix []byte, length int) func([]byte) bool {
+	return func(key []byte) bool {
+		return bytes.HasPrefix(key, prefix) && (length == 0 || len(key) == length)
+	}
+}
+
+// matchSuffix matches the keys of the given prefix, suffix and length.
+func matchSuffix(prefix, suffix []byte, length int) func([]byte) bool {
+	return func(key []byte) bool {
+		return bytes.HasPrefix(key, prefix) && bytes.HasSuffix(key, suffix) && len(key) == length
+	}
+}
+
+// matchKeys matches the given keys exactly.
+func matchKeys(keys ...[]byte) func([]byte) bool {
+	return func(key []byte) bool {
+		for _, k := range keys {
+			if bytes.Equal(key, k) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ChainDataCategories are the categories of the chain database entries. A key
+// is tallied into the first matching category.
+var ChainDataCategories = []InspectCategory{
+	{"Headers", matchPrefix(headerPrefix, len(headerPrefix)+8+common.HashLength)},
+	{"Total difficulties", matchSuffix(headerPrefix, headerTDSuffix, len(headerPrefix)+8+common.HashLength+len(headerTDSuffix))},
+	{"Canonical hashes", matchSuffix(headerPrefix, headerHashSuffix, len(headerPrefix)+8+len(headerHashSuffix))},
+	{"Header numbers", matchPrefix(headerNumberPrefix, len(headerNumberPrefix)+common.HashLength)},
+	{"Bodies", matchPrefix(blockBodyPrefix, len(blockBodyPrefix)+8+common.HashLength)},
+	{"Receipts", matchPrefix(blockReceiptsPrefix, len(blockReceiptsPrefix)+8+common.HashLength)},
+	{"Transaction lookups", matchPrefix(txLookupPrefix, len(txLookupPrefix)+common.HashLength)},
+	{"Bloombit index", matchPrefix(bloomBitsPrefix, len(bloomBitsPrefix)+2+8+common.HashLength)},
+	{"Chain indexers", matchPrefix(BloomBitsIndexPrefix, 0)},
+	{"Trie nodes", func(key []byte) bool { return len(key) == common.HashLength }},
+	{"Trie preimages", matchPrefix(preimagePrefix, len(preimagePrefix)+common.HashLength)},
+	{"Proposers in epoch", matchPrefix([]byte("proposed-in-epoch-"), 0)},
+	// The snapshot schema is defined in core/state/snapshot
+	{"Account snapshot", matchPrefix([]byte("a"), 1+common.HashLength)},
+	{"Storage snapshot", matchPrefix([]byte("o"), 1+2*common.HashLength)},
+	{"Data prune progress", matchSuffix(dataPruneProcessPrefix, dataPruneProcessSuffix, len(dataPruneProcessPrefix)+8+8+len(dataPruneProcessSuffix))},
+	{"Data prune candidates", matchPrefix(dataPruneCandidatePrefix, len(dataPruneCandidatePrefix)+common.HashLength)},
+	{"Data prune markers", matchPrefix(dataPruneMarkerPrefix, len(dataPruneMarkerPrefix)+common.HashLength)},
+	{"Chain configs", matchPrefix(configPrefix, 0)},
+	{"Metadata", matchKeys(
+		databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey, txIndexTailKey,
+		headDataScanKey, headDataPruneKey,
+		[]byte("SnapshotRoot"), []byte("SnapshotJournal"), []byte("SnapshotGenerator"),
+	)},
+}
+
+// TX3Categories are the categories of the local TX3 cache database entries.
+var TX3Categories = []InspectCategory{
+	{"TX3 transactions", matchPrefix(tx3Prefix, 0)},
+	{"TX3 lookups", matchPrefix(tx3LookupPrefix, 0)},
+	{"TX3 proof data", matchPrefix(tx3ProofPrefix, 0)},
+	{"TX3 proof epochs", matchPrefix(tx3EpochPrefix, 0)},
+}
+
+// InspectIterator walks the entries of the iterator, tallying them into the first
+// matching category. The last stat counts the entries matching no category.
+func InspectIterator(it intdb.Iterator, categories []InspectCategory) ([]InspectStat, error) {
+	defer it.Release()
+
+	stats := make([]InspectStat, len(categories)+1)
+	for i, category := range categories {
+		stats[i].Name = category.Name
+	}
+	stats[len(categories)].Name = "Unaccounted"
+
+	var (
+		count  uint64
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for it.Next() {
+		var (
+			key  = it.Key()
+			size = common.StorageSize(len(key) + len(it.Value()))
+			i    int
+		)
+		for i = 0; i < len(categories); i++ {
+			if categories[i].Match(key) {
+				break
+			}
+		}
+		stats[i].Count++
+		stats[i].Size += size
+
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	return stats, it.Error()
+}
+
+// InspectAncients returns the number of items and the size of each freezer table,
+// nil if the database has no freezer.
+func InspectAncients(db intdb.AncientReader) []InspectStat {
+	items, err := db.Ancients()
+	if err != nil {
+		return nil
+	}
+	var stats []InspectStat
+	for _, table := range []string{freezerHeaderTable, freezerBodiesTable, freezerReceiptTable, freezerDifficultyTable, freezerHashTable} {
+		size, err := db.AncientSize(table)
+		if err != nil {
+			return nil
+		}
+		stats = append(stats, InspectStat{Name: "Ancient " + table, Count: items, Size: common.StorageSize(size)})
+	}
+	return stats
+}
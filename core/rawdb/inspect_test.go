@@ -0,0 +1,52 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/types"
+)
+
+// Tests that the database entries are tallied into their categories.
+func TestInspectIterator(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	header := &types.Header{Number: big.NewInt(42), Extra: []byte("test header")}
+	WriteHeader(db, header)
+	WriteTd(db, header.Hash(), 42, big.NewInt(42))
+	WriteCanonicalHash(db, header.Hash(), 42)
+	WriteHeadBlockHash(db, header.Hash())
+	db.Put(common.Hash{0x01}.Bytes(), []byte("trie node"))
+	db.Put(dataPruneCandidateKey(common.Hash{0x02}), nil)
+	db.Put([]byte("unknown"), []byte("entry"))
+
+	stats, err := InspectIterator(db.NewIterator(), ChainDataCategories)
+	if err != nil {
+		t.Fatalf("failed to inspect: %v", err)
+	}
+	want := map[string]uint64{
+		"Headers":               1,
+		"Total difficulties":    1,
+		"Canonical hashes":      1,
+		"Header numbers":        1,
+		"Trie nodes":            1,
+		"Data prune candidates": 1,
+		"Metadata":              1,
+		"Unaccounted":           1,
+	}
+	if len(stats) != len(ChainDataCategories)+1 {
+		t.Fatalf("stats count mismatch: have %d, want %d", len(stats), len(ChainDataCategories)+1)
+	}
+	for _, stat := range stats {
+		if stat.Count != want[stat.Name] {
+			t.Errorf("%s: count mismatch: have %d, want %d", stat.Name, stat.Count, want[stat.Name])
+		}
+		if stat.Count == 0 && stat.Size != 0 {
+			t.Errorf("%s: size of no entries: %v", stat.Name, stat.Size)
+		}
+	}
+	if stats := InspectAncients(db); stats != nil {
+		t.Errorf("ancients of database without freezer: %v", stats)
+	}
+}
@@ -1,6 +1,8 @@
 // Package rawdb contains a collection of low level database accessors.
 package rawdb
 
+import "github.com/intfoundation/intchain/common"
+
 // The fields below define the low level database schema prefixing for data prune.
 var (
 	// headDataScanKey tracks the latest know scan header's number.
@@ -12,9 +14,22 @@ var (
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	dataPruneProcessPrefix = []byte("p") // dataPruneProcessPrefix + scan num (uint64 big endian) + prune num (uint64 big endian) + dataPruneProcessSuffix -> trie root hash
 	dataPruneProcessSuffix = []byte("n")
+
+	dataPruneCandidatePrefix = []byte("c") // dataPruneCandidatePrefix + node hash -> nil, the node of a scanned trie which may be pruned
+	dataPruneMarkerPrefix    = []byte("k") // dataPruneMarkerPrefix + node hash -> nil, the node of a retained trie which is kept
 )
 
 // dataPruneNumberKey = dataPruneProcessPrefix + scan (uint64 big endian) + prune (uint64 big endian) + dataPruneProcessSuffix
 func dataPruneNumberKey(scan, prune uint64) []byte {
 	return append(append(append(dataPruneProcessPrefix, encodeBlockNumber(scan)...), encodeBlockNumber(prune)...), dataPruneProcessSuffix...)
 }
+
+// dataPruneCandidateKey = dataPruneCandidatePrefix + hash
+func dataPruneCandidateKey(hash common.Hash) []byte {
+	return append(append([]byte{}, dataPruneCandidatePrefix...), hash.Bytes()...)
+}
+
+// dataPruneMarkerKey = dataPruneMarkerPrefix + hash
+func dataPruneMarkerKey(hash common.Hash) []byte {
+	return append(append([]byte{}, dataPruneMarkerPrefix...), hash.Bytes()...)
+}
@@ -2,6 +2,7 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"github.com/intfoundation/intchain/common"
 	tdmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
@@ -17,8 +18,15 @@ var (
 	tx3Prefix       = []byte("t") // tx3Prefix + chainId + txHash -> tx3
 	tx3LookupPrefix = []byte("k") // tx3LookupPrefix + chainId + txHash -> tx3 lookup metadata
 	tx3ProofPrefix  = []byte("p") // tx3ProofPrefix + chainId + height -> proof data
+	tx3EpochPrefix  = []byte("e") // tx3EpochPrefix + chainId + height -> main chain epoch number when the proof data received
 )
 
+// TX3StoreStats is the size of the local tx3 store
+type TX3StoreStats struct {
+	Proofs int // number of the child chain blocks with tx3 proof data
+	Txs    int // number of the tx3
+}
+
 // TX3LookupEntry is a positional metadata to help looking up the tx3 proof content given only its chainId and hash.
 type TX3LookupEntry struct {
 	BlockIndex uint64
@@ -108,7 +116,7 @@ func GetAllTX3ProofData(db intdb.Database) []*types.TX3ProofData {
 			break
 		}
 
-		var proofData *types.TX3ProofData
+		proofData := new(types.TX3ProofData)
 		err := rlp.DecodeBytes(value, proofData)
 		if err != nil {
 			continue
@@ -119,8 +127,9 @@ func GetAllTX3ProofData(db intdb.Database) []*types.TX3ProofData {
 	return ret
 }
 
-// WriteTX3ProofData serializes TX3ProofData into the database.
-func WriteTX3ProofData(db intdb.Database, proofData *types.TX3ProofData) error {
+// WriteTX3ProofData serializes TX3ProofData into the database, epoch is the current
+// main chain epoch number, which is used to expire the proof data never consumed.
+func WriteTX3ProofData(db intdb.Database, proofData *types.TX3ProofData, epoch uint64) error {
 	header := proofData.Header
 	tdmExtra, err := tdmTypes.ExtractTendermintExtra(header)
 	if err != nil {
@@ -141,6 +150,9 @@ func WriteTX3ProofData(db intdb.Database, proofData *types.TX3ProofData) error {
 		if err := db.Put(key1, bss); err != nil {
 			return err
 		}
+		if err := db.Put(tx3EpochKey(chainId, num), encodeBlockNumber(epoch)); err != nil {
+			return err
+		}
 
 		for i, txIndex := range proofData.TxIndexs {
 			if err := WriteTX3(db, chainId, header, txIndex, proofData.TxProofs[i]); err != nil {
@@ -187,16 +199,25 @@ func hasTxIndex(proofData *types.TX3ProofData, target uint) bool {
 	return false
 }
 
-func WriteTX3(db intdb.Writer, chainId string, header *types.Header, txIndex uint, txProofData *types.BSKeyValueSet) error {
+// tx3FromProof retrieves the tx at txIndex from the proof of the child chain block
+func tx3FromProof(header *types.Header, txIndex uint, txProofData *types.BSKeyValueSet) (*types.Transaction, error) {
 	keybuf := new(bytes.Buffer)
 	rlp.Encode(keybuf, txIndex)
 	val, _, err := trie.VerifyProof(header.TxHash, keybuf.Bytes(), txProofData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var tx types.Transaction
 	err = rlp.DecodeBytes(val, &tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func WriteTX3(db intdb.Writer, chainId string, header *types.Header, txIndex uint, txProofData *types.BSKeyValueSet) error {
+	tx, err := tx3FromProof(header, txIndex, txProofData)
 	if err != nil {
 		return err
 	}
@@ -208,10 +229,10 @@ func WriteTX3(db intdb.Writer, chainId string, header *types.Header, txIndex uin
 			return err
 		}
 
-		if function == intAbi.WithdrawFromChildChain {
+		if intAbi.IsTX3Function(function) {
 			txHash := tx.Hash()
 			key1 := append(tx3Prefix, append([]byte(chainId), txHash.Bytes()...)...)
-			bs, _ := rlp.EncodeToBytes(&tx)
+			bs, _ := rlp.EncodeToBytes(tx)
 			if err = db.Put(key1, bs); err != nil {
 				return err
 			}
@@ -274,6 +295,7 @@ func DeleteTX3(db intdb.Database, chainId string, txHash common.Hash) {
 	if len(proofData.TxIndexs) == 0 {
 		// delete the whole proof data
 		db.Delete(key3)
+		db.Delete(tx3EpochKey(chainId, blockNumber))
 	} else {
 		// update the proof data
 		bs, _ := rlp.EncodeToBytes(proofData)
@@ -281,6 +303,69 @@ func DeleteTX3(db intdb.Database, chainId string, txHash common.Hash) {
 	}
 }
 
+// PruneTX3ProofData deletes the tx3 consumed on the main chain from the proof data received
+// before the expire epoch of the main chain, the tx3 not consumed yet are kept as they can
+// still be claimed. The proof data without received epoch (written by the old version) is
+// stamped with the current epoch, so it will be expired later.
+// It returns the number of pruned tx3 and the size of the remaining tx3 store.
+func PruneTX3ProofData(db intdb.Database, expireEpoch, currentEpoch uint64, consumed func(tx *types.Transaction) bool) (int, TX3StoreStats) {
+	type tx3Entry struct {
+		chainId string
+		hash    common.Hash
+	}
+
+	var (
+		expired []tx3Entry
+		stats   TX3StoreStats
+	)
+	iter := db.NewIteratorWithPrefix(tx3ProofPrefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, tx3ProofPrefix) || len(key) <= len(tx3ProofPrefix)+8 {
+			continue
+		}
+		chainId := string(key[len(tx3ProofPrefix) : len(key)-8])
+		number := binary.BigEndian.Uint64(key[len(key)-8:])
+
+		epoch, err := db.Get(tx3EpochKey(chainId, number))
+		if len(epoch) != 8 || err != nil {
+			db.Put(tx3EpochKey(chainId, number), encodeBlockNumber(currentEpoch))
+			epoch = encodeBlockNumber(currentEpoch)
+		}
+
+		var proofData types.TX3ProofData
+		if err := rlp.DecodeBytes(iter.Value(), &proofData); err != nil {
+			continue
+		}
+
+		remaining := len(proofData.TxIndexs)
+		if binary.BigEndian.Uint64(epoch) < expireEpoch {
+			for i, txIndex := range proofData.TxIndexs {
+				tx, err := tx3FromProof(proofData.Header, txIndex, proofData.TxProofs[i])
+				if err != nil || !consumed(tx) {
+					continue
+				}
+				expired = append(expired, tx3Entry{chainId, tx.Hash()})
+				remaining--
+			}
+		}
+		if remaining > 0 {
+			stats.Proofs++
+			stats.Txs += remaining
+		}
+	}
+	iter.Release()
+
+	for _, entry := range expired {
+		DeleteTX3(db, entry.chainId, entry.hash)
+	}
+	return len(expired), stats
+}
+
+func tx3EpochKey(chainId string, number uint64) []byte {
+	return append(append([]byte{}, tx3EpochPrefix...), append([]byte(chainId), encodeBlockNumber(number)...)...)
+}
+
 func decodeTx(txBytes []byte) (*types.Transaction, error) {
 
 	tx := new(types.Transaction)
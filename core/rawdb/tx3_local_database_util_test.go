@@ -0,0 +1,92 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/intfoundation/go-wire"
+	"github.com/intfoundation/intchain/common"
+	tdmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core/types"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+)
+
+func newTX3ProofData(t *testing.T, chainId string, number int64) (*types.TX3ProofData, *types.Transaction) {
+	input, err := intAbi.ChainABI.Pack(intAbi.WithdrawFromChildChain.String(), chainId)
+	if err != nil {
+		t.Fatalf("failed to pack tx3: %v", err)
+	}
+	tx3 := types.NewTransaction(uint64(number), intAbi.ChainContractMagicAddr, big.NewInt(1), 0, big.NewInt(1), input)
+	tx := types.NewTransaction(1, common.BytesToAddress([]byte{0x11}), big.NewInt(1), 0, big.NewInt(1), nil)
+
+	header := &types.Header{
+		Number: big.NewInt(number),
+		Extra:  wire.BinaryBytes(tdmTypes.TendermintExtra{ChainID: chainId, Time: time.Now()}),
+	}
+	block := types.NewBlock(header, []*types.Transaction{tx, tx3}, nil, nil)
+
+	proofData, err := types.NewTX3ProofData(block)
+	if err != nil {
+		t.Fatalf("failed to create proof data: %v", err)
+	}
+	return proofData, tx3
+}
+
+func TestPruneTX3ProofData(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	proofData1, tx1 := newTX3ProofData(t, "child_0", 10)
+	if err := WriteTX3ProofData(db, proofData1, 1); err != nil {
+		t.Fatalf("failed to write proof data: %v", err)
+	}
+	proofData2, tx2 := newTX3ProofData(t, "child_0", 20)
+	if err := WriteTX3ProofData(db, proofData2, 3); err != nil {
+		t.Fatalf("failed to write proof data: %v", err)
+	}
+	if len(GetAllTX3ProofData(db)) != 2 {
+		t.Fatalf("proof data count mismatch: have %d, want 2", len(GetAllTX3ProofData(db)))
+	}
+
+	consumed := make(map[common.Hash]bool)
+	isConsumed := func(tx *types.Transaction) bool { return consumed[tx.Hash()] }
+
+	// Nothing expired
+	pruned, stats := PruneTX3ProofData(db, 1, 5, isConsumed)
+	if pruned != 0 || stats.Proofs != 2 || stats.Txs != 2 {
+		t.Fatalf("prune mismatch: pruned %d, stats %+v", pruned, stats)
+	}
+
+	// The expired tx3 not consumed yet is kept
+	pruned, stats = PruneTX3ProofData(db, 2, 5, isConsumed)
+	if pruned != 0 || stats.Proofs != 2 || stats.Txs != 2 {
+		t.Fatalf("prune mismatch: pruned %d, stats %+v", pruned, stats)
+	}
+
+	// The expired tx3 consumed is pruned, the unexpired one is kept even if consumed
+	consumed[tx1.Hash()] = true
+	consumed[tx2.Hash()] = true
+	pruned, stats = PruneTX3ProofData(db, 2, 5, isConsumed)
+	if pruned != 1 || stats.Proofs != 1 || stats.Txs != 1 {
+		t.Fatalf("prune mismatch: pruned %d, stats %+v", pruned, stats)
+	}
+	if GetTX3(db, "child_0", tx1.Hash()) != nil || GetTX3ProofData(db, "child_0", tx1.Hash()) != nil {
+		t.Fatalf("expired tx3 not pruned")
+	}
+	if has, _ := db.Has(tx3EpochKey("child_0", 10)); has {
+		t.Fatalf("epoch of the pruned proof data not deleted")
+	}
+	if GetTX3(db, "child_0", tx2.Hash()) == nil || GetTX3ProofData(db, "child_0", tx2.Hash()) == nil {
+		t.Fatalf("unexpired tx3 pruned")
+	}
+
+	// Consumed tx3 deletes the proof data and its epoch
+	DeleteTX3(db, "child_0", tx2.Hash())
+	if has, _ := db.Has(tx3EpochKey("child_0", 20)); has {
+		t.Fatalf("epoch of the deleted proof data not deleted")
+	}
+	pruned, stats = PruneTX3ProofData(db, 10, 10, isConsumed)
+	if pruned != 0 || stats.Proofs != 0 || stats.Txs != 0 {
+		t.Fatalf("prune mismatch: pruned %d, stats %+v", pruned, stats)
+	}
+}
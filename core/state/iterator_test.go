@@ -18,7 +18,6 @@ package state
 
 import (
 	"bytes"
-	"github.com/intfoundation/intchain/intdb/memorydb"
 	"testing"
 
 	"github.com/intfoundation/intchain/common"
@@ -60,7 +59,7 @@ func TestNodeIteratorCoverage(t *testing.T) {
 	//	}
 	//}
 
-	it := db.TrieDB().DiskDB().(*memorydb.Database).NewIterator()
+	it := db.TrieDB().DiskDB().NewIterator()
 	for it.Next() {
 		key := it.Key()
 		if bytes.HasPrefix(key, []byte("secure-key-")) {
@@ -0,0 +1,50 @@
+package pruner
+
+import (
+	"encoding/binary"
+)
+
+// stateBloomHashes is the number of bit positions set for every key.
+const stateBloomHashes = 4
+
+// stateBloom is a bloom filter of the trie node and contract code hashes reachable
+// from the retained states.
+//
+// The keys are keccak hashes already, so the bit positions are taken from the key
+// itself instead of hashing it again. A false positive keeps a stale node in the
+// database, the reachable nodes are never reported missing.
+type stateBloom struct {
+	bits []uint64
+	size uint64 // number of bits in the filter
+}
+
+// newStateBloom creates a bloom filter of the given size in megabytes.
+func newStateBloom(megabytes uint64) *stateBloom {
+	if megabytes == 0 {
+		megabytes = 1
+	}
+	size := megabytes * 1024 * 1024 * 8
+	return &stateBloom{
+		bits: make([]uint64, size/64),
+		size: size,
+	}
+}
+
+// add inserts the 32 bytes hash key into the filter.
+func (b *stateBloom) add(key []byte) {
+	for i := 0; i < stateBloomHashes; i++ {
+		pos := binary.BigEndian.Uint64(key[i*8:]) % b.size
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// contain reports whether the 32 bytes hash key may be in the filter.
+func (b *stateBloom) contain(key []byte) bool {
+	for i := 0; i < stateBloomHashes; i++ {
+		pos := binary.BigEndian.Uint64(key[i*8:]) % b.size
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+// Package pruner implements the offline pruning of the state data, it deletes the
+// trie nodes and contract codes not reachable from the recent states of the chain.
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+)
+
+// logInterval is the time between the progress logs.
+const logInterval = 8 * time.Second
+
+// errHeadStateMissing is returned if the state of the head block is not in the database.
+var errHeadStateMissing = errors.New("head state missing")
+
+// Stats are the results of a state pruning.
+type Stats struct {
+	Marked  int                // trie nodes and contract codes retained
+	Deleted int                // trie nodes and contract codes deleted
+	Size    common.StorageSize // size of the deleted data
+}
+
+// Pruner deletes the state data not reachable from the state of the latest blocks
+// and the genesis. The node must be stopped, the blocks imported meanwhile could
+// reference the deleted data.
+//
+// The reachable trie nodes and contract codes are marked into a bloom filter, the
+// keys missing from the filter are deleted. The marking is done from scratch every
+// run, so a pruning interrupted by a crash is completed by running it again.
+type Pruner struct {
+	db     intdb.Database
+	bloom  *stateBloom
+	walker *state.StateWalker
+}
+
+// NewPruner creates a state pruner with a bloom filter of the given size in megabytes.
+func NewPruner(db intdb.Database, bloomSize uint64) *Pruner {
+	return &Pruner{
+		db:     db,
+		bloom:  newStateBloom(bloomSize),
+		walker: state.NewStateWalker(state.NewDatabase(db), false),
+	}
+}
+
+// Prune retains the state of the latest retain blocks and the genesis, and deletes
+// all the other state data from the database.
+func (p *Pruner) Prune(retain uint64) (*Stats, error) {
+	headHash := rawdb.ReadHeadBlockHash(p.db)
+	if headHash == (common.Hash{}) {
+		return nil, errors.New("head block missing")
+	}
+	headNumber := rawdb.ReadHeaderNumber(p.db, headHash)
+	if headNumber == nil {
+		return nil, fmt.Errorf("head block number missing, hash %x", headHash)
+	}
+	if retain == 0 {
+		retain = 1
+	}
+
+	stats := new(Stats)
+	start := time.Now()
+
+	// Mark the head state first, it must be complete
+	head := rawdb.ReadHeader(p.db, headHash, *headNumber)
+	if head == nil {
+		return nil, fmt.Errorf("head header missing, number %d", *headNumber)
+	}
+	if err := p.mark(head.Root, stats); err != nil {
+		return nil, fmt.Errorf("%v: %v", errHeadStateMissing, err)
+	}
+
+	// Mark the state of the other retained blocks and the genesis, the missing ones are skipped
+	numbers := []uint64{0}
+	for i := uint64(1); i < retain && i <= *headNumber; i++ {
+		numbers = append(numbers, *headNumber-i)
+	}
+	for _, number := range numbers {
+		hash := rawdb.ReadCanonicalHash(p.db, number)
+		header := rawdb.ReadHeader(p.db, hash, number)
+		if header == nil {
+			log.Warn("Retained block header missing", "number", number)
+			continue
+		}
+		if err := p.mark(header.Root, stats); err != nil {
+			log.Warn("Skip the incomplete retained state", "number", number, "root", header.Root, "err", err)
+			// The sub tries remembered may be marked partially, traverse them again for the next states
+			p.walker.Reset()
+		}
+	}
+	log.Info("Marked the retained state", "head", *headNumber, "retain", retain, "marked", stats.Marked, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	if err := p.sweep(stats); err != nil {
+		return stats, err
+	}
+	log.Info("Pruned the state data", "deleted", stats.Deleted, "size", stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Compact the database to reclaim the disk space of the deleted data, the
+	// pruning is complete even if the backend doesn't support compaction
+	cstart := time.Now()
+	log.Info("Compacting the database")
+	if err := p.db.Compact(nil, nil); err != nil {
+		log.Warn("Failed to compact the database", "err", err)
+		return stats, nil
+	}
+	log.Info("Compacted the database", "elapsed", common.PrettyDuration(time.Since(cstart)))
+	return stats, nil
+}
+
+// mark adds the trie nodes of the state and all the tries and codes of its accounts
+// into the bloom filter.
+func (p *Pruner) mark(root common.Hash, stats *Stats) error {
+	logged := time.Now()
+	return p.walker.Walk(root, func(hash common.Hash, code bool) (bool, error) {
+		p.bloom.add(hash.Bytes())
+		stats.Marked++
+
+		if time.Since(logged) > logInterval {
+			log.Info("Marking the retained state", "marked", stats.Marked)
+			logged = time.Now()
+		}
+		return true, nil
+	})
+}
+
+// sweep deletes the trie nodes and contract codes missing from the bloom filter.
+func (p *Pruner) sweep(stats *Stats) error {
+	var (
+		batch  = p.db.NewBatch()
+		logged = time.Now()
+	)
+	it := p.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		// The trie nodes and contract codes are the only keys of hash length
+		key := it.Key()
+		if len(key) != common.HashLength || p.bloom.contain(key) {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		stats.Deleted++
+		stats.Size += common.StorageSize(len(key) + len(it.Value()))
+
+		if batch.ValueSize() >= intdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > logInterval {
+			log.Info("Pruning the state data", "deleted", stats.Deleted, "size", stats.Size)
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
@@ -0,0 +1,101 @@
+package pruner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/intdb"
+)
+
+// makeTestChain creates a chain of states updating all the accounts, the contract
+// code and storage in every block.
+func makeTestChain(t *testing.T, blocks int) (intdb.Database, []common.Hash) {
+	db := rawdb.NewMemoryDatabase()
+	sdb := state.NewDatabase(db)
+
+	var (
+		roots    []common.Hash
+		root     common.Hash
+		contract = common.BytesToAddress([]byte{0xff})
+	)
+	for i := 0; i < blocks; i++ {
+		statedb, err := state.New(root, sdb)
+		if err != nil {
+			t.Fatalf("block %d: failed to open state: %v", i, err)
+		}
+		for j := byte(0); j < 32; j++ {
+			statedb.SetBalance(common.BytesToAddress([]byte{j}), big.NewInt(int64(i*100+int(j))))
+		}
+		statedb.SetCode(contract, []byte{0x60, byte(i)})
+		statedb.SetState(contract, common.Hash{1}, common.BigToHash(big.NewInt(int64(i+1))))
+
+		if root, err = statedb.Commit(false); err != nil {
+			t.Fatalf("block %d: failed to commit state: %v", i, err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatalf("block %d: failed to commit trie: %v", i, err)
+		}
+		roots = append(roots, root)
+
+		header := &types.Header{Number: big.NewInt(int64(i)), Root: root}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), uint64(i))
+		rawdb.WriteHeadBlockHash(db, header.Hash())
+	}
+	return db, roots
+}
+
+// checkState iterates the whole state from a fresh database, without any cache.
+func checkState(db intdb.Database, root common.Hash) error {
+	statedb, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		return err
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+	}
+	return it.Error
+}
+
+func TestPruneState(t *testing.T) {
+	db, roots := makeTestChain(t, 5)
+
+	stats, err := NewPruner(db, 1).Prune(2)
+	if err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+	if stats.Deleted == 0 || stats.Size == 0 {
+		t.Fatalf("nothing pruned: %+v", stats)
+	}
+	// The head, the retained block and the genesis are intact
+	for _, i := range []int{0, 3, 4} {
+		if err := checkState(db, roots[i]); err != nil {
+			t.Errorf("block %d: retained state broken: %v", i, err)
+		}
+	}
+	// The other states are pruned
+	for _, i := range []int{1, 2} {
+		if err := checkState(db, roots[i]); err == nil {
+			t.Errorf("block %d: state not pruned", i)
+		}
+	}
+}
+
+func TestPruneStateHeadMissing(t *testing.T) {
+	db, roots := makeTestChain(t, 3)
+	db.Delete(roots[2].Bytes())
+
+	if _, err := NewPruner(db, 1).Prune(2); err == nil {
+		t.Fatal("pruned with the head state missing")
+	}
+	// Nothing is deleted
+	for _, i := range []int{0, 1} {
+		if err := checkState(db, roots[i]); err != nil {
+			t.Errorf("block %d: state broken: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+)
+
+// The database schema of the snapshot, kept here rather than in rawdb since
+// core/rawdb depends on core/state through the consensus types.
+var (
+	// snapshotRootKey tracks the hash of the last snapshot.
+	snapshotRootKey = []byte("SnapshotRoot")
+
+	// snapshotJournalKey tracks the in-memory diff layers across restarts.
+	snapshotJournalKey = []byte("SnapshotJournal")
+
+	// snapshotGeneratorKey tracks the snapshot generation marker across restarts.
+	snapshotGeneratorKey = []byte("SnapshotGenerator")
+
+	snapshotAccountPrefix = []byte("a") // snapshotAccountPrefix + account hash -> account trie value
+	snapshotStoragePrefix = []byte("o") // snapshotStoragePrefix + account hash + storage hash -> storage trie value
+)
+
+// accountSnapshotKey = snapshotAccountPrefix + hash
+func accountSnapshotKey(hash common.Hash) []byte {
+	return append(append([]byte{}, snapshotAccountPrefix...), hash.Bytes()...)
+}
+
+// storageSnapshotKey = snapshotStoragePrefix + account hash + storage hash
+func storageSnapshotKey(accountHash, storageHash common.Hash) []byte {
+	return append(append(append([]byte{}, snapshotStoragePrefix...), accountHash.Bytes()...), storageHash.Bytes()...)
+}
+
+// storageSnapshotsKey = snapshotStoragePrefix + account hash
+func storageSnapshotsKey(accountHash common.Hash) []byte {
+	return append(append([]byte{}, snapshotStoragePrefix...), accountHash.Bytes()...)
+}
+
+// readSnapshotRoot retrieves the root of the block whose state is contained in
+// the persisted snapshot.
+func readSnapshotRoot(db intdb.Reader) common.Hash {
+	data, _ := db.Get(snapshotRootKey)
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// writeSnapshotRoot stores the root of the block whose state is contained in
+// the persisted snapshot.
+func writeSnapshotRoot(db intdb.Writer, root common.Hash) {
+	if err := db.Put(snapshotRootKey, root[:]); err != nil {
+		log.Crit("Failed to store snapshot root", "err", err)
+	}
+}
+
+// readAccountSnapshot retrieves the snapshot entry of an account trie leaf.
+func readAccountSnapshot(db intdb.Reader, hash common.Hash) []byte {
+	data, _ := db.Get(accountSnapshotKey(hash))
+	return data
+}
+
+// writeAccountSnapshot stores the snapshot entry of an account trie leaf.
+func writeAccountSnapshot(db intdb.Writer, hash common.Hash, entry []byte) {
+	if err := db.Put(accountSnapshotKey(hash), entry); err != nil {
+		log.Crit("Failed to store account snapshot", "err", err)
+	}
+}
+
+// deleteAccountSnapshot removes the snapshot entry of an account trie leaf.
+func deleteAccountSnapshot(db intdb.Writer, hash common.Hash) {
+	if err := db.Delete(accountSnapshotKey(hash)); err != nil {
+		log.Crit("Failed to delete account snapshot", "err", err)
+	}
+}
+
+// readStorageSnapshot retrieves the snapshot entry of a storage trie leaf.
+func readStorageSnapshot(db intdb.Reader, accountHash, storageHash common.Hash) []byte {
+	data, _ := db.Get(storageSnapshotKey(accountHash, storageHash))
+	return data
+}
+
+// writeStorageSnapshot stores the snapshot entry of a storage trie leaf.
+func writeStorageSnapshot(db intdb.Writer, accountHash, storageHash common.Hash, entry []byte) {
+	if err := db.Put(storageSnapshotKey(accountHash, storageHash), entry); err != nil {
+		log.Crit("Failed to store storage snapshot", "err", err)
+	}
+}
+
+// deleteStorageSnapshot removes the snapshot entry of a storage trie leaf.
+func deleteStorageSnapshot(db intdb.Writer, accountHash, storageHash common.Hash) {
+	if err := db.Delete(storageSnapshotKey(accountHash, storageHash)); err != nil {
+		log.Crit("Failed to delete storage snapshot", "err", err)
+	}
+}
+
+// iterateStorageSnapshots returns an iterator for walking the entire storage
+// space of a specific account.
+func iterateStorageSnapshots(db intdb.Iteratee, accountHash common.Hash) intdb.Iterator {
+	return db.NewIteratorWithPrefix(storageSnapshotsKey(accountHash))
+}
+
+// readSnapshotJournal retrieves the serialized in-memory diff layers saved at
+// the last shutdown.
+func readSnapshotJournal(db intdb.Reader) []byte {
+	data, _ := db.Get(snapshotJournalKey)
+	return data
+}
+
+// writeSnapshotJournal stores the serialized in-memory diff layers to save at
+// shutdown.
+func writeSnapshotJournal(db intdb.Writer, journal []byte) {
+	if err := db.Put(snapshotJournalKey, journal); err != nil {
+		log.Crit("Failed to store snapshot journal", "err", err)
+	}
+}
+
+// deleteSnapshotJournal deletes the serialized in-memory diff layers.
+func deleteSnapshotJournal(db intdb.Writer) {
+	if err := db.Delete(snapshotJournalKey); err != nil {
+		log.Crit("Failed to remove snapshot journal", "err", err)
+	}
+}
+
+// readSnapshotGenerator retrieves the serialized progress of the snapshot generation.
+func readSnapshotGenerator(db intdb.Reader) []byte {
+	data, _ := db.Get(snapshotGeneratorKey)
+	return data
+}
+
+// writeSnapshotGenerator stores the serialized progress of the snapshot generation.
+func writeSnapshotGenerator(db intdb.Writer, generator []byte) {
+	if err := db.Put(snapshotGeneratorKey, generator); err != nil {
+		log.Crit("Failed to store snapshot generator", "err", err)
+	}
+}
@@ -0,0 +1,181 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/allegro/bigcache"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/trie"
+)
+
+// diskLayer is a low level persistent snapshot built on top of a key-value store.
+type diskLayer struct {
+	diskdb intdb.KeyValueStore // Key-value store containing the base snapshot
+	triedb *trie.Database      // Trie node cache for reconstruction purposes
+	cache  *layerCache         // Cache to avoid hitting the disk for direct access
+
+	root  common.Hash // Root hash of the base snapshot
+	stale bool        // Signals that the layer became stale (state progressed)
+
+	genMarker  []byte                    // Last account covered by the generator, nil once generated
+	genPending chan struct{}             // Notification channel when generation is done (test synchronicity)
+	genAbort   chan chan *generatorStats // Notification channel to abort generating the snapshot in this layer
+
+	lock sync.RWMutex
+}
+
+// Root returns root hash for which this snapshot was made.
+func (dl *diskLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Parent always returns nil as there's no layer below the disk.
+func (dl *diskLayer) Parent() snapshot {
+	return nil
+}
+
+// Stale return whether this layer has become stale (was flattened across) or if
+// it's still live.
+func (dl *diskLayer) Stale() bool {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.stale
+}
+
+// Account directly retrieves the account trie value associated with a particular
+// hash in the snapshot.
+func (dl *diskLayer) Account(hash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	// If the layer was flattened into, consider it invalid (any live reference to
+	// the original should be marked as unusable).
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	// If the layer is being generated, ensure the requested hash has already been
+	// covered by the generator.
+	if dl.genMarker != nil && bytes.Compare(hash[:], dl.genMarker) > 0 {
+		return nil, ErrNotCoveredYet
+	}
+	// Try to retrieve the account from the memory cache
+	key := string(hash[:])
+	if blob, found := dl.cache.get(key); found {
+		return blob, nil
+	}
+	// Cache doesn't contain account, pull from disk and cache for later
+	blob := readAccountSnapshot(dl.diskdb, hash)
+	dl.cache.set(key, blob)
+	return blob, nil
+}
+
+// Storage directly retrieves the storage trie value associated with a particular
+// hash within a particular account.
+func (dl *diskLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	// If the layer was flattened into, consider it invalid (any live reference to
+	// the original should be marked as unusable).
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	// The storage of an account is generated along with the account
+	if dl.genMarker != nil && bytes.Compare(accountHash[:], dl.genMarker) > 0 {
+		return nil, ErrNotCoveredYet
+	}
+	// Try to retrieve the storage slot from the memory cache
+	key := string(append(accountHash[:], storageHash[:]...))
+	if blob, found := dl.cache.get(key); found {
+		return blob, nil
+	}
+	// Cache doesn't contain storage slot, pull from disk and cache for later
+	blob := readStorageSnapshot(dl.diskdb, accountHash, storageHash)
+	dl.cache.set(key, blob)
+	return blob, nil
+}
+
+// Update creates a new layer on top of the existing snapshot diff tree with
+// the specified data items. Note, the maps are retained by the method to avoid
+// copying everything.
+func (dl *diskLayer) Update(blockHash common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer {
+	return newDiffLayer(dl, blockHash, destructs, accounts, storage)
+}
+
+// stopGeneration aborts the generator running on the layer, if any, returning
+// the generation statistics to continue with.
+func (dl *diskLayer) stopGeneration() *generatorStats {
+	if dl.genAbort == nil {
+		return nil
+	}
+	abort := make(chan *generatorStats)
+	dl.genAbort <- abort
+	dl.genAbort = nil
+	return <-abort
+}
+
+// layerCache is the memory cache of the disk layer entries, the missing entries
+// are cached as empty values. A nil cache caches nothing.
+type layerCache struct {
+	cache *bigcache.BigCache
+}
+
+// newLayerCache creates a cache of the given size in megabytes.
+func newLayerCache(size int) *layerCache {
+	if size <= 0 {
+		return &layerCache{}
+	}
+	cache, _ := bigcache.NewBigCache(bigcache.Config{
+		Shards:             1024,
+		LifeWindow:         time.Hour,
+		MaxEntriesInWindow: size * 1024,
+		MaxEntrySize:       512,
+		HardMaxCacheSize:   size,
+		Hasher:             entryHasher{},
+	})
+	return &layerCache{cache: cache}
+}
+
+// get retrieves the entry, a missing entry cached is returned as a nil blob.
+func (c *layerCache) get(key string) ([]byte, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	blob, err := c.cache.Get(key)
+	if err != nil {
+		return nil, false
+	}
+	if len(blob) == 0 {
+		return nil, true
+	}
+	return blob, true
+}
+
+// set caches the entry, a nil blob caches the entry as missing.
+func (c *layerCache) set(key string, blob []byte) {
+	if c.cache != nil {
+		c.cache.Set(key, blob)
+	}
+}
+
+// remove drops the entry from the cache.
+func (c *layerCache) remove(key string) {
+	if c.cache != nil {
+		c.cache.Delete(key)
+	}
+}
+
+// entryHasher is a struct to be used with BigCache, which uses a Hasher to
+// determine which shard to go to. The keys end with a hash, whose last bytes
+// are random already.
+type entryHasher struct{}
+
+// Sum64 implements the bigcache.Hasher interface.
+func (entryHasher) Sum64(key string) uint64 {
+	return binary.BigEndian.Uint64([]byte(key[len(key)-8:]))
+}
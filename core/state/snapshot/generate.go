@@ -0,0 +1,265 @@
+package snapshot
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/rlp"
+	"github.com/intfoundation/intchain/trie"
+)
+
+// generatorStats is a collection of statistics gathered by the snapshot generator
+// for logging purposes.
+type generatorStats struct {
+	start    time.Time          // Timestamp when generation started
+	accounts uint64             // Number of accounts indexed
+	slots    uint64             // Number of storage slots indexed
+	storage  common.StorageSize // Account and storage slot size
+}
+
+// log creates an contextual log with the given message and the context pulled
+// from the internally maintained statistics.
+func (gs *generatorStats) log(msg string, root common.Hash, marker []byte) {
+	ctx := []interface{}{
+		"root", root, "accounts", gs.accounts, "slots", gs.slots,
+		"storage", gs.storage, "elapsed", common.PrettyDuration(time.Since(gs.start)),
+	}
+	if len(marker) > 0 {
+		ctx = append(ctx, "at", common.BytesToHash(marker))
+	}
+	log.Info(msg, ctx...)
+}
+
+// generatorProgress is the persisted progress of the snapshot generation.
+type generatorProgress struct {
+	Done     bool   // Whether the generator finished creating the snapshot
+	Marker   []byte // Last account hash generated
+	Accounts uint64
+	Slots    uint64
+	Storage  uint64
+}
+
+// journalProgress persists the generator progress into the database, a nil marker
+// means the generation is completed.
+func journalProgress(db intdb.Writer, marker []byte, stats *generatorStats) {
+	progress := generatorProgress{
+		Done:   marker == nil,
+		Marker: marker,
+	}
+	if stats != nil {
+		progress.Accounts = stats.accounts
+		progress.Slots = stats.slots
+		progress.Storage = uint64(stats.storage)
+	}
+	blob, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		panic(err) // Cannot happen, here to catch dev errors
+	}
+	writeSnapshotGenerator(db, blob)
+}
+
+// generateSnapshot regenerates a brand new snapshot based on an existing state
+// database and head block asynchronously. The snapshot is returned immediately
+// and generation is continued in the background until done.
+func generateSnapshot(diskdb intdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash) *diskLayer {
+	// Wipe any previously existing snapshot from the database
+	if err := wipeSnapshot(diskdb); err != nil {
+		log.Crit("Failed to wipe state snapshot", "err", err)
+	}
+	// Create a new disk layer with an initialized state marker at zero
+	batch := diskdb.NewBatch()
+	deleteSnapshotJournal(batch)
+	writeSnapshotRoot(batch, root)
+	journalProgress(batch, []byte{}, nil)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write initialized state marker", "err", err)
+	}
+	base := &diskLayer{
+		diskdb:     diskdb,
+		triedb:     triedb,
+		root:       root,
+		cache:      newLayerCache(cache),
+		genMarker:  []byte{}, // Initialized but empty!
+		genPending: make(chan struct{}),
+		genAbort:   make(chan chan *generatorStats),
+	}
+	go base.generate(&generatorStats{start: time.Now()})
+	return base
+}
+
+// wipeSnapshot deletes all the account and storage snapshot entries.
+func wipeSnapshot(db intdb.KeyValueStore) error {
+	start := time.Now()
+	for _, prefix := range []struct {
+		prefix []byte
+		length int
+	}{
+		{snapshotAccountPrefix, len(snapshotAccountPrefix) + common.HashLength},
+		{snapshotStoragePrefix, len(snapshotStoragePrefix) + 2*common.HashLength},
+	} {
+		if err := wipeKeyRange(db, prefix.prefix, prefix.length); err != nil {
+			return err
+		}
+	}
+	log.Info("Deleted state snapshot leftovers", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// wipeKeyRange deletes the keys of the given length with the given prefix, the
+// other keys of the prefix belong to other data, e.g. the trie nodes.
+func wipeKeyRange(db intdb.KeyValueStore, prefix []byte, length int) error {
+	batch := db.NewBatch()
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != length {
+			continue
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() > intdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// generate is a background thread that iterates over the state and storage tries,
+// constructing the state snapshot. All the arguments are purely for statistics
+// gathering and logging, since the method surfs the blocks as they arrive, often
+// being restarted.
+func (dl *diskLayer) generate(stats *generatorStats) {
+	// The generator may be restarted without statistics after a restart
+	if stats == nil {
+		stats = &generatorStats{start: time.Now()}
+	}
+	dl.lock.RLock()
+	marker := dl.genMarker
+	dl.lock.RUnlock()
+
+	// Create an account and state iterator pointing to the current generator marker.
+	// A missing trie node waits for the next disk layer, the tries of the older
+	// blocks are released or pruned while the newer ones are retained.
+	accTrie, err := trie.NewSecure(dl.root, dl.triedb)
+	if err != nil {
+		log.Warn("Snapshot generator failed to open the state", "root", dl.root, "err", err)
+		abort := <-dl.genAbort
+		abort <- stats
+		return
+	}
+	stats.log("Resuming state snapshot generation", dl.root, marker)
+
+	var (
+		batch  = dl.diskdb.NewBatch()
+		logged = time.Now()
+		it     = trie.NewIterator(accTrie.NodeIterator(marker))
+	)
+	for it.Next() {
+		// The marker account is generated already
+		if len(marker) > 0 && bytes.Equal(it.Key, marker) {
+			continue
+		}
+		accountHash := common.BytesToHash(it.Key)
+
+		// The non-account entries of the account trie are not snapshotted
+		if root, ok := accountStorageRoot(it.Value); ok {
+			if err := dl.generateAccount(batch, accountHash, root, it.Value, stats); err != nil {
+				log.Warn("Snapshot generator failed to generate the account", "root", dl.root, "account", accountHash, "err", err)
+				abort := <-dl.genAbort
+				abort <- stats
+				return
+			}
+		}
+		// The progress is persisted once the batch is flushed, at account boundaries,
+		// so the entries written are always covered by the persisted marker
+		if batch.ValueSize() > intdb.IdealBatchSize {
+			journalProgress(batch, accountHash[:], stats)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to write snapshot", "err", err)
+			}
+			batch.Reset()
+
+			dl.lock.Lock()
+			dl.genMarker = accountHash[:]
+			dl.lock.Unlock()
+
+			if time.Since(logged) > 8*time.Second {
+				stats.log("Generating state snapshot", dl.root, accountHash[:])
+				logged = time.Now()
+			}
+			// Abort the generation if the disk layer moved, it resumes on the new layer
+			select {
+			case abort := <-dl.genAbort:
+				abort <- stats
+				return
+			default:
+			}
+		}
+	}
+	if it.Err != nil {
+		log.Warn("Snapshot generator failed to iterate the state", "root", dl.root, "err", it.Err)
+		abort := <-dl.genAbort
+		abort <- stats
+		return
+	}
+	// Snapshot fully generated, set the marker to nil
+	journalProgress(batch, nil, stats)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write snapshot", "err", err)
+	}
+	log.Info("Generated state snapshot", "accounts", stats.accounts, "slots", stats.slots,
+		"storage", stats.storage, "elapsed", common.PrettyDuration(time.Since(stats.start)))
+
+	dl.lock.Lock()
+	dl.genMarker = nil
+	if dl.genPending != nil {
+		close(dl.genPending)
+	}
+	dl.lock.Unlock()
+
+	// Someone will be looking for us, wait it out
+	abort := <-dl.genAbort
+	abort <- nil
+}
+
+// generateAccount writes the snapshot entries of the account and its storage.
+func (dl *diskLayer) generateAccount(batch intdb.Batch, accountHash, root common.Hash, blob []byte, stats *generatorStats) error {
+	writeAccountSnapshot(batch, accountHash, blob)
+	stats.storage += common.StorageSize(1 + common.HashLength + len(blob))
+	stats.accounts++
+
+	// The entries left by an aborted generation may be stale
+	it := iterateStorageSnapshots(dl.diskdb, accountHash)
+	for it.Next() {
+		if len(it.Key()) == len(snapshotStoragePrefix)+2*common.HashLength {
+			batch.Delete(common.CopyBytes(it.Key()))
+		}
+	}
+	it.Release()
+
+	if root == emptyRoot || root == (common.Hash{}) {
+		return nil
+	}
+	storeTrie, err := trie.NewSecure(root, dl.triedb)
+	if err != nil {
+		return err
+	}
+	storeIt := trie.NewIterator(storeTrie.NodeIterator(nil))
+	for storeIt.Next() {
+		writeStorageSnapshot(batch, accountHash, common.BytesToHash(storeIt.Key), storeIt.Value)
+		stats.storage += common.StorageSize(1 + 2*common.HashLength + len(storeIt.Value))
+		stats.slots++
+	}
+	return storeIt.Err
+}
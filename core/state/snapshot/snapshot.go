@@ -0,0 +1,443 @@
+// Package snapshot implements a flat snapshot of the accounts and storage slots
+// of the state, maintained alongside the trie so the reads of the recent states
+// do not walk the trie.
+//
+// The snapshot is a tree of layers: the disk layer holds the flat state of a block
+// in the database, and every block imported on top adds an in-memory diff layer
+// with the accounts and storage slots it changed. Once the diff layers exceed the
+// retention, the oldest ones are flattened into the disk layer. The diff layers are
+// journalled at shutdown and restored at the next start.
+//
+// Only the account trie and the storage tries are flattened. The tx1, tx3, proxied
+// and reward tries of the IntChain accounts are still read from the trie, along with
+// the non-account entries of the account trie, such as the delegate refund set.
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/rlp"
+	"github.com/intfoundation/intchain/trie"
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// ErrSnapshotStale is returned from data accessors if the underlying snapshot
+	// layer had been invalidated due to the chain progressing forward far enough
+	// to not maintain the layer's original state.
+	ErrSnapshotStale = errors.New("snapshot stale")
+
+	// ErrNotCoveredYet is returned from data accessors if the underlying snapshot
+	// is being generated currently and the requested data item is not yet in the
+	// range of accounts covered.
+	ErrNotCoveredYet = errors.New("not covered yet")
+
+	// errSnapshotCycle is returned if a snapshot is attempted to be inserted
+	// that forms a cycle in the snapshot tree.
+	errSnapshotCycle = errors.New("snapshot cycle")
+)
+
+// Snapshot represents the functionality supported by a snapshot storage layer.
+type Snapshot interface {
+	// Root returns the root hash for which this snapshot was made.
+	Root() common.Hash
+
+	// Account directly retrieves the account trie value associated with a particular
+	// hash in the snapshot. A nil value means the account does not exist.
+	Account(hash common.Hash) ([]byte, error)
+
+	// Storage directly retrieves the storage trie value associated with a particular
+	// hash within a particular account. A nil value means the slot is empty.
+	Storage(accountHash, storageHash common.Hash) ([]byte, error)
+}
+
+// snapshot is the internal version of the snapshot data layer that supports some
+// additional methods compared to the public API.
+type snapshot interface {
+	Snapshot
+
+	// Parent returns the subsequent layer of a snapshot, or nil if the base was
+	// reached.
+	Parent() snapshot
+
+	// Update creates a new layer on top of the existing snapshot diff tree with
+	// the specified data items.
+	Update(blockRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer
+
+	// Journal commits an entire diff hierarchy to disk into a single journal entry.
+	// This is meant to be used during shutdown to persist the snapshot without
+	// flattening everything down (bad for reorgs).
+	Journal(buffer *bytes.Buffer) (common.Hash, error)
+
+	// Stale return whether this layer has become stale (was flattened across) or
+	// if it's still live.
+	Stale() bool
+}
+
+// Tree is an IntChain state snapshot tree. It consists of one persistent base
+// layer backed by a key-value store, on top of which arbitrarily many in-memory
+// diff layers are topped. The memory diffs can form a tree with branching, but
+// the disk layer is singleton and common to all. If a reorg goes deeper than the
+// disk layer, everything needs to be deleted.
+type Tree struct {
+	diskdb intdb.KeyValueStore      // Persistent database to store the snapshot
+	triedb *trie.Database           // In-memory cache to access the trie through
+	cache  int                      // Megabytes permitted to use for read caches
+	layers map[common.Hash]snapshot // Collection of all known layers
+	lock   sync.RWMutex
+}
+
+// New attempts to load an already existing snapshot from a persistent key-value
+// store (with a number of memory layers from a journal), ensuring that the head
+// of the snapshot matches the expected one.
+//
+// If the snapshot is missing or inconsistent, the entirety is deleted and will
+// be reconstructed from scratch based on the tries in the key-value store, on a
+// background thread.
+func New(diskdb intdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash) *Tree {
+	snap := &Tree{
+		diskdb: diskdb,
+		triedb: triedb,
+		cache:  cache,
+		layers: make(map[common.Hash]snapshot),
+	}
+	head, err := loadSnapshot(diskdb, triedb, cache, root)
+	if err != nil {
+		log.Warn("Failed to load snapshot, regenerating", "err", err)
+		snap.Rebuild(root)
+		return snap
+	}
+	// Existing snapshot loaded, seed all the layers
+	for head != nil {
+		snap.layers[head.Root()] = head
+		head = head.Parent()
+	}
+	return snap
+}
+
+// Snapshot retrieves a snapshot belonging to the given block root, or nil if no
+// snapshot is maintained for that block.
+func (t *Tree) Snapshot(blockRoot common.Hash) Snapshot {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if snap, ok := t.layers[blockRoot]; ok {
+		return snap
+	}
+	return nil
+}
+
+// DiskRoot returns the root of the block whose state is held by the disk layer,
+// along with whether the disk layer is still being generated from its trie.
+func (t *Tree) DiskRoot() (common.Hash, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if base := t.disklayer(); base != nil {
+		base.lock.RLock()
+		defer base.lock.RUnlock()
+		return base.root, base.genMarker != nil
+	}
+	return common.Hash{}, false
+}
+
+// Update adds a new snapshot into the tree, if that can be linked to an existing
+// old parent. It is disallowed to insert a disk layer (the origin of all).
+func (t *Tree) Update(blockRoot common.Hash, parentRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) error {
+	// Reject noop updates to avoid self-loops in the snapshot tree. This is a
+	// special case that can only happen for blocks without any state change.
+	if blockRoot == parentRoot {
+		return errSnapshotCycle
+	}
+	// Generate a new snapshot on top of the parent
+	parent := t.Snapshot(parentRoot)
+	if parent == nil {
+		return fmt.Errorf("parent [%#x] snapshot missing", parentRoot)
+	}
+	snap := parent.(snapshot).Update(blockRoot, destructs, accounts, storage)
+
+	// Save the new snapshot for later
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.layers[snap.root] = snap
+	return nil
+}
+
+// Cap traverses downwards the snapshot tree from a head block hash until the
+// number of allowed layers are crossed. All layers beyond the permitted number
+// are flattened downwards.
+func (t *Tree) Cap(root common.Hash, layers int) error {
+	// Retrieve the head snapshot to cap from
+	snap := t.Snapshot(root)
+	if snap == nil {
+		return fmt.Errorf("snapshot [%#x] missing", root)
+	}
+	diff, ok := snap.(*diffLayer)
+	if !ok {
+		return fmt.Errorf("snapshot [%#x] is disk layer", root)
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Flattening everything into the disk layer is a special case
+	if layers == 0 {
+		base := diffToDisk(diff.flatten().(*diffLayer))
+		t.layers = map[common.Hash]snapshot{base.root: base}
+		return nil
+	}
+	if !t.cap(diff, layers) {
+		return nil
+	}
+	// Remove any layer that is stale or links into a stale layer
+	children := make(map[common.Hash][]common.Hash)
+	for root, snap := range t.layers {
+		if diff, ok := snap.(*diffLayer); ok {
+			parent := diff.Parent().Root()
+			children[parent] = append(children[parent], root)
+		}
+	}
+	var remove func(root common.Hash)
+	remove = func(root common.Hash) {
+		delete(t.layers, root)
+		for _, child := range children[root] {
+			remove(child)
+		}
+		delete(children, root)
+	}
+	for root, snap := range t.layers {
+		if snap.Stale() {
+			remove(root)
+		}
+	}
+	return nil
+}
+
+// cap traverses downwards the diff tree until the number of allowed layers are
+// crossed, then flattens the layers below into the disk layer. It returns whether
+// the disk layer was replaced.
+//
+// Note, the tree lock must be held by the caller.
+func (t *Tree) cap(diff *diffLayer, layers int) bool {
+	// Dive until we run out of layers or reach the persistent database
+	for ; layers > 1; layers-- {
+		parent, ok := diff.Parent().(*diffLayer)
+		if !ok {
+			return false
+		}
+		diff = parent
+	}
+	// The layers below the last retained one are flattened and persisted
+	bottom, ok := diff.Parent().(*diffLayer)
+	if !ok {
+		return false
+	}
+	base := diffToDisk(bottom.flatten().(*diffLayer))
+
+	diff.lock.Lock()
+	diff.parent = base
+	diff.lock.Unlock()
+
+	t.layers[base.root] = base
+	return true
+}
+
+// Journal commits an entire diff hierarchy to disk into a single journal entry.
+// This is meant to be used during shutdown to persist the snapshot without
+// flattening everything down (bad for reorgs). It returns the root of the disk
+// layer, whose trie must be persisted along if the generation is not completed.
+func (t *Tree) Journal(root common.Hash) (common.Hash, error) {
+	// Retrieve the head snapshot to journal from
+	snap := t.Snapshot(root)
+	if snap == nil {
+		return common.Hash{}, fmt.Errorf("snapshot [%#x] missing", root)
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	journal := new(bytes.Buffer)
+	base, err := snap.(snapshot).Journal(journal)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	writeSnapshotJournal(t.diskdb, journal.Bytes())
+	return base, nil
+}
+
+// Rebuild wipes all available snapshot data from the persistent database and
+// discard all caches and diff layers. Afterwards, it starts a new snapshot
+// generator with the given root hash.
+func (t *Tree) Rebuild(root common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Track whether there's a wipe currently running and keep it alive if so
+	for _, layer := range t.layers {
+		switch layer := layer.(type) {
+		case *diskLayer:
+			// If the base layer is generating, abort it and save
+			layer.stopGeneration()
+			layer.lock.Lock()
+			layer.stale = true
+			layer.lock.Unlock()
+
+		case *diffLayer:
+			layer.lock.Lock()
+			layer.stale = true
+			layer.lock.Unlock()
+
+		default:
+			panic(fmt.Sprintf("unknown layer type: %T", layer))
+		}
+	}
+	// Start generating a new snapshot from scratch on a background thread
+	log.Info("Rebuilding state snapshot", "root", root)
+	t.layers = map[common.Hash]snapshot{
+		root: generateSnapshot(t.diskdb, t.triedb, t.cache, root),
+	}
+}
+
+// disklayer is an internal helper function to return the disk layer.
+//
+// Note, the tree lock must be held by the caller.
+func (t *Tree) disklayer() *diskLayer {
+	for _, layer := range t.layers {
+		for {
+			if base, ok := layer.(*diskLayer); ok {
+				return base
+			}
+			layer = layer.Parent()
+		}
+	}
+	return nil
+}
+
+// diffToDisk merges a bottom-most diff into the persistent disk layer underneath
+// it. The method will panic if called onto a non-bottom-most diff layer.
+func diffToDisk(bottom *diffLayer) *diskLayer {
+	var (
+		base  = bottom.Parent().(*diskLayer)
+		batch = base.diskdb.NewBatch()
+	)
+	// If the disk layer is running a snapshot generator, abort it
+	stats := base.stopGeneration()
+
+	// Mark the original base as stale as we're going to create a new wrapper
+	base.lock.Lock()
+	if base.stale {
+		panic("parent disk layer is stale") // we've committed into the same base from two children, boo
+	}
+	base.stale = true
+	marker := base.genMarker
+	base.lock.Unlock()
+
+	// The entries beyond the generation marker are generated from the new root later
+	covered := func(hash common.Hash) bool {
+		return marker == nil || bytes.Compare(hash[:], marker) <= 0
+	}
+	// Destroy all the destructed accounts from the database
+	for hash := range bottom.destructSet {
+		if !covered(hash) {
+			continue
+		}
+		deleteAccountSnapshot(batch, hash)
+		base.cache.set(string(hash[:]), nil)
+
+		it := iterateStorageSnapshots(base.diskdb, hash)
+		for it.Next() {
+			key := it.Key()
+			if len(key) != len(snapshotStoragePrefix)+2*common.HashLength {
+				continue
+			}
+			batch.Delete(common.CopyBytes(key))
+			base.cache.remove(string(key[len(snapshotStoragePrefix):]))
+		}
+		it.Release()
+		batch = flushBatch(batch)
+	}
+	// Push all updated accounts into the database
+	for hash, data := range bottom.accountData {
+		if !covered(hash) {
+			continue
+		}
+		if len(data) > 0 {
+			writeAccountSnapshot(batch, hash, data)
+		} else {
+			deleteAccountSnapshot(batch, hash)
+		}
+		base.cache.set(string(hash[:]), data)
+		batch = flushBatch(batch)
+	}
+	// Push all the storage slots into the database
+	for accountHash, storage := range bottom.storageData {
+		if !covered(accountHash) {
+			continue
+		}
+		for storageHash, data := range storage {
+			if len(data) > 0 {
+				writeStorageSnapshot(batch, accountHash, storageHash, data)
+			} else {
+				deleteStorageSnapshot(batch, accountHash, storageHash)
+			}
+			base.cache.set(string(append(accountHash[:], storageHash[:]...)), data)
+		}
+		batch = flushBatch(batch)
+	}
+	// Update the snapshot block marker and write any remainder data
+	writeSnapshotRoot(batch, bottom.root)
+	if marker != nil {
+		journalProgress(batch, marker, stats)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write leftover snapshot", "err", err)
+	}
+	res := &diskLayer{
+		root:       bottom.root,
+		cache:      base.cache,
+		diskdb:     base.diskdb,
+		triedb:     base.triedb,
+		genMarker:  marker,
+		genPending: base.genPending,
+	}
+	// If snapshot generation hasn't finished yet, port over all the starts and
+	// continue where the previous round left off.
+	if marker != nil {
+		res.genAbort = make(chan chan *generatorStats)
+		go res.generate(stats)
+	}
+	return res
+}
+
+// flushBatch writes the batch once it grows large enough, returning the batch to
+// continue with.
+func flushBatch(batch intdb.Batch) intdb.Batch {
+	if batch.ValueSize() > intdb.IdealBatchSize {
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to write snapshot", "err", err)
+		}
+		batch.Reset()
+	}
+	return batch
+}
+
+// accountStorageRoot returns the storage root of an account trie value, the sixth
+// field of the account. It returns false for the non-account entries of the trie.
+func accountStorageRoot(blob []byte) (common.Hash, bool) {
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(blob, &fields); err != nil || len(fields) < 6 {
+		return common.Hash{}, false
+	}
+	var root common.Hash
+	if err := rlp.DecodeBytes(fields[5], &root); err != nil {
+		return common.Hash{}, false
+	}
+	return root, true
+}
@@ -0,0 +1,218 @@
+package snapshot
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/crypto"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/intdb/memorydb"
+	"github.com/intfoundation/intchain/rlp"
+	"github.com/intfoundation/intchain/trie"
+)
+
+// testAccount has the leading fields of the state account, up to the storage root.
+type testAccount struct {
+	Nonce                    uint64
+	Balance                  *big.Int
+	DepositBalance           *big.Int
+	ChildChainDepositBalance []common.Hash
+	ChainBalance             *big.Int
+	Root                     common.Hash
+}
+
+// makeTestState creates a state of accounts, the odd ones with a storage slot,
+// along with a non-account entry in the account trie.
+func makeTestState(t *testing.T) (intdb.KeyValueStore, *trie.Database, common.Hash) {
+	diskdb := memorydb.New()
+	triedb := trie.NewDatabase(diskdb)
+
+	accTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+	for i := byte(0); i < 32; i++ {
+		account := testAccount{Nonce: uint64(i), Balance: big.NewInt(int64(i)), DepositBalance: new(big.Int), ChainBalance: new(big.Int), Root: emptyRoot}
+		if i%2 == 1 {
+			storeTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+			storeTrie.Update(common.Hash{i}.Bytes(), []byte{i})
+			root, err := storeTrie.Commit(nil)
+			if err != nil {
+				t.Fatalf("failed to commit storage: %v", err)
+			}
+			account.Root = root
+		}
+		blob, _ := rlp.EncodeToBytes(&account)
+		accTrie.Update(common.BytesToAddress([]byte{i}).Bytes(), blob)
+	}
+	accTrie.Update([]byte("RefundSet"), []byte{0x01})
+
+	root, err := accTrie.Commit(nil)
+	if err != nil {
+		t.Fatalf("failed to commit accounts: %v", err)
+	}
+	if err := triedb.Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	return diskdb, triedb, root
+}
+
+// waitGeneration waits for the disk layer of the tree to be generated.
+func waitGeneration(tree *Tree) {
+	tree.lock.RLock()
+	base := tree.disklayer()
+	tree.lock.RUnlock()
+
+	if base.genPending != nil {
+		<-base.genPending
+	}
+}
+
+func accountHash(i byte) common.Hash {
+	return crypto.Keccak256Hash(common.BytesToAddress([]byte{i}).Bytes())
+}
+
+func TestGenerateSnapshot(t *testing.T) {
+	diskdb, triedb, root := makeTestState(t)
+
+	tree := New(diskdb, triedb, 16, root)
+	waitGeneration(tree)
+
+	if base, generating := tree.DiskRoot(); base != root || generating {
+		t.Fatalf("disk root mismatch: have %x, generating %v", base, generating)
+	}
+	accTrie, _ := trie.NewSecure(root, triedb)
+	snap := tree.Snapshot(root)
+	for i := byte(0); i < 32; i++ {
+		blob, err := snap.Account(accountHash(i))
+		if err != nil {
+			t.Fatalf("account %d: failed to read: %v", i, err)
+		}
+		if want := accTrie.Get(common.BytesToAddress([]byte{i}).Bytes()); !bytes.Equal(blob, want) {
+			t.Fatalf("account %d: mismatch: have %x, want %x", i, blob, want)
+		}
+		slot, err := snap.Storage(accountHash(i), crypto.Keccak256Hash(common.Hash{i}.Bytes()))
+		if err != nil {
+			t.Fatalf("account %d: failed to read storage: %v", i, err)
+		}
+		if i%2 == 1 && !bytes.Equal(slot, []byte{i}) || i%2 == 0 && slot != nil {
+			t.Fatalf("account %d: storage mismatch: %x", i, slot)
+		}
+	}
+	// The non-account entry is not snapshotted
+	if blob, _ := snap.Account(crypto.Keccak256Hash([]byte("RefundSet"))); blob != nil {
+		t.Fatalf("non-account entry snapshotted: %x", blob)
+	}
+}
+
+func TestDiffLayers(t *testing.T) {
+	diskdb, triedb, root := makeTestState(t)
+
+	tree := New(diskdb, triedb, 16, root)
+	waitGeneration(tree)
+
+	// Stack two diff layers, the first deletes an account, the second updates one
+	var (
+		root1 = common.Hash{0x01}
+		root2 = common.Hash{0x02}
+		slot  = crypto.Keccak256Hash(common.Hash{1}.Bytes())
+	)
+	if err := tree.Update(root1, root, map[common.Hash]struct{}{accountHash(1): {}}, nil, nil); err != nil {
+		t.Fatalf("failed to add layer: %v", err)
+	}
+	accounts := map[common.Hash][]byte{accountHash(2): {0x02}}
+	storage := map[common.Hash]map[common.Hash][]byte{accountHash(3): {slot: {0x03}}}
+	if err := tree.Update(root2, root1, nil, accounts, storage); err != nil {
+		t.Fatalf("failed to add layer: %v", err)
+	}
+	if err := tree.Update(root2, root2, nil, nil, nil); err != errSnapshotCycle {
+		t.Fatalf("cycle error mismatch: have %v, want %v", err, errSnapshotCycle)
+	}
+	check := func(snap Snapshot) {
+		if blob, _ := snap.Account(accountHash(1)); blob != nil {
+			t.Fatalf("deleted account present: %x", blob)
+		}
+		if blob, _ := snap.Storage(accountHash(1), crypto.Keccak256Hash(common.Hash{1}.Bytes())); blob != nil {
+			t.Fatalf("deleted account storage present: %x", blob)
+		}
+		if blob, _ := snap.Account(accountHash(2)); !bytes.Equal(blob, []byte{0x02}) {
+			t.Fatalf("updated account mismatch: %x", blob)
+		}
+		if blob, _ := snap.Storage(accountHash(3), slot); !bytes.Equal(blob, []byte{0x03}) {
+			t.Fatalf("updated storage mismatch: %x", blob)
+		}
+	}
+	check(tree.Snapshot(root2))
+
+	// Flatten the first layer into the disk, the old disk layer becomes stale
+	old := tree.Snapshot(root)
+	if err := tree.Cap(root2, 1); err != nil {
+		t.Fatalf("failed to cap: %v", err)
+	}
+	if _, err := old.Account(accountHash(0)); err != ErrSnapshotStale {
+		t.Fatalf("stale layer error mismatch: have %v, want %v", err, ErrSnapshotStale)
+	}
+	if tree.Snapshot(root) != nil {
+		t.Fatalf("stale layer retained")
+	}
+	if base, _ := tree.DiskRoot(); base != root1 {
+		t.Fatalf("disk root mismatch: have %x, want %x", base, root1)
+	}
+	check(tree.Snapshot(root2))
+
+	// Flatten everything, the disk holds the whole state
+	if err := tree.Cap(root2, 0); err != nil {
+		t.Fatalf("failed to cap: %v", err)
+	}
+	check(tree.Snapshot(root2))
+	if blob := readAccountSnapshot(diskdb, accountHash(2)); !bytes.Equal(blob, []byte{0x02}) {
+		t.Fatalf("persisted account mismatch: %x", blob)
+	}
+	if blob := readStorageSnapshot(diskdb, accountHash(1), crypto.Keccak256Hash(common.Hash{1}.Bytes())); blob != nil {
+		t.Fatalf("deleted account storage persisted: %x", blob)
+	}
+}
+
+func TestJournal(t *testing.T) {
+	diskdb, triedb, root := makeTestState(t)
+
+	tree := New(diskdb, triedb, 16, root)
+	waitGeneration(tree)
+
+	var (
+		root1 = common.Hash{0x01}
+		root2 = common.Hash{0x02}
+	)
+	tree.Update(root1, root, map[common.Hash]struct{}{accountHash(1): {}}, nil, nil)
+	tree.Update(root2, root1, nil, map[common.Hash][]byte{accountHash(2): {0x02}}, nil)
+
+	base, err := tree.Journal(root2)
+	if err != nil {
+		t.Fatalf("failed to journal: %v", err)
+	}
+	if base != root {
+		t.Fatalf("journalled disk root mismatch: have %x, want %x", base, root)
+	}
+	// Reload the snapshot, the diff layers are restored from the journal
+	tree = New(diskdb, triedb, 16, root2)
+	for _, r := range []common.Hash{root, root1, root2} {
+		if tree.Snapshot(r) == nil {
+			t.Fatalf("layer %x not restored", r)
+		}
+	}
+	snap := tree.Snapshot(root2)
+	if blob, _ := snap.Account(accountHash(1)); blob != nil {
+		t.Fatalf("deleted account present: %x", blob)
+	}
+	if blob, _ := snap.Account(accountHash(2)); !bytes.Equal(blob, []byte{0x02}) {
+		t.Fatalf("updated account mismatch: %x", blob)
+	}
+	// A journal of another head is discarded and the snapshot regenerated
+	tree = New(diskdb, triedb, 16, root)
+	waitGeneration(tree)
+	if tree.Snapshot(root2) != nil {
+		t.Fatalf("layer of another head restored")
+	}
+	if blob, _ := tree.Snapshot(root).Account(accountHash(2)); bytes.Equal(blob, []byte{0x02}) {
+		t.Fatalf("diff layer data persisted")
+	}
+}
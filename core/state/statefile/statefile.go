@@ -0,0 +1,313 @@
+// Package statefile implements the portable state file, a checksummed copy of the
+// full state of a block, used to bootstrap a node at that block without copying
+// the data directory.
+//
+// The file is a stream of RLP encoded items: the file header carrying the block,
+// the raw entries of the epoch db, the trie nodes and contract codes of the state,
+// and finally the SHA256 checksum of all the previous items.
+package statefile
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/crypto"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/rlp"
+)
+
+const (
+	fileMagic   = "INTSTATE"
+	fileVersion = 1
+
+	// logInterval is the time between the progress logs.
+	logInterval = 8 * time.Second
+)
+
+// The kinds of the file items, in the order of the file.
+const (
+	itemHeader = iota
+	itemEpoch
+	itemState
+	itemChecksum
+)
+
+var (
+	errInvalidFile      = errors.New("invalid state file")
+	errChecksumMismatch = errors.New("state file checksum mismatch")
+	errUntrustedBlock   = errors.New("state file block not trusted")
+)
+
+// Entry is a raw key value entry of the epoch db.
+type Entry struct {
+	Key   []byte
+	Value []byte
+}
+
+// Snapshot is the content of a state file besides the state itself.
+type Snapshot struct {
+	ChainID string
+	Block   *types.Block
+	Td      *big.Int
+	Epochs  []Entry
+}
+
+type fileHeader struct {
+	Magic   string
+	Version uint64
+	ChainID string
+	Block   *types.Block
+	Td      *big.Int
+}
+
+type fileItem struct {
+	Kind  uint8
+	Key   []byte
+	Value []byte
+}
+
+// itemWriter writes the items of the file, hashing them into the checksum.
+type itemWriter struct {
+	w        io.Writer
+	checksum hash.Hash
+}
+
+func (w *itemWriter) write(kind uint8, key, value []byte) error {
+	enc, err := rlp.EncodeToBytes(&fileItem{Kind: kind, Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	if kind != itemChecksum {
+		w.checksum.Write(enc)
+	}
+	_, err = w.w.Write(enc)
+	return err
+}
+
+// Export writes the state of the snapshot block into the state file. It returns
+// the number of the trie nodes and contract codes exported.
+func Export(w io.Writer, db intdb.Database, snap *Snapshot) (int, error) {
+	header, err := rlp.EncodeToBytes(&fileHeader{
+		Magic:   fileMagic,
+		Version: fileVersion,
+		ChainID: snap.ChainID,
+		Block:   snap.Block,
+		Td:      snap.Td,
+	})
+	if err != nil {
+		return 0, err
+	}
+	bw := bufio.NewWriter(w)
+	iw := &itemWriter{w: bw, checksum: sha256.New()}
+	if err := iw.write(itemHeader, nil, header); err != nil {
+		return 0, err
+	}
+	for _, entry := range snap.Epochs {
+		if err := iw.write(itemEpoch, entry.Key, entry.Value); err != nil {
+			return 0, err
+		}
+	}
+
+	var (
+		count  int
+		logged = time.Now()
+		sdb    = state.NewDatabase(db)
+	)
+	err = state.NewStateWalker(sdb, false).Walk(snap.Block.Root(), func(hash common.Hash, code bool) (bool, error) {
+		// The contract codes are stored by hash along with the trie nodes
+		blob, err := sdb.TrieDB().Node(hash)
+		if err != nil {
+			return false, err
+		}
+		if err := iw.write(itemState, hash.Bytes(), blob); err != nil {
+			return false, err
+		}
+		count++
+		if time.Since(logged) > logInterval {
+			log.Info("Exporting state", "entries", count)
+			logged = time.Now()
+		}
+		return true, nil
+	})
+	if err != nil {
+		return count, err
+	}
+	if err := iw.write(itemChecksum, nil, iw.checksum.Sum(nil)); err != nil {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+// Import reads the state file, writing the state into the database. The block of
+// the file must match the trusted block hash, the epoch of the block must match the
+// validators of the block, and the state must be complete and match the state root
+// of the block. It returns the snapshot of the file.
+func Import(r io.Reader, db intdb.Database, trusted common.Hash) (*Snapshot, error) {
+	var (
+		stream   = rlp.NewStream(bufio.NewReader(r), 0)
+		checksum = sha256.New()
+		snap     *Snapshot
+		count    int
+		logged   = time.Now()
+		batch    = db.NewBatch()
+	)
+	for {
+		var item fileItem
+		if err := stream.Decode(&item); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("%v: checksum missing", errInvalidFile)
+			}
+			return nil, err
+		}
+		if item.Kind == itemChecksum {
+			if !bytes.Equal(item.Value, checksum.Sum(nil)) {
+				return nil, errChecksumMismatch
+			}
+			break
+		}
+		enc, _ := rlp.EncodeToBytes(&item)
+		checksum.Write(enc)
+
+		switch {
+		case item.Kind == itemHeader && snap == nil:
+			var header fileHeader
+			if err := rlp.DecodeBytes(item.Value, &header); err != nil {
+				return nil, fmt.Errorf("%v: %v", errInvalidFile, err)
+			}
+			if header.Magic != fileMagic || header.Version != fileVersion || header.Block == nil {
+				return nil, fmt.Errorf("%v: unsupported version %d", errInvalidFile, header.Version)
+			}
+			if hash := header.Block.Hash(); hash != trusted {
+				return nil, fmt.Errorf("%v: have %x, want %x", errUntrustedBlock, hash, trusted)
+			}
+			snap = &Snapshot{ChainID: header.ChainID, Block: header.Block, Td: header.Td}
+
+		case item.Kind == itemEpoch && snap != nil:
+			snap.Epochs = append(snap.Epochs, Entry{Key: item.Key, Value: item.Value})
+
+		case item.Kind == itemState && snap != nil:
+			// The entries are keyed by their hash, the state root verifies the rest
+			if len(item.Key) != common.HashLength || crypto.Keccak256Hash(item.Value) != common.BytesToHash(item.Key) {
+				return nil, fmt.Errorf("%v: corrupted state entry %x", errInvalidFile, item.Key)
+			}
+			if err := batch.Put(item.Key, item.Value); err != nil {
+				return nil, err
+			}
+			if batch.ValueSize() >= intdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					return nil, err
+				}
+				batch.Reset()
+			}
+			count++
+			if time.Since(logged) > logInterval {
+				log.Info("Importing state", "entries", count)
+				logged = time.Now()
+			}
+
+		default:
+			return nil, fmt.Errorf("%v: unexpected item %d", errInvalidFile, item.Kind)
+		}
+	}
+	if snap == nil {
+		return nil, fmt.Errorf("%v: header missing", errInvalidFile)
+	}
+	entries := make([]epoch.EpochDBEntry, 0, len(snap.Epochs))
+	for _, entry := range snap.Epochs {
+		entries = append(entries, epoch.EpochDBEntry{Key: entry.Key, Value: entry.Value})
+	}
+	if _, err := epoch.VerifyEpochDB(entries, snap.Block.Header()); err != nil {
+		return nil, err
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	log.Info("Imported state", "entries", count, "root", snap.Block.Root())
+
+	// Verify the state is complete, every node is reached from the state root
+	err := state.NewStateWalker(state.NewDatabase(db), false).Walk(snap.Block.Root(), func(hash common.Hash, code bool) (bool, error) {
+		// The trie nodes are resolved by the walk, the codes are checked here
+		if !code {
+			return true, nil
+		}
+		if ok, _ := db.Has(hash.Bytes()); !ok {
+			return false, fmt.Errorf("code %x missing", hash)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("incomplete state: %v", err)
+	}
+	return snap, nil
+}
+
+// Verifier checks the integrity of the states of a database. The sub tries shared
+// by the states of several blocks are checked once while remembered.
+type Verifier struct {
+	db     intdb.Database
+	walker *state.StateWalker
+}
+
+// NewVerifier creates a verifier of the states of the database.
+func NewVerifier(db intdb.Database) *Verifier {
+	return &Verifier{db: db, walker: state.NewStateWalker(state.NewDatabase(db), false)}
+}
+
+// Verify checks that the state of the root is complete, and that every trie node
+// and contract code of it matches its hash. It returns the number of the entries
+// checked.
+func (v *Verifier) Verify(root common.Hash) (int, error) {
+	var (
+		count  int
+		logged = time.Now()
+	)
+	err := v.walker.Walk(root, func(hash common.Hash, code bool) (bool, error) {
+		blob, err := v.db.Get(hash.Bytes())
+		if err != nil {
+			if code {
+				return false, fmt.Errorf("code %x missing", hash)
+			}
+			return false, fmt.Errorf("trie node %x missing", hash)
+		}
+		if crypto.Keccak256Hash(blob) != hash {
+			return false, fmt.Errorf("corrupted state entry %x", hash)
+		}
+		count++
+		if time.Since(logged) > logInterval {
+			log.Info("Verifying state", "root", root, "entries", count)
+			logged = time.Now()
+		}
+		return true, nil
+	})
+	if err != nil {
+		// The failed sub tries must be walked again
+		v.walker.Reset()
+	}
+	return count, err
+}
+
+// WriteChainHead initializes the chain at the block of the snapshot, the block must
+// be imported along with its state into a database holding the genesis only.
+func WriteChainHead(db intdb.Database, snap *Snapshot) {
+	block := snap.Block
+	rawdb.WriteTd(db, block.Hash(), block.NumberU64(), snap.Td)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), nil)
+	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	rawdb.WriteHeadBlockHash(db, block.Hash())
+	rawdb.WriteHeadHeaderHash(db, block.Hash())
+	rawdb.WriteHeadFastBlockHash(db, block.Hash())
+}
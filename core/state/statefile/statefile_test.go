@@ -0,0 +1,180 @@
+package statefile
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/intfoundation/go-crypto"
+	"github.com/intfoundation/go-wire"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus/ipbft/epoch"
+	tmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	intCrypto "github.com/intfoundation/intchain/crypto"
+	"github.com/intfoundation/intchain/intdb"
+)
+
+// makeTestEpoch creates the epoch of the test block, and the extra data of the
+// block committed by the validators of the given epoch.
+func makeTestEpoch() ([]Entry, []byte) {
+	vals := []*tmTypes.Validator{
+		tmTypes.NewValidator(common.BytesToAddress([]byte{1}).Bytes(), crypto.GenPrivKeyEd25519().PubKey(), big.NewInt(100)),
+	}
+	ep := &epoch.Epoch{Number: 1, StartBlock: 1, EndBlock: 200, Validators: tmTypes.NewValidatorSet(vals)}
+	extra := wire.BinaryBytes(tmTypes.TendermintExtra{EpochNumber: ep.Number, ValidatorsHash: ep.Validators.Hash()})
+	return []Entry{
+		{Key: []byte("LatestEpoch"), Value: []byte("1")},
+		{Key: []byte("Epoch:1"), Value: ep.Bytes()},
+	}, extra
+}
+
+// makeTestSnapshot creates a state with accounts, a contract code and storage, and
+// the snapshot of a block at that state.
+func makeTestSnapshot(t *testing.T) (intdb.Database, *Snapshot) {
+	db := rawdb.NewMemoryDatabase()
+	sdb := state.NewDatabase(db)
+
+	statedb, _ := state.New(common.Hash{}, sdb)
+	for i := byte(0); i < 64; i++ {
+		statedb.SetBalance(common.BytesToAddress([]byte{i}), big.NewInt(int64(i)+1))
+	}
+	contract := common.BytesToAddress([]byte{0xff})
+	statedb.SetCode(contract, []byte{0x60, 0x01, 0x60, 0x02})
+	statedb.SetState(contract, common.Hash{1}, common.Hash{2})
+
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := sdb.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	epochs, extra := makeTestEpoch()
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(100), Root: root, Difficulty: big.NewInt(1), Extra: extra})
+	return db, &Snapshot{
+		ChainID: "intchain",
+		Block:   block,
+		Td:      big.NewInt(100),
+		Epochs:  epochs,
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	db, snap := makeTestSnapshot(t)
+
+	var buf bytes.Buffer
+	count, err := Export(&buf, db, snap)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	if count == 0 {
+		t.Fatal("no state exported")
+	}
+
+	imported := rawdb.NewMemoryDatabase()
+	result, err := Import(bytes.NewReader(buf.Bytes()), imported, snap.Block.Hash())
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if result.ChainID != snap.ChainID || result.Block.Hash() != snap.Block.Hash() || result.Td.Cmp(snap.Td) != 0 {
+		t.Fatalf("snapshot mismatch: have %v %x %v, want %v %x %v", result.ChainID, result.Block.Hash(), result.Td, snap.ChainID, snap.Block.Hash(), snap.Td)
+	}
+	if len(result.Epochs) != 2 || !bytes.Equal(result.Epochs[0].Value, []byte("1")) {
+		t.Fatalf("epoch entries mismatch: %v", result.Epochs)
+	}
+
+	statedb, err := state.New(snap.Block.Root(), state.NewDatabase(imported))
+	if err != nil {
+		t.Fatalf("failed to open imported state: %v", err)
+	}
+	contract := common.BytesToAddress([]byte{0xff})
+	if code := statedb.GetCode(contract); !bytes.Equal(code, []byte{0x60, 0x01, 0x60, 0x02}) {
+		t.Errorf("code mismatch: have %x", code)
+	}
+	if value := statedb.GetState(contract, common.Hash{1}); value != (common.Hash{2}) {
+		t.Errorf("storage mismatch: have %x", value)
+	}
+	if balance := statedb.GetBalance(common.BytesToAddress([]byte{10})); balance.Cmp(big.NewInt(11)) != 0 {
+		t.Errorf("balance mismatch: have %v", balance)
+	}
+
+	WriteChainHead(imported, result)
+	if head := rawdb.ReadHeadBlockHash(imported); head != snap.Block.Hash() {
+		t.Errorf("head mismatch: have %x, want %x", head, snap.Block.Hash())
+	}
+}
+
+func TestImportUntrusted(t *testing.T) {
+	db, snap := makeTestSnapshot(t)
+
+	var buf bytes.Buffer
+	if _, err := Export(&buf, db, snap); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	_, err := Import(bytes.NewReader(buf.Bytes()), rawdb.NewMemoryDatabase(), common.Hash{1})
+	if err == nil || !strings.Contains(err.Error(), errUntrustedBlock.Error()) {
+		t.Fatalf("untrusted block error mismatch: have %v, want %v", err, errUntrustedBlock)
+	}
+}
+
+func TestImportUntrustedEpoch(t *testing.T) {
+	db, snap := makeTestSnapshot(t)
+
+	// Replace the epoch by one of other validators
+	snap.Epochs, _ = makeTestEpoch()
+
+	var buf bytes.Buffer
+	if _, err := Export(&buf, db, snap); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	if _, err := Import(bytes.NewReader(buf.Bytes()), rawdb.NewMemoryDatabase(), snap.Block.Hash()); err == nil {
+		t.Fatal("imported an untrusted epoch")
+	}
+}
+
+func TestImportCorrupted(t *testing.T) {
+	db, snap := makeTestSnapshot(t)
+
+	var buf bytes.Buffer
+	if _, err := Export(&buf, db, snap); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	// Drop the checksum at the end of the file
+	truncated := buf.Bytes()[:buf.Len()-34]
+	if _, err := Import(bytes.NewReader(truncated), rawdb.NewMemoryDatabase(), snap.Block.Hash()); err == nil {
+		t.Fatal("imported a truncated file")
+	}
+	// Flip a byte of the last state entry
+	corrupted := common.CopyBytes(buf.Bytes())
+	corrupted[len(corrupted)-40] ^= 0xff
+	if _, err := Import(bytes.NewReader(corrupted), rawdb.NewMemoryDatabase(), snap.Block.Hash()); err == nil {
+		t.Fatal("imported a corrupted file")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	db, snap := makeTestSnapshot(t)
+
+	count, err := NewVerifier(db).Verify(snap.Block.Root())
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if count == 0 {
+		t.Fatal("no state verified")
+	}
+	// Overwrite the contract code with a different one
+	code := intCrypto.Keccak256Hash([]byte{0x60, 0x01, 0x60, 0x02})
+	db.Put(code.Bytes(), []byte{0x60, 0x03})
+	if _, err := NewVerifier(db).Verify(snap.Block.Root()); err == nil {
+		t.Fatal("verified a corrupted state")
+	}
+	// Drop the state root
+	db.Delete(snap.Block.Root().Bytes())
+	if _, err := NewVerifier(db).Verify(snap.Block.Root()); err == nil {
+		t.Fatal("verified an incomplete state")
+	}
+}
@@ -17,11 +17,8 @@
 package state
 
 import (
-	"bytes"
-
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/intdb"
-	"github.com/intfoundation/intchain/rlp"
 	"github.com/intfoundation/intchain/trie"
 )
 
@@ -29,11 +26,15 @@ import (
 func NewStateSync(root common.Hash, database intdb.Reader) *trie.Sync {
 	var syncer *trie.Sync
 	callback := func(leaf []byte, parent common.Hash) error {
-		var obj Account
-		if err := rlp.Decode(bytes.NewReader(leaf), &obj); err != nil {
-			return err
+		obj, ok := decodeAccount(leaf)
+		if !ok {
+			return nil
+		}
+		for _, subRoot := range obj.subRoots() {
+			if subRoot != (common.Hash{}) {
+				syncer.AddSubTrie(subRoot, 64, parent, nil)
+			}
 		}
-		syncer.AddSubTrie(obj.Root, 64, parent, nil)
 		syncer.AddRawEntry(common.BytesToHash(obj.CodeHash), 64, parent)
 		return nil
 	}
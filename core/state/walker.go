@@ -0,0 +1,141 @@
+package state
+
+import (
+	"github.com/hashicorp/golang-lru"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/rlp"
+	"github.com/intfoundation/intchain/trie"
+)
+
+// walkCacheSize is the number of trie nodes remembered as visited by a walker, the
+// sub tries shared by several accounts or states are walked once while remembered.
+const walkCacheSize = 256 * 1024
+
+// StateVisitor is called for every trie node and contract code of a walk, it
+// reports whether the children of the trie node should be visited.
+type StateVisitor func(hash common.Hash, code bool) (bool, error)
+
+// StateWalker walks the trie nodes and contract codes of the states, including
+// all the tries of their accounts. The nodes and codes remembered as visited are
+// skipped, so a failed walk must be followed by a Reset.
+type StateWalker struct {
+	db          Database
+	seen        *lru.Cache
+	skipMissing bool
+}
+
+// NewStateWalker creates a walker of the states of the database. If skipMissing
+// is set the states and the tries whose root is missing are skipped rather than
+// failing the walk, the state of the blocks before the fast sync pivot is not
+// available. A node missing below a root always fails the walk, the iterator
+// can't step over it and the nodes after it would be left unvisited.
+func NewStateWalker(db Database, skipMissing bool) *StateWalker {
+	seen, _ := lru.New(walkCacheSize)
+	return &StateWalker{db: db, seen: seen, skipMissing: skipMissing}
+}
+
+// Reset forgets the trie nodes and contract codes visited.
+func (w *StateWalker) Reset() {
+	w.seen.Purge()
+}
+
+// Walk calls visit for every trie node and contract code of the state root not
+// visited yet. The embedded nodes are stored within their parent and not visited.
+func (w *StateWalker) Walk(root common.Hash, visit StateVisitor) error {
+	t, err := w.db.OpenTrie(root)
+	if err != nil {
+		if w.missing(err) {
+			log.Debug("Skip the missing state", "root", root)
+			return nil
+		}
+		return err
+	}
+	return w.walkTrie(t, visit, func(account *Account) error {
+		opens := [...]func(addrHash, root common.Hash) (Trie, error){
+			w.db.OpenStorageTrie, w.db.OpenTX1Trie, w.db.OpenTX3Trie, w.db.OpenProxiedTrie, w.db.OpenRewardTrie,
+		}
+		for i, subRoot := range account.subRoots() {
+			if subRoot == emptyRoot || subRoot == (common.Hash{}) {
+				continue
+			}
+			subTrie, err := opens[i](common.Hash{}, subRoot)
+			if err != nil {
+				if w.missing(err) {
+					continue
+				}
+				return err
+			}
+			if err := w.walkTrie(subTrie, visit, nil); err != nil {
+				return err
+			}
+		}
+		if codeHash := common.BytesToHash(account.CodeHash); len(account.CodeHash) > 0 && codeHash != emptyCode {
+			if w.seen.Contains(codeHash) {
+				return nil
+			}
+			w.seen.Add(codeHash, nil)
+			_, err := visit(codeHash, true)
+			return err
+		}
+		return nil
+	})
+}
+
+// walkTrie visits the trie nodes of the trie, calling processLeaf for every account.
+func (w *StateWalker) walkTrie(t Trie, visit StateVisitor, processLeaf func(account *Account) error) error {
+	descend := true
+	it := t.NodeIterator(nil)
+	for it.Next(descend) {
+		descend = true
+		if it.Leaf() {
+			if processLeaf == nil {
+				continue
+			}
+			if account, ok := decodeAccount(it.LeafBlob()); ok {
+				if err := processLeaf(account); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		hash := it.Hash()
+		if hash == (common.Hash{}) {
+			continue
+		}
+		if w.seen.Contains(hash) {
+			descend = false
+			continue
+		}
+		w.seen.Add(hash, nil)
+
+		var err error
+		if descend, err = visit(hash, false); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// missing reports whether the error is a missing trie root to skip.
+func (w *StateWalker) missing(err error) bool {
+	_, ok := err.(*trie.MissingNodeError)
+	return ok && w.skipMissing
+}
+
+// decodeAccount decodes the leaf of the account trie. The delegate refund set,
+// reward set and child chain reward per block are stored in the account trie as
+// well, they are not accounts.
+func decodeAccount(leaf []byte) (*Account, bool) {
+	var account Account
+	if err := rlp.DecodeBytes(leaf, &account); err != nil {
+		return nil, false
+	}
+	return &account, true
+}
+
+// subRoots returns the roots of the storage trie and the IntChain specific tx1,
+// tx3, proxied and reward tries of the account.
+func (a *Account) subRoots() []common.Hash {
+	return []common.Hash{a.Root, a.TX1Root, a.TX3Root, a.ProxiedRoot, a.RewardRoot}
+}
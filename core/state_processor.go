@@ -99,7 +99,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, error) {
-	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
+	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number), header.BaseFee)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +125,7 @@ func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common
 	// Create a new receipt for the transaction, storing the intermediate root and gas used by the tx
 	// based on the eip phase, we're passing wether the root touch-delete accounts.
 	receipt := types.NewReceipt(root, result.Failed(), *usedGas)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
 	// if the transaction created a contract, store the creation address in the receipt.
@@ -146,7 +147,7 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 	header *types.Header, tx *types.Transaction, usedGas *uint64, totalUsedMoney *big.Int, cfg vm.Config, cch CrossChainHelper, mining bool) (*types.Receipt, error) {
 
 	signer := types.MakeSigner(config, header.Number)
-	msg, err := tx.AsMessage(signer)
+	msg, err := tx.AsMessage(signer, header.BaseFee)
 	if err != nil {
 		return nil, err
 	}
@@ -157,6 +158,8 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 
 		// Create a new context to be used in the EVM environment
 		context := NewEVMContext(msg, header, bc, author)
+		staking := NewEVMStaking(bc)
+		context.Staking = staking
 
 		//log.Debugf("ApplyTransactionEx 2\n")
 
@@ -168,6 +171,12 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 		if err != nil {
 			return nil, err
 		}
+		// Update the next epoch votes of the candidates delegated through the staking precompiled contract
+		if !result.Failed() {
+			if err := staking.UpdateNextEpoch(tx.Hash(), statedb, ops); err != nil {
+				return nil, err
+			}
+		}
 
 		//log.Debugf("ApplyTransactionEx 3\n")
 		// Update the state with pending changes
@@ -186,6 +195,7 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 		// based on the eip phase, we're passing wether the root touch-delete accounts.
 		receipt := types.NewReceipt(root, result.Failed(), *usedGas)
 		log.Debugf("ApplyTransactionEx，new receipt with (root,failed,*usedGas) = (%v,%v,%v)\n", root, result.Failed(), *usedGas)
+		receipt.Type = tx.Type()
 		receipt.TxHash = tx.Hash()
 		//log.Debugf("ApplyTransactionEx，new receipt with txhash %v\n", receipt.TxHash)
 		receipt.GasUsed = result.UsedGas
@@ -235,9 +245,17 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 			}
 		}
 
+		// Make sure the fee cap covers the base fee after the London fork
+		if config.IsLondon(header.Number) {
+			if err := checkFeeCaps(msg.GasFeeCap(), msg.GasTipCap(), header.BaseFee); err != nil {
+				return nil, err
+			}
+		}
+
 		// pre-buy gas according to the gas limit
 		gasLimit := tx.Gas()
-		gasValue := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), tx.GasPrice())
+		gasPrice := msg.GasPrice()
+		gasValue := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice)
 		if statedb.GetBalance(from).Cmp(gasValue) < 0 {
 			return nil, fmt.Errorf("insufficient INT for gas (%x). Req %v, has %v", from.Bytes()[:4], gasValue, statedb.GetBalance(from))
 		}
@@ -284,12 +302,15 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 
 		// refund gas
 		remainingGas := gasLimit - gas
-		remaining := new(big.Int).Mul(new(big.Int).SetUint64(remainingGas), tx.GasPrice())
+		remaining := new(big.Int).Mul(new(big.Int).SetUint64(remainingGas), gasPrice)
 		statedb.AddBalance(from, remaining)
 		gp.AddGas(remainingGas)
 
+		baseFee, tip := splitGasPrice(config, header.Number, header.BaseFee, gasPrice)
+		payBaseFee(config, statedb, gas, baseFee)
+
 		*usedGas += gas
-		totalUsedMoney.Add(totalUsedMoney, new(big.Int).Mul(new(big.Int).SetUint64(gas), tx.GasPrice()))
+		totalUsedMoney.Add(totalUsedMoney, new(big.Int).Mul(new(big.Int).SetUint64(gas), tip))
 		log.Infof("ApplyTransactionEx() 2, totalUsedMoney is %v\n", totalUsedMoney)
 
 		// Update the state with pending changes
@@ -302,6 +323,7 @@ func ApplyTransactionEx(config *params.ChainConfig, bc *BlockChain, author *comm
 
 		// TODO: whether true of false for the transaction receipt
 		receipt := types.NewReceipt(root, false, *usedGas)
+		receipt.Type = tx.Type()
 		receipt.TxHash = tx.Hash()
 		receipt.GasUsed = gas
 
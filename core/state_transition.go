@@ -22,6 +22,8 @@ import (
 	"math/big"
 
 	"github.com/intfoundation/intchain/common"
+	cmath "github.com/intfoundation/intchain/common/math"
+	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/core/vm"
 	"github.com/intfoundation/intchain/params"
 )
@@ -52,6 +54,8 @@ type StateTransition struct {
 	msg        Message
 	gas        uint64
 	gasPrice   *big.Int
+	gasFeeCap  *big.Int
+	gasTipCap  *big.Int
 	initialGas uint64
 	value      *big.Int
 	data       []byte
@@ -66,12 +70,15 @@ type Message interface {
 	To() *common.Address
 
 	GasPrice() *big.Int
+	GasFeeCap() *big.Int
+	GasTipCap() *big.Int
 	Gas() uint64
 	Value() *big.Int
 
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -110,7 +117,7 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error) {
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation, homestead bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation && homestead {
@@ -140,19 +147,25 @@ func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error)
 		}
 		gas += z * params.TxDataZeroGas
 	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+	}
 	return gas, nil
 }
 
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *GasPool) *StateTransition {
 	return &StateTransition{
-		gp:       gp,
-		evm:      evm,
-		msg:      msg,
-		gasPrice: msg.GasPrice(),
-		value:    msg.Value(),
-		data:     msg.Data(),
-		state:    evm.StateDB,
+		gp:        gp,
+		evm:       evm,
+		msg:       msg,
+		gasPrice:  msg.GasPrice(),
+		gasFeeCap: msg.GasFeeCap(),
+		gasTipCap: msg.GasTipCap(),
+		value:     msg.Value(),
+		data:      msg.Data(),
+		state:     evm.StateDB,
 	}
 }
 
@@ -206,7 +219,11 @@ func (st *StateTransition) buyGas() error {
 		sender = st.from()
 	)
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if state.GetBalance(sender.Address()).Cmp(mgval) < 0 {
+	balanceCheck := mgval
+	if st.gasFeeCap != nil {
+		balanceCheck = new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasFeeCap)
+	}
+	if state.GetBalance(sender.Address()).Cmp(balanceCheck) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -232,9 +249,57 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
+	if st.evm.ChainConfig().IsLondon(st.evm.BlockNumber) {
+		// Skip the checks if gas fields are zero and baseFee was explicitly disabled (eth_call)
+		if !st.evm.Config().NoBaseFee || st.gasFeeCap.BitLen() > 0 || st.gasTipCap.BitLen() > 0 {
+			if err := checkFeeCaps(st.gasFeeCap, st.gasTipCap, st.evm.BaseFee); err != nil {
+				return err
+			}
+		}
+	}
 	return st.buyGas()
 }
 
+// checkFeeCaps verifies the fee cap and the tip cap of a message against the base
+// fee of the block.
+func checkFeeCaps(gasFeeCap, gasTipCap, baseFee *big.Int) error {
+	if l := gasFeeCap.BitLen(); l > 256 {
+		return ErrFeeCapVeryHigh
+	}
+	if l := gasTipCap.BitLen(); l > 256 {
+		return ErrTipVeryHigh
+	}
+	if gasFeeCap.Cmp(gasTipCap) < 0 {
+		return ErrTipAboveFeeCap
+	}
+	// This will panic if baseFee is nil, but basefee presence is verified
+	// as part of header validation.
+	if gasFeeCap.Cmp(baseFee) < 0 {
+		return ErrFeeCapTooLow
+	}
+	return nil
+}
+
+// splitGasPrice splits the effective gas price of a message into the base fee and
+// the tip paid to the block proposer. The base fee is zero before the London fork.
+func splitGasPrice(config *params.ChainConfig, number, baseFee, gasPrice *big.Int) (*big.Int, *big.Int) {
+	if !config.IsLondon(number) || baseFee == nil {
+		return new(big.Int), gasPrice
+	}
+	burnt := cmath.BigMin(baseFee, gasPrice)
+	return burnt, new(big.Int).Sub(gasPrice, burnt)
+}
+
+// payBaseFee credits the base fee of the used gas to the base fee treasury of the
+// chain, the base fee is burnt if the chain has no treasury.
+func payBaseFee(config *params.ChainConfig, statedb vm.StateDB, gasUsed uint64, baseFee *big.Int) {
+	if config.BaseFeeTreasury == nil || baseFee.Sign() == 0 {
+		return
+	}
+	statedb.AddBalance(*config.BaseFeeTreasury, new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), baseFee))
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the result including the the used gas. It returns an error if it
 // failed. An error indicates a consensus issue.
@@ -249,7 +314,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	contractCreation := msg.To() == nil
 
 	// Pay intrinsic gas
-	gas, err := IntrinsicGas(st.data, contractCreation, homestead)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, homestead)
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +322,12 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 		return nil, err
 	}
 
+	// Warm the sender, the destination, the precompiles and the access list of
+	// the transaction after the Berlin fork
+	if rules := st.evm.ChainConfig().Rules(st.evm.BlockNumber); rules.IsBerlin {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	}
+
 	var (
 		evm = st.evm
 		// vm errors do not effect consensus and are therefor
@@ -282,7 +353,10 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	//	}
 	//}
 	st.refundGas()
-	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+
+	baseFee, tip := splitGasPrice(st.evm.ChainConfig(), st.evm.BlockNumber, st.evm.BaseFee, st.gasPrice)
+	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), tip))
+	payBaseFee(st.evm.ChainConfig(), st.state, st.gasUsed(), baseFee)
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -341,7 +415,7 @@ func (st *StateTransition) TransitionDbEx() (*ExecutionResult, *big.Int, error)
 	contractCreation := msg.To() == nil
 
 	// Pay intrinsic gas
-	gas, err := IntrinsicGas(st.data, contractCreation, homestead)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, homestead)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -349,6 +423,12 @@ func (st *StateTransition) TransitionDbEx() (*ExecutionResult, *big.Int, error)
 		return nil, nil, err
 	}
 
+	// Warm the sender, the destination, the precompiles and the access list of
+	// the transaction after the Berlin fork
+	if rules := st.evm.ChainConfig().Rules(st.evm.BlockNumber); rules.IsBerlin {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	}
+
 	var (
 		evm = st.evm
 		// vm errors do not effect consensus and are therefor
@@ -391,7 +471,12 @@ func (st *StateTransition) TransitionDbEx() (*ExecutionResult, *big.Int, error)
 	//	st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
 
 	//st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
-	usedMoney := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+
+	// Only the tips go to the block reward after the London fork, the base fee is
+	// burnt or paid to the base fee treasury
+	baseFee, tip := splitGasPrice(st.evm.ChainConfig(), st.evm.BlockNumber, st.evm.BaseFee, st.gasPrice)
+	payBaseFee(st.evm.ChainConfig(), st.state, st.gasUsed(), baseFee)
+	usedMoney := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), tip)
 
 	//log.Debugf("TransitionDbEx 5\n")
 
@@ -32,6 +32,8 @@ type CrossChainHelper interface {
 
 	CanCreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount, startupCost *big.Int, startBlock, endBlock *big.Int) error
 	CreateChildChain(from common.Address, chainId string, minValidators uint16, minDepositAmount *big.Int, startBlock, endBlock *big.Int) error
+	ValidateSetChildChainGenesis(from common.Address, chainId string, genesisTemplate []byte) error
+	SetChildChainGenesis(chainId string, genesisTemplate []byte) error
 	ValidateJoinChildChain(from common.Address, pubkey []byte, chainId string, depositAmount *big.Int, signature []byte) error
 	JoinChildChain(from common.Address, pubkey crypto.PubKey, chainId string, depositAmount *big.Int) error
 	ReadyForLaunchChildChain(height *big.Int, stateDB *state.StateDB) ([]string, []byte, []string)
@@ -44,11 +46,15 @@ type CrossChainHelper interface {
 	GetHeightFromMainChain() *big.Int
 	GetEpochFromMainChain() (string, *epoch.Epoch)
 	GetTxFromMainChain(txHash common.Hash) *types.Transaction
+	GetReceiptFromMainChain(txHash common.Hash) *types.Receipt
+
+	// for token bridge only
+	GetMainChainBridge() common.Address
 
 	ChangeValidators(chainId string)
 
-	// for epoch only
-	VerifyChildChainProofData(bs []byte) error
+	// for epoch only, number is the main chain block carrying the proof data
+	VerifyChildChainProofData(bs []byte, number *big.Int) error
 	SaveChildChainProofDataToMainChain(bs []byte) error
 
 	TX3LocalCache
@@ -70,9 +76,15 @@ type NonCrossChainApplyCb = func(tx *types.Transaction, state *state.StateDB, bc
 
 type EtdInsertBlockCb func(bc *BlockChain, block *types.Block)
 
+// Staking Precompiled Contract Callback
+type EVMStakingCb = func(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *BlockChain) error
+type UpdateNextEpochCb = func(candidate common.Address, txHash common.Hash, state *state.StateDB, bc *BlockChain, ops *types.PendingOps) error
+
 var validateCbMap = make(map[intAbi.FunctionType]interface{})
 var applyCbMap = make(map[intAbi.FunctionType]interface{})
 var insertBlockCbMap = make(map[string]EtdInsertBlockCb)
+var evmStakingCbMap = make(map[intAbi.FunctionType]EVMStakingCb)
+var updateNextEpochCb UpdateNextEpochCb
 
 func RegisterValidateCb(function intAbi.FunctionType, validateCb interface{}) error {
 
@@ -143,3 +155,41 @@ func GetInsertBlockCbMap() map[string]EtdInsertBlockCb {
 
 	return insertBlockCbMap
 }
+
+func RegisterEVMStakingCb(function intAbi.FunctionType, stakingCb EVMStakingCb) error {
+
+	_, ok := evmStakingCbMap[function]
+	if ok {
+		return errors.New("the name has registered in evmStakingCbMap")
+	}
+
+	evmStakingCbMap[function] = stakingCb
+
+	return nil
+}
+
+func GetEVMStakingCb(function intAbi.FunctionType) EVMStakingCb {
+
+	cb, ok := evmStakingCbMap[function]
+	if ok {
+		return cb
+	}
+
+	return nil
+}
+
+func RegisterUpdateNextEpochCb(cb UpdateNextEpochCb) error {
+
+	if updateNextEpochCb != nil {
+		return errors.New("the update next epoch callback has registered")
+	}
+
+	updateNextEpochCb = cb
+
+	return nil
+}
+
+func GetUpdateNextEpochCb() UpdateNextEpochCb {
+
+	return updateNextEpochCb
+}
@@ -17,11 +17,15 @@
 package core
 
 import (
+	"crypto/ecdsa"
+	"math/big"
 	"math/rand"
 	"testing"
 
+	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/crypto"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
 )
 
 // Tests that transactions can be added to strict lists and list contents and
@@ -49,3 +53,47 @@ func TestStrictTxListAdd(t *testing.T) {
 		}
 	}
 }
+
+// specialTransaction creates a transaction calling the IntChain contract.
+func specialTransaction(nonce uint64, gasprice *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTransaction(nonce, intAbi.ChainContractMagicAddr, big.NewInt(0), 100000, gasprice, nil), types.HomesteadSigner{}, key)
+	return tx
+}
+
+// Tests that the price based eviction of the priced list keeps the special
+// transactions, which are bounded by their own lane.
+func TestPricedTxListKeepsSpecial(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	all := make(map[common.Hash]*types.Transaction)
+	var special, ordinary types.Transactions
+	for i := 0; i < 4; i++ {
+		special = append(special, specialTransaction(uint64(i), big.NewInt(1), key))
+		ordinary = append(ordinary, pricedTransaction(uint64(4+i), 100000, big.NewInt(1), key))
+	}
+	fill := func() *txPricedList {
+		for hash := range all {
+			delete(all, hash)
+		}
+		priced := newTxPricedList(&all)
+		for _, tx := range append(append(types.Transactions{}, special...), ordinary...) {
+			all[tx.Hash()] = tx
+			priced.Put(tx)
+		}
+		return priced
+	}
+	local := newAccountSet(types.HomesteadSigner{})
+
+	checkDrop := func(op string, drop types.Transactions) {
+		if len(drop) != len(ordinary) {
+			t.Errorf("%s: dropped transaction count mismatch: have %d, want %d", op, len(drop), len(ordinary))
+		}
+		for _, tx := range drop {
+			if isSpecialTx(tx) {
+				t.Errorf("%s: special transaction %x dropped", op, tx.Hash())
+			}
+		}
+	}
+	checkDrop("cap", fill().Cap(big.NewInt(2), local))
+	checkDrop("discard", fill().Discard(len(special)+len(ordinary), local))
+}
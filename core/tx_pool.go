@@ -27,6 +27,7 @@ import (
 
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/common/prque"
+	"github.com/intfoundation/intchain/consensus/misc"
 	"github.com/intfoundation/intchain/core/state"
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/event"
@@ -75,10 +76,27 @@ var (
 	// transaction with a negative value.
 	ErrNegativeValue = errors.New("negative value")
 
+	// ErrTxTypeNotSupported is returned if a typed transaction is received before
+	// the Berlin fork activates.
+	ErrTxTypeNotSupported = types.ErrTxTypeNotSupported
+
 	// ErrOversizedData is returned if the input data of a transaction is greater
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrSpecialLaneFull is returned if a special IntChain transaction is added
+	// while the special transaction lane of the pool is full.
+	ErrSpecialLaneFull = errors.New("special transaction lane full")
+
+	// ErrSpecialQuotaExceeded is returned if a special IntChain transaction is
+	// added while the pool holds the maximum number of special transactions of
+	// the same function.
+	ErrSpecialQuotaExceeded = errors.New("special transaction quota of the function exceeded")
+
+	// ErrNonceGap is reported for a queued transaction if some of the nonces in
+	// front of it are missing from the pool.
+	ErrNonceGap = errors.New("nonce gap")
 )
 
 var (
@@ -139,6 +157,11 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	SpecialSlots         uint64 // Maximum number of special IntChain transactions kept in the priority lane
+	SpecialFunctionSlots uint64 // Maximum number of special IntChain transactions per function in the priority lane
+
+	PrivateBlocks uint64 // Maximum number of blocks a private transaction is kept waiting for inclusion
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -156,6 +179,11 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	SpecialSlots:         1024,
+	SpecialFunctionSlots: 256,
+
+	PrivateBlocks: 100,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -174,6 +202,18 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	if conf.SpecialSlots < 1 {
+		log.Warn("Sanitizing invalid txpool special slots", "provided", conf.SpecialSlots, "updated", DefaultTxPoolConfig.SpecialSlots)
+		conf.SpecialSlots = DefaultTxPoolConfig.SpecialSlots
+	}
+	if conf.SpecialFunctionSlots < 1 || conf.SpecialFunctionSlots > conf.SpecialSlots {
+		log.Warn("Sanitizing invalid txpool special function slots", "provided", conf.SpecialFunctionSlots, "updated", conf.SpecialSlots)
+		conf.SpecialFunctionSlots = conf.SpecialSlots
+	}
+	if conf.PrivateBlocks < 1 {
+		log.Warn("Sanitizing invalid txpool private blocks", "provided", conf.PrivateBlocks, "updated", DefaultTxPoolConfig.PrivateBlocks)
+		conf.PrivateBlocks = DefaultTxPoolConfig.PrivateBlocks
+	}
 	return conf
 }
 
@@ -190,6 +230,11 @@ type TxPool struct {
 	chain        blockChain
 	gasPrice     *big.Int
 	txFeed       event.Feed
+	dropFeed     event.Feed
+	dropMu       sync.Mutex    // Protects the drop events awaiting delivery
+	drops        []TxDropEvent // Drop events awaiting delivery, in order
+	dropReqCh    chan struct{} // Wakes up the drop event delivery loop
+	quit         chan struct{} // Quit channel of the background loops
 	scope        event.SubscriptionScope
 	chainHeadCh  chan ChainHeadEvent
 	chainHeadSub event.Subscription
@@ -199,6 +244,8 @@ type TxPool struct {
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
+	eip2718       bool                // Fork indicator whether typed transactions are accepted
+	eip1559       bool                // Fork indicator whether dynamic fee transactions are accepted
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -208,6 +255,8 @@ type TxPool struct {
 	beats   map[common.Address]time.Time       // Last heartbeat from each known account
 	all     map[common.Hash]*types.Transaction // All transactions to allow lookups
 	priced  *txPricedList                      // All transactions sorted by price
+	special *specialLane                       // Special IntChain transactions, exempt from the price based eviction
+	private map[common.Hash]*privateTx         // Private transactions, never propagated to the network
 
 	wg sync.WaitGroup // for shutdown sync
 
@@ -227,12 +276,16 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       chain,
-		signer:      types.NewEIP155Signer(chainconfig.ChainId),
+		signer:      types.LatestSigner(chainconfig),
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
 		all:         make(map[common.Hash]*types.Transaction),
+		special:     newSpecialLane(),
+		private:     make(map[common.Hash]*privateTx),
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
+		dropReqCh:   make(chan struct{}, 1),
+		quit:        make(chan struct{}),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
 		cch:         cch,
 	}
@@ -259,12 +312,35 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
 
 	// Start the event loop and return
-	pool.wg.Add(1)
+	pool.wg.Add(2)
 	go pool.loop()
+	go pool.dropLoop()
 
 	return pool
 }
 
+// dropLoop delivers the drop events to the subscribers one batch at a time,
+// keeping the order of the drops without blocking the pool on slow readers.
+func (pool *TxPool) dropLoop() {
+	defer pool.wg.Done()
+
+	for {
+		select {
+		case <-pool.dropReqCh:
+			pool.dropMu.Lock()
+			drops := pool.drops
+			pool.drops = nil
+			pool.dropMu.Unlock()
+
+			for _, ev := range drops {
+				pool.dropFeed.Send(ev)
+			}
+		case <-pool.quit:
+			return
+		}
+	}
+}
+
 // loop is the transaction pool's main event loop, waiting for and reacting to
 // outside blockchain events as well as for various reporting and transaction
 // eviction events.
@@ -324,7 +400,7 @@ func (pool *TxPool) loop() {
 				// Any non-locals old enough should be removed
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					for _, tx := range pool.queue[addr].Flatten() {
-						pool.removeTx(tx.Hash())
+						pool.removeTx(tx.Hash(), TxDropExpired)
 					}
 				}
 			}
@@ -415,6 +491,16 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	// Update the fork indicator for the next pending block number
+	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
+	pool.eip2718 = pool.chainconfig.IsBerlin(next)
+	pool.eip1559 = pool.chainconfig.IsLondon(next)
+
+	// Sort the transactions by their effective tip under the base fee of the next block
+	if pool.eip1559 {
+		pool.priced.SetBaseFee(misc.CalcBaseFee(pool.chainconfig, newHead))
+	}
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	pool.addTxsLocked(reinject, false)
@@ -433,6 +519,9 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Check the queue and move transactions over to the pending if possible
 	// or remove those that have become invalid
 	pool.promoteExecutables(nil)
+
+	// Drop the private transactions not included in time
+	pool.expirePrivates(newHead.Number.Uint64())
 }
 
 // Stop terminates the transaction pool.
@@ -442,6 +531,7 @@ func (pool *TxPool) Stop() {
 
 	// Unsubscribe subscriptions registered from blockchain
 	pool.chainHeadSub.Unsubscribe()
+	close(pool.quit)
 	pool.wg.Wait()
 
 	if pool.journal != nil {
@@ -456,6 +546,12 @@ func (pool *TxPool) SubscribeTxPreEvent(ch chan<- TxPreEvent) event.Subscription
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeTxDropEvent registers a subscription of TxDropEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeTxDropEvent(ch chan<- TxDropEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -472,7 +568,7 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 
 	pool.gasPrice = price
 	for _, tx := range pool.priced.Cap(price, pool.locals) {
-		pool.removeTx(tx.Hash())
+		pool.removeTx(tx.Hash(), TxDropUnderpriced)
 	}
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
@@ -525,6 +621,59 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// NonceGap is an inclusive range of nonces missing from the pool in front of
+// some queued transactions of an account.
+type NonceGap struct {
+	From uint64
+	To   uint64
+}
+
+// QueuedAccount describes why the queued transactions of an account are not
+// executable.
+type QueuedAccount struct {
+	Nonce   uint64           // Next nonce executable on top of the pending transactions
+	Gaps    []NonceGap       // Nonces missing in front of the queued transactions
+	Reasons map[uint64]error // Reason of each queued transaction not being executable, keyed by nonce
+}
+
+// Queued retrieves the nonce gaps of all the accounts with queued transactions,
+// along with the reason of each queued transaction not being executable. A nil
+// reason means the transaction is executable and awaits promotion.
+func (pool *TxPool) Queued() map[common.Address]*QueuedAccount {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	queued := make(map[common.Address]*QueuedAccount)
+	for addr, list := range pool.queue {
+		account := &QueuedAccount{
+			Nonce:   pool.pendingState.GetNonce(addr),
+			Reasons: make(map[uint64]error),
+		}
+		balance := pool.currentState.GetBalance(addr)
+
+		next := account.Nonce
+		for _, tx := range list.Flatten() {
+			if tx.Nonce() > next {
+				account.Gaps = append(account.Gaps, NonceGap{From: next, To: tx.Nonce() - 1})
+			}
+			next = tx.Nonce() + 1
+
+			switch {
+			case len(account.Gaps) > 0:
+				account.Reasons[tx.Nonce()] = ErrNonceGap
+			case balance.Cmp(tx.Cost()) < 0:
+				account.Reasons[tx.Nonce()] = ErrInsufficientFunds
+			case tx.Gas() > pool.currentMaxGas:
+				account.Reasons[tx.Nonce()] = ErrGasLimit
+			default:
+				account.Reasons[tx.Nonce()] = nil
+			}
+		}
+		queued[addr] = account
+	}
+	return queued
+}
+
 // Pending retrieves all currently processable transactions, groupped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -539,6 +688,28 @@ func (pool *TxPool) Pending() (map[common.Address]types.Transactions, error) {
 	return pending, nil
 }
 
+// SpecialPending retrieves the processable special IntChain transactions, so they
+// can be committed ahead of the others. Only the special transactions in front of
+// the first ordinary one of each account are returned, the ordinary transactions
+// never get into the reserved lane.
+func (pool *TxPool) SpecialPending() map[common.Address]types.Transactions {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	pending := make(map[common.Address]types.Transactions)
+	for addr, list := range pool.pending {
+		txs := list.Flatten()
+		n := 0
+		for n < len(txs) && isSpecialTx(txs[n]) {
+			n++
+		}
+		if n > 0 {
+			pending[addr] = txs[:n]
+		}
+	}
+	return pending
+}
+
 // local retrieves all currently known local transactions, groupped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -546,10 +717,10 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	txs := make(map[common.Address]types.Transactions)
 	for addr := range pool.locals.accounts {
 		if pending := pool.pending[addr]; pending != nil {
-			txs[addr] = append(txs[addr], pending.Flatten()...)
+			txs[addr] = append(txs[addr], pool.public(pending.Flatten())...)
 		}
 		if queued := pool.queue[addr]; queued != nil {
-			txs[addr] = append(txs[addr], queued.Flatten()...)
+			txs[addr] = append(txs[addr], pool.public(queued.Flatten())...)
 		}
 	}
 	return txs
@@ -558,6 +729,14 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+	// Accept only legacy transactions until the Berlin fork
+	if !pool.eip2718 && tx.Type() != types.LegacyTxType {
+		return ErrTxTypeNotSupported
+	}
+	// Reject dynamic fee transactions until the London fork
+	if !pool.eip1559 && tx.Type() == types.DynamicFeeTxType {
+		return ErrTxTypeNotSupported
+	}
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
 	if tx.Size() > 32*1024 {
 		return ErrOversizedData
@@ -572,15 +751,26 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentMaxGas < tx.Gas() {
 		return ErrGasLimit
 	}
+	// Sanity check for extremely large numbers
+	if tx.GasFeeCap().BitLen() > 256 {
+		return ErrFeeCapVeryHigh
+	}
+	if tx.GasTipCap().BitLen() > 256 {
+		return ErrTipVeryHigh
+	}
+	// Ensure gasFeeCap is greater than or equal to gasTipCap.
+	if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
+		return ErrTipAboveFeeCap
+	}
 
 	// Make sure the transaction is signed properly
 	from, err := types.Sender(pool.signer, tx)
 	if err != nil {
 		return ErrInvalidSender
 	}
-	// Drop non-local transactions under our own minimal accepted gas price
+	// Drop non-local transactions under our own minimal accepted gas price or tip
 	local = local || pool.locals.contains(from) // account may be local even if the transaction arrived from the network
-	if !local && pool.gasPrice.Cmp(tx.GasPrice()) > 0 {
+	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
 		return ErrUnderpriced
 	}
 	// Ensure the transaction adheres to nonce ordering
@@ -589,13 +779,13 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	}
 
 	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
+	// cost == V + GP * GL, the fee cap being the gas price of the fee market txs
 	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
 		return ErrInsufficientFunds
 	}
 
 	if !intAbi.IsIntChainContractAddr(tx.To()) {
-		intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, true)
+		intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true)
 		if err != nil {
 			return err
 		}
@@ -667,9 +857,21 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		return false, err
 	}
 
+	// Special transactions are bounded by the limits of their own lane instead
+	// of competing with the ordinary ones on price
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	function, special := specialFunction(tx)
+	pool.special.sync(pool.all)
+	if special {
+		if err := pool.checkSpecialLane(from, tx, function); err != nil {
+			log.Trace("Discarding special transaction", "hash", hash, "function", function, "err", err)
+			return false, err
+		}
+	}
+
 	// If the transaction pool is full, discard underpriced transactions
-	if !params.GenCfg.PerfTest &&
-		uint64(len(pool.all)) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+	if !special && !params.GenCfg.PerfTest &&
+		uint64(len(pool.all)-pool.special.len()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
 		if pool.priced.Underpriced(tx, pool.locals) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
@@ -677,16 +879,15 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 			return false, ErrUnderpriced
 		}
 		// New transaction is better than our worse ones, make room for it
-		drop := pool.priced.Discard(len(pool.all)-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
+		drop := pool.priced.Discard(len(pool.all)-pool.special.len()-int(pool.config.GlobalSlots+pool.config.GlobalQueue-1), pool.locals)
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
-			pool.removeTx(tx.Hash())
+			pool.removeTx(tx.Hash(), TxDropUnderpriced)
 		}
 	}
 
 	// If the transaction is replacing an already pending one, do directly
-	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -696,18 +897,20 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 		// New transaction is better, replace old one
 		if old != nil {
-			delete(pool.all, old.Hash())
-			pool.priced.Removed()
+			pool.dropTx(old, TxDropReplaced, tx)
 			pendingReplaceCounter.Inc(1)
 		}
 		pool.all[tx.Hash()] = tx
 		pool.priced.Put(tx)
+		if special {
+			pool.special.add(hash, function)
+		}
 		pool.journalTx(from, tx)
 
 		log.Trace("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To())
 
 		// We've directly injected a replacement transaction, notify subsystems
-		go pool.txFeed.Send(TxPreEvent{tx})
+		go pool.txFeed.Send(TxPreEvent{Tx: tx, Private: pool.private[hash] != nil})
 
 		return old != nil, nil
 	}
@@ -717,6 +920,9 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if special {
+		pool.special.add(hash, function)
+	}
 	// Mark local addresses and journal local transactions
 	if local {
 		pool.locals.add(from)
@@ -727,6 +933,27 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	return replace, nil
 }
 
+// checkSpecialLane checks whether the special transaction fits into the special
+// transaction lane. Replacements of the already pooled transactions are always
+// accepted, the replaced one frees its slot.
+//
+// Note, this method assumes the pool lock is held and the lane is synced!
+func (pool *TxPool) checkSpecialLane(from common.Address, tx *types.Transaction, function intAbi.FunctionType) error {
+	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
+		return nil
+	}
+	if list := pool.queue[from]; list != nil && list.Overlaps(tx) {
+		return nil
+	}
+	if uint64(pool.special.len()) >= pool.config.SpecialSlots {
+		return ErrSpecialLaneFull
+	}
+	if pool.special.count(function) >= pool.config.SpecialFunctionSlots {
+		return ErrSpecialQuotaExceeded
+	}
+	return nil
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -744,8 +971,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	}
 	// Discard any previous transaction and mark this
 	if old != nil {
-		delete(pool.all, old.Hash())
-		pool.priced.Removed()
+		pool.dropTx(old, TxDropReplaced, tx)
 		queuedReplaceCounter.Inc(1)
 	}
 	pool.all[hash] = tx
@@ -760,6 +986,10 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || !pool.locals.contains(from) {
 		return
 	}
+	// Private transactions don't survive restarts, they would be reloaded as public ones
+	if pool.private[tx.Hash()] != nil {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
@@ -778,16 +1008,14 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 		// An older transaction was better, discard this
-		delete(pool.all, hash)
-		pool.priced.Removed()
+		pool.dropTx(tx, TxDropUnderpriced, nil)
 
 		pendingDiscardCounter.Inc(1)
 		return
 	}
 	// Otherwise discard any previous transaction and mark this
 	if old != nil {
-		delete(pool.all, old.Hash())
-		pool.priced.Removed()
+		pool.dropTx(old, TxDropReplaced, tx)
 
 		pendingReplaceCounter.Inc(1)
 	}
@@ -800,7 +1028,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	pool.beats[addr] = time.Now()
 	pool.pendingState.SetNonce(addr, tx.Nonce()+1)
 
-	go pool.txFeed.Send(TxPreEvent{tx})
+	go pool.txFeed.Send(TxPreEvent{Tx: tx, Private: pool.private[hash] != nil})
 }
 
 // AddLocal enqueues a single transaction into the pool if it is valid, marking
@@ -849,6 +1077,94 @@ func (pool *TxPool) addTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// AddPrivate enqueues a single private transaction into the pool if it is valid.
+// Private transactions are never propagated to the network by the pool, they are
+// dropped if not included within maxBlocks blocks, capped by the configured private
+// blocks. Zero maxBlocks keeps the transaction for the configured private blocks,
+// as for the transactions received from the network. The relay flag marks the
+// transactions to be handed out by PrivateTxs.
+func (pool *TxPool) AddPrivate(tx *types.Transaction, maxBlocks uint64, relay bool) error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if maxBlocks == 0 || maxBlocks > pool.config.PrivateBlocks {
+		maxBlocks = pool.config.PrivateBlocks
+	}
+	expiry := pool.chain.CurrentBlock().NumberU64() + maxBlocks
+
+	// Mark the transaction before adding, so that no event announces it as public
+	hash := tx.Hash()
+	if pool.all[hash] != nil {
+		log.Trace("Discarding already known private transaction", "hash", hash)
+		return fmt.Errorf("known transaction: %x", hash)
+	}
+	pool.private[hash] = &privateTx{expiry: expiry, relay: relay}
+
+	replace, err := pool.add(tx, false)
+	if err != nil {
+		delete(pool.private, hash)
+		return err
+	}
+	if !replace {
+		from, _ := types.Sender(pool.signer, tx) // already validated
+		pool.promoteExecutables([]common.Address{from})
+	}
+	return nil
+}
+
+// PrivateTxs retrieves the pooled private transactions marked for relaying.
+func (pool *TxPool) PrivateTxs() types.Transactions {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	txs := make(types.Transactions, 0, len(pool.private))
+	for hash, private := range pool.private {
+		if tx := pool.all[hash]; tx != nil && private.relay {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// IsPrivate reports whether the transaction is pooled as a private one.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.private[hash] != nil
+}
+
+// public filters the private transactions out of the given list.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) public(txs types.Transactions) types.Transactions {
+	public := txs[:0:0]
+	for _, tx := range txs {
+		if pool.private[tx.Hash()] == nil {
+			public = append(public, tx)
+		}
+	}
+	return public
+}
+
+// expirePrivates forgets the private transactions which left the pool and drops
+// the ones not included until their expiry block.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) expirePrivates(head uint64) {
+	for hash, private := range pool.private {
+		if pool.all[hash] == nil {
+			delete(pool.private, hash)
+			continue
+		}
+		if head >= private.expiry {
+			log.Trace("Removed expired private transaction", "hash", hash, "expiry", private.expiry)
+			pool.removeTx(hash, TxDropExpired)
+			delete(pool.private, hash)
+		}
+	}
+}
+
 // addTxs attempts to queue a batch of transactions if they are valid.
 func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 	pool.mu.Lock()
@@ -919,13 +1235,31 @@ func (pool *TxPool) RemoveTxs(txs types.Transactions) {
 	defer pool.mu.Unlock()
 
 	for _, tx := range txs {
-		pool.removeTx(tx.Hash())
+		pool.removeTx(tx.Hash(), TxDropInvalid)
+	}
+}
+
+// dropTx removes a transaction from the lookup and the price index, notifying
+// the subscribers about the reason of the drop.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) dropTx(tx *types.Transaction, reason TxDropReason, replacement *types.Transaction) {
+	delete(pool.all, tx.Hash())
+	pool.priced.Removed()
+
+	pool.dropMu.Lock()
+	pool.drops = append(pool.drops, TxDropEvent{Tx: tx, Reason: reason, Replacement: replacement})
+	pool.dropMu.Unlock()
+
+	select {
+	case pool.dropReqCh <- struct{}{}:
+	default:
 	}
 }
 
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
-func (pool *TxPool) removeTx(hash common.Hash) {
+func (pool *TxPool) removeTx(hash common.Hash, reason TxDropReason) {
 	// Fetch the transaction we wish to delete
 	tx, ok := pool.all[hash]
 	if !ok {
@@ -934,8 +1268,7 @@ func (pool *TxPool) removeTx(hash common.Hash) {
 	addr, _ := types.Sender(pool.signer, tx) // already validated during insertion
 
 	// Remove it from the list of known transactions
-	delete(pool.all, hash)
-	pool.priced.Removed()
+	pool.dropTx(tx, reason, nil)
 
 	// Remove the transaction from the pending lists and reset the account nonce
 	if pending := pool.pending[addr]; pending != nil {
@@ -986,16 +1319,14 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		for _, tx := range list.Forward(pool.currentState.GetNonce(addr)) {
 			hash := tx.Hash()
 			log.Trace("Removed old queued transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.dropTx(tx, TxDropStale, nil)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable queued transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.dropTx(tx, TxDropUnpayable, nil)
 			queuedNofundsCounter.Inc(1)
 		}
 		// Gather all executable transactions and promote them
@@ -1008,8 +1339,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 		if !pool.locals.contains(addr) {
 			for _, tx := range list.Cap(int(pool.config.AccountQueue)) {
 				hash := tx.Hash()
-				delete(pool.all, hash)
-				pool.priced.Removed()
+				pool.dropTx(tx, TxDropRateLimit, nil)
 				queuedRateLimitCounter.Inc(1)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
@@ -1019,11 +1349,13 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			delete(pool.queue, addr)
 		}
 	}
-	// If the pending limit is overflown, start equalizing allowances
+	// If the pending limit is overflown, start equalizing allowances. The special
+	// transactions are not counted, they are bounded by their own lane.
 	pending := uint64(0)
 	for _, list := range pool.pending {
 		pending += uint64(list.Len())
 	}
+	pending -= pool.pendingSpecials()
 	if pending > pool.config.GlobalSlots {
 		pendingBeforeCap := pending
 		// Assemble a spam order to penalize large transactors first
@@ -1053,8 +1385,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 						for _, tx := range list.Cap(list.Len() - 1) {
 							// Drop the transaction from the global pools too
 							hash := tx.Hash()
-							delete(pool.all, hash)
-							pool.priced.Removed()
+							pool.dropTx(tx, TxDropRateLimit, nil)
 
 							// Update the account nonce to the dropped transaction
 							if nonce := tx.Nonce(); pool.pendingState.GetNonce(offenders[i]) > nonce {
@@ -1075,8 +1406,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 					for _, tx := range list.Cap(list.Len() - 1) {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
-						delete(pool.all, hash)
-						pool.priced.Removed()
+						pool.dropTx(tx, TxDropRateLimit, nil)
 
 						// Update the account nonce to the dropped transaction
 						if nonce := tx.Nonce(); pool.pendingState.GetNonce(addr) > nonce {
@@ -1115,7 +1445,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			// Drop all transactions if they are less than the overflow
 			if size := uint64(list.Len()); size <= drop {
 				for _, tx := range list.Flatten() {
-					pool.removeTx(tx.Hash())
+					pool.removeTx(tx.Hash(), TxDropRateLimit)
 				}
 				drop -= size
 				queuedRateLimitCounter.Inc(int64(size))
@@ -1124,7 +1454,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			// Otherwise drop only last few transactions
 			txs := list.Flatten()
 			for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
-				pool.removeTx(txs[i].Hash())
+				pool.removeTx(txs[i].Hash(), TxDropRateLimit)
 				drop--
 				queuedRateLimitCounter.Inc(1)
 			}
@@ -1132,6 +1462,25 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 	}
 }
 
+// pendingSpecials returns the number of special transactions in the pending lists.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) pendingSpecials() uint64 {
+	pool.special.sync(pool.all)
+
+	count := uint64(0)
+	for hash := range pool.special.txs {
+		tx := pool.all[hash]
+		from, _ := types.Sender(pool.signer, tx) // already validated
+		if list := pool.pending[from]; list != nil {
+			if pending := list.txs.Get(tx.Nonce()); pending != nil && pending.Hash() == hash {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 // demoteUnexecutables removes invalid and processed transactions from the pools
 // executable/pending queue and any subsequent transactions that become unexecutable
 // are moved back into the future queue.
@@ -1144,16 +1493,14 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range list.Forward(nonce) {
 			hash := tx.Hash()
 			log.Trace("Removed old pending transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.dropTx(tx, TxDropStale, nil)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
 		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
-			delete(pool.all, hash)
-			pool.priced.Removed()
+			pool.dropTx(tx, TxDropUnpayable, nil)
 			pendingNofundsCounter.Inc(1)
 		}
 		for _, tx := range invalids {
@@ -1224,3 +1571,74 @@ func (as *accountSet) containsTx(tx *types.Transaction) bool {
 func (as *accountSet) add(addr common.Address) {
 	as.accounts[addr] = struct{}{}
 }
+
+// specialLane tracks the special IntChain transactions of the pool and the
+// number of them per function. The transactions leave the lane lazily, the
+// lane is synced with the pool before its limits are checked.
+type specialLane struct {
+	txs    map[common.Hash]intAbi.FunctionType
+	counts map[intAbi.FunctionType]uint64
+}
+
+// newSpecialLane creates a new empty special transaction lane.
+func newSpecialLane() *specialLane {
+	return &specialLane{
+		txs:    make(map[common.Hash]intAbi.FunctionType),
+		counts: make(map[intAbi.FunctionType]uint64),
+	}
+}
+
+// add inserts a new special transaction into the lane.
+func (l *specialLane) add(hash common.Hash, function intAbi.FunctionType) {
+	if _, ok := l.txs[hash]; ok {
+		return
+	}
+	l.txs[hash] = function
+	l.counts[function]++
+}
+
+// sync drops the transactions which are no longer contained in the pool.
+func (l *specialLane) sync(all map[common.Hash]*types.Transaction) {
+	for hash, function := range l.txs {
+		if _, ok := all[hash]; !ok {
+			delete(l.txs, hash)
+			if l.counts[function]--; l.counts[function] == 0 {
+				delete(l.counts, function)
+			}
+		}
+	}
+}
+
+// len returns the number of transactions in the lane.
+func (l *specialLane) len() int {
+	return len(l.txs)
+}
+
+// count returns the number of transactions of the function in the lane.
+func (l *specialLane) count(function intAbi.FunctionType) uint64 {
+	return l.counts[function]
+}
+
+// privateTx tracks a private transaction of the pool.
+type privateTx struct {
+	expiry uint64 // Block number the transaction is dropped at if not included
+	relay  bool   // Whether the transaction is relayed to the proposers by this node
+}
+
+// specialFunction returns the function of a special IntChain transaction and
+// whether the transaction is a special one at all.
+func specialFunction(tx *types.Transaction) (intAbi.FunctionType, bool) {
+	if !isSpecialTx(tx) || len(tx.Data()) < 4 {
+		return intAbi.Unknown, false
+	}
+	function, err := intAbi.FunctionTypeFromId(tx.Data()[:4])
+	if err != nil {
+		return intAbi.Unknown, false
+	}
+	return function, true
+}
+
+// isSpecialTx reports whether the transaction calls the IntChain contract.
+func isSpecialTx(tx *types.Transaction) bool {
+	return intAbi.IsIntChainContractAddr(tx.To())
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/crypto"
 	"github.com/intfoundation/intchain/event"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
 	"github.com/intfoundation/intchain/params"
 )
 
@@ -350,7 +351,7 @@ func TestTransactionChainFork(t *testing.T) {
 	if _, err := pool.add(tx, false); err != nil {
 		t.Error("didn't expect error", err)
 	}
-	pool.removeTx(tx.Hash())
+	pool.removeTx(tx.Hash(), TxDropInvalid)
 
 	// reset the pool's internal state
 	resetState()
@@ -1681,6 +1682,175 @@ func TestTransactionStatusCheck(t *testing.T) {
 	}
 }
 
+// signedTransaction creates a transaction signed with the signer of the test
+// chain, replay protected as required by the pool.
+func signedTransaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), gaslimit, big.NewInt(1), nil), types.LatestSigner(params.TestChainConfig), key)
+	return tx
+}
+
+// Tests that private transactions are kept out of the gossip, expire at the block
+// decided by the local pool and only the relayed ones are handed out.
+func TestTransactionPrivate(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupTxPool()
+	defer pool.Stop()
+
+	events := make(chan TxPreEvent, 8)
+	sub := pool.SubscribeTxPreEvent(events)
+	defer sub.Unsubscribe()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	relayed := signedTransaction(0, 100000, keys[0])
+	received := signedTransaction(0, 100000, keys[1])
+	capped := signedTransaction(0, 100000, keys[2])
+
+	if err := pool.AddPrivate(relayed, 5, true); err != nil {
+		t.Fatalf("failed to add relayed private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(received, 0, false); err != nil {
+		t.Fatalf("failed to add received private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(capped, testTxPoolConfig.PrivateBlocks+10, false); err != nil {
+		t.Fatalf("failed to add capped private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(relayed, 5, true); err == nil {
+		t.Fatalf("known private transaction added again")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// All the transactions are announced as private ones
+	for i := 0; i < 3; i++ {
+		select {
+		case ev := <-events:
+			if !ev.Private {
+				t.Errorf("transaction %x announced as public", ev.Tx.Hash())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not fired", i)
+		}
+	}
+	// Only the relayed transaction is handed out for relaying
+	if txs := pool.PrivateTxs(); len(txs) != 1 || txs[0].Hash() != relayed.Hash() {
+		t.Fatalf("relayed private transactions mismatch: have %v, want %x", txs, relayed.Hash())
+	}
+	// The expiry is decided by the local configuration
+	for _, tt := range []struct {
+		tx     *types.Transaction
+		expiry uint64
+	}{
+		{relayed, 5},
+		{received, testTxPoolConfig.PrivateBlocks},
+		{capped, testTxPoolConfig.PrivateBlocks},
+	} {
+		if !pool.IsPrivate(tt.tx.Hash()) {
+			t.Fatalf("transaction %x not private", tt.tx.Hash())
+		}
+		if expiry := pool.private[tt.tx.Hash()].expiry; expiry != tt.expiry {
+			t.Errorf("transaction %x expiry mismatch: have %d, want %d", tt.tx.Hash(), expiry, tt.expiry)
+		}
+	}
+	// The transactions not included until their expiry are dropped
+	pool.mu.Lock()
+	pool.expirePrivates(5)
+	pool.mu.Unlock()
+
+	if pool.Get(relayed.Hash()) != nil || pool.IsPrivate(relayed.Hash()) {
+		t.Errorf("expired private transaction not dropped")
+	}
+	if pool.Get(received.Hash()) == nil || !pool.IsPrivate(received.Hash()) {
+		t.Errorf("private transaction dropped before expiry")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that only the special transactions in front of the first ordinary one of
+// each account are handed out for the reserved lane of the miner.
+func TestTransactionSpecialPending(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	other, _ := crypto.GenerateKey()
+	var (
+		account      = crypto.PubkeyToAddress(key.PublicKey)
+		otherAccount = crypto.PubkeyToAddress(other.PublicKey)
+	)
+	txs := types.Transactions{
+		specialTransaction(0, big.NewInt(1), key),
+		specialTransaction(1, big.NewInt(1), key),
+		transaction(2, 100000, key),
+		specialTransaction(3, big.NewInt(1), key),
+	}
+	for _, tx := range txs {
+		pool.promoteTx(account, tx.Hash(), tx)
+	}
+	for _, tx := range (types.Transactions{transaction(0, 100000, other), specialTransaction(1, big.NewInt(1), other)}) {
+		pool.promoteTx(otherAccount, tx.Hash(), tx)
+	}
+
+	special := pool.SpecialPending()
+	if len(special) != 1 {
+		t.Fatalf("special account count mismatch: have %d, want %d", len(special), 1)
+	}
+	if have := special[account]; len(have) != 2 || have[0] != txs[0] || have[1] != txs[1] {
+		t.Errorf("special transactions mismatch: have %v, want %v", have, txs[:2])
+	}
+}
+
+// Tests that the special transaction lane enforces its own limits, while the
+// replacements of the pooled transactions are always accepted.
+func TestTransactionSpecialLaneLimits(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	pool.config.SpecialSlots = 3
+	pool.config.SpecialFunctionSlots = 2
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	for i := 0; i < 2; i++ {
+		tx := specialTransaction(uint64(i), big.NewInt(1), key)
+		pool.all[tx.Hash()] = tx
+		pool.special.add(tx.Hash(), intAbi.Delegate)
+		pool.promoteTx(account, tx.Hash(), tx)
+	}
+	next := specialTransaction(2, big.NewInt(1), key)
+	if err := pool.checkSpecialLane(account, next, intAbi.Delegate); err != ErrSpecialQuotaExceeded {
+		t.Errorf("function quota error mismatch: have %v, want %v", err, ErrSpecialQuotaExceeded)
+	}
+	if err := pool.checkSpecialLane(account, next, intAbi.UnDelegate); err != nil {
+		t.Errorf("other function rejected: %v", err)
+	}
+	replacement := specialTransaction(1, big.NewInt(2), key)
+	if err := pool.checkSpecialLane(account, replacement, intAbi.Delegate); err != nil {
+		t.Errorf("replacement rejected: %v", err)
+	}
+
+	tx := specialTransaction(2, big.NewInt(1), key)
+	pool.all[tx.Hash()] = tx
+	pool.special.add(tx.Hash(), intAbi.UnDelegate)
+	if err := pool.checkSpecialLane(account, specialTransaction(3, big.NewInt(1), key), intAbi.UnDelegate); err != ErrSpecialLaneFull {
+		t.Errorf("lane limit error mismatch: have %v, want %v", err, ErrSpecialLaneFull)
+	}
+	// The transactions leaving the pool free their slots
+	delete(pool.all, tx.Hash())
+	pool.special.sync(pool.all)
+	if err := pool.checkSpecialLane(account, specialTransaction(3, big.NewInt(1), key), intAbi.UnDelegate); err != nil {
+		t.Errorf("freed slot not reused: %v", err)
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
@@ -1777,3 +1947,38 @@ func benchmarkPoolBatchInsert(b *testing.B, size int) {
 		pool.AddRemotes(batch)
 	}
 }
+
+// Tests that the drop events are delivered in the order of the drops.
+func TestTransactionDropEventOrder(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	events := make(chan TxDropEvent, 64)
+	sub := pool.SubscribeTxDropEvent(events)
+	defer sub.Unsubscribe()
+
+	txs := make(types.Transactions, 64)
+	pool.mu.Lock()
+	for i := range txs {
+		txs[i] = signedTransaction(uint64(i), 100000, key)
+		pool.all[txs[i].Hash()] = txs[i]
+		pool.dropTx(txs[i], TxDropStale, nil)
+	}
+	pool.mu.Unlock()
+
+	for i, tx := range txs {
+		select {
+		case ev := <-events:
+			if ev.Tx.Hash() != tx.Hash() {
+				t.Fatalf("drop event %d: transaction mismatch: have nonce %d, want %d", i, ev.Tx.Nonce(), tx.Nonce())
+			}
+			if ev.Reason != TxDropStale {
+				t.Errorf("drop event %d: reason mismatch: have %s, want %s", i, ev.Reason, TxDropStale)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("drop event %d not fired", i)
+		}
+	}
+}
@@ -90,6 +90,9 @@ type Header struct {
 
 	// For Child Chain only
 	MainChainNumber *big.Int `json:"mainNumber"           gencodec:"required"`
+
+	// BaseFee was added by the London fork and is ignored in legacy headers.
+	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -100,6 +103,7 @@ type headerMarshaling struct {
 	GasUsed    hexutil.Uint64
 	Time       *hexutil.Big
 	Extra      hexutil.Bytes
+	BaseFee    *hexutil.Big
 	Hash       common.Hash `json:"hash"` // adds call to Hash() in MarshalJSON
 }
 
@@ -153,7 +157,11 @@ func (h *Header) HashNoNonce() common.Hash {
 // Size returns the approximate memory used by all internal contents. It is used
 // to approximate and limit the memory consumption of various caches.
 func (h *Header) Size() common.StorageSize {
-	return common.StorageSize(unsafe.Sizeof(*h)) + common.StorageSize(len(h.Extra)+(h.Difficulty.BitLen()+h.Number.BitLen()+h.Time.BitLen())/8)
+	size := common.StorageSize(unsafe.Sizeof(*h)) + common.StorageSize(len(h.Extra)+(h.Difficulty.BitLen()+h.Number.BitLen()+h.Time.BitLen())/8)
+	if h.BaseFee != nil {
+		size += common.StorageSize(h.BaseFee.BitLen() / 8)
+	}
+	return size
 }
 
 func rlpHash(x interface{}) (h common.Hash) {
@@ -163,6 +171,16 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding the
+// given interface. It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	hw.Write([]byte{prefix})
+	rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
+
 // Body is a simple (mutable, non-safe) data container for storing and moving
 // a block's data contents (transactions and uncles) together.
 type Body struct {
@@ -277,6 +295,9 @@ func CopyHeader(h *Header) *Header {
 	if cpy.Number = new(big.Int); h.Number != nil {
 		cpy.Number.Set(h.Number)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	if len(h.Extra) > 0 {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
@@ -295,6 +316,9 @@ func CopyWithoutTimeHeader(h *Header) *Header {
 	if cpy.Number = new(big.Int); h.Number != nil {
 		cpy.Number.Set(h.Number)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	if len(h.Extra) > 0 {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
@@ -365,6 +389,14 @@ func (b *Block) ReceiptHash() common.Hash { return b.header.ReceiptHash }
 func (b *Block) UncleHash() common.Hash   { return b.header.UncleHash }
 func (b *Block) Extra() []byte            { return common.CopyBytes(b.header.Extra) }
 
+// BaseFee returns the base fee per gas of the block, nil before the London fork.
+func (b *Block) BaseFee() *big.Int {
+	if b.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(b.header.BaseFee)
+}
+
 func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
@@ -465,7 +497,8 @@ func (h *Header) String() string {
 	Extra:		    %x
 	MixDigest:      %x
 	Nonce:		    %x
-]`, h.Hash(), h.ParentHash, h.UncleHash, h.Coinbase.String(), h.Root, h.TxHash, h.ReceiptHash, h.Bloom, h.Difficulty, h.Number, h.GasLimit, h.GasUsed, h.Time, h.Extra, h.MixDigest, h.Nonce)
+	BaseFee:	    %v
+]`, h.Hash(), h.ParentHash, h.UncleHash, h.Coinbase.String(), h.Root, h.TxHash, h.ReceiptHash, h.Bloom, h.Difficulty, h.Number, h.GasLimit, h.GasUsed, h.Time, h.Extra, h.MixDigest, h.Nonce, h.BaseFee)
 }
 
 type Blocks []*Block
@@ -498,6 +531,33 @@ type ChildChainProofData struct {
 	Header *Header
 }
 
+// MaxChildChainProofBatch is the max number of headers in one ChildChainProofDataBatch
+const MaxChildChainProofBatch = 32
+
+// ChildChainProofDataBatch represents a batch of epochs from child chain to the main chain,
+// which is saved by one SaveDataToMainChain tx. Headers are in ascending order of height.
+type ChildChainProofDataBatch struct {
+	Headers []*Header
+}
+
+// DecodeChildChainProofData decodes the data of the SaveDataToMainChain tx into the child chain headers,
+// the data is either the rlp of ChildChainProofData or ChildChainProofDataBatch.
+func DecodeChildChainProofData(bs []byte) ([]*Header, error) {
+	var proofData ChildChainProofData
+	if err := rlp.DecodeBytes(bs, &proofData); err == nil {
+		return []*Header{proofData.Header}, nil
+	}
+
+	var batch ChildChainProofDataBatch
+	if err := rlp.DecodeBytes(bs, &batch); err != nil {
+		return nil, err
+	}
+	if len(batch.Headers) == 0 || len(batch.Headers) > MaxChildChainProofBatch {
+		return nil, fmt.Errorf("invalid child chain proof batch size: %d", len(batch.Headers))
+	}
+	return batch.Headers, nil
+}
+
 // TX3ProofData represents proof of tx3 from child chain to the main chain.
 type TX3ProofData struct {
 	Header *Header
@@ -514,6 +574,21 @@ func NewChildChainProofData(block *Block) (*ChildChainProofData, error) {
 	return ret, nil
 }
 
+func NewChildChainProofDataBatch(blocks []*Block) (*ChildChainProofDataBatch, error) {
+	if len(blocks) == 0 || len(blocks) > MaxChildChainProofBatch {
+		return nil, fmt.Errorf("invalid child chain proof batch size: %d", len(blocks))
+	}
+
+	ret := &ChildChainProofDataBatch{
+		Headers: make([]*Header, len(blocks)),
+	}
+	for i, block := range blocks {
+		ret.Headers[i] = block.Header()
+	}
+
+	return ret, nil
+}
+
 func NewTX3ProofData(block *Block) (*TX3ProofData, error) {
 	ret := &TX3ProofData{
 		Header: block.Header(),
@@ -537,7 +612,7 @@ func NewTX3ProofData(block *Block) (*TX3ProofData, error) {
 				continue
 			}
 
-			if function == intAbi.WithdrawFromChildChain {
+			if intAbi.IsTX3Function(function) {
 				kvSet := MakeBSKeyValueSet()
 				keybuf.Reset()
 				rlp.Encode(keybuf, uint(i))
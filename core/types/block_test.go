@@ -55,7 +55,7 @@ func TestBlockEncoding(t *testing.T) {
 
 	tx1, _ = tx1.WithSignature(HomesteadSigner{}, common.Hex2Bytes("9bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094f8a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b100"))
 	fmt.Println(block.Transactions()[0].Hash())
-	fmt.Println(tx1.data)
+	fmt.Println(tx1)
 	fmt.Println(tx1.Hash())
 	check("len(Transactions)", len(block.Transactions()), 1)
 	check("Transactions[0].Hash", block.Transactions()[0].Hash(), tx1.Hash())
@@ -68,3 +68,47 @@ func TestBlockEncoding(t *testing.T) {
 		t.Errorf("encoded block mismatch:\ngot:  %x\nwant: %x", ourBlockEnc, blockEnc)
 	}
 }
+
+func TestDecodeChildChainProofData(t *testing.T) {
+	blocks := []*Block{
+		NewBlockWithHeader(&Header{Number: big.NewInt(10), Extra: []byte("epoch1")}),
+		NewBlockWithHeader(&Header{Number: big.NewInt(20), Extra: []byte("epoch2")}),
+	}
+
+	// single proof data
+	single, _ := NewChildChainProofData(blocks[0])
+	bs, err := rlp.EncodeToBytes(single)
+	if err != nil {
+		t.Fatalf("failed to encode proof data: %v", err)
+	}
+	headers, err := DecodeChildChainProofData(bs)
+	if err != nil {
+		t.Fatalf("failed to decode proof data: %v", err)
+	}
+	if len(headers) != 1 || headers[0].Hash() != blocks[0].Hash() {
+		t.Fatalf("single proof data mismatch: %v", headers)
+	}
+
+	// batch proof data
+	batch, err := NewChildChainProofDataBatch(blocks)
+	if err != nil {
+		t.Fatalf("failed to create proof data batch: %v", err)
+	}
+	bs, err = rlp.EncodeToBytes(batch)
+	if err != nil {
+		t.Fatalf("failed to encode proof data batch: %v", err)
+	}
+	headers, err = DecodeChildChainProofData(bs)
+	if err != nil {
+		t.Fatalf("failed to decode proof data batch: %v", err)
+	}
+	if len(headers) != 2 || headers[0].Hash() != blocks[0].Hash() || headers[1].Hash() != blocks[1].Hash() {
+		t.Fatalf("batch proof data mismatch: %v", headers)
+	}
+
+	// empty batch
+	bs, _ = rlp.EncodeToBytes(&ChildChainProofDataBatch{})
+	if _, err := DecodeChildChainProofData(bs); err == nil {
+		t.Fatalf("empty batch decoded without error")
+	}
+}
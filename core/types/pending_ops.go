@@ -55,6 +55,25 @@ func (op *CreateChildChainOp) String() string {
 		op.From, op.ChainId, op.MinValidators, op.MinDepositAmount, op.StartBlock, op.EndBlock)
 }
 
+// SetChildChainGenesis op
+type SetChildChainGenesisOp struct {
+	From            common.Address
+	ChainId         string
+	GenesisTemplate []byte
+}
+
+func (op *SetChildChainGenesisOp) Conflict(op1 PendingOp) bool {
+	if op1, ok := op1.(*SetChildChainGenesisOp); ok {
+		return op.ChainId == op1.ChainId
+	}
+	return false
+}
+
+func (op *SetChildChainGenesisOp) String() string {
+	return fmt.Sprintf("SetChildChainGenesisOp - From: %x, ChainId: %s, GenesisTemplate: %d bytes",
+		op.From, op.ChainId, len(op.GenesisTemplate))
+}
+
 // JoinChildChain op
 type JoinChildChainOp struct {
 	From          common.Address
@@ -0,0 +1,121 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/rlp"
+)
+
+func newTestReceipt(txType uint8) *Receipt {
+	receipt := &Receipt{
+		Type:              txType,
+		Status:            ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs: []*Log{
+			{Address: common.BytesToAddress([]byte{0x11}), Topics: []common.Hash{common.HexToHash("dead"), common.HexToHash("beef")}, Data: []byte{0x01, 0x00, 0xff}},
+			{Address: common.BytesToAddress([]byte{0x01, 0x11}), Topics: []common.Hash{common.HexToHash("dead")}, Data: []byte{0x02}},
+		},
+	}
+	receipt.Bloom = CreateBloom(Receipts{receipt})
+	return receipt
+}
+
+// Tests that the receipts of every transaction type survive the consensus
+// encoding, both the RLP and the binary one.
+func TestReceiptEncodingRoundTrip(t *testing.T) {
+	for _, txType := range []uint8{LegacyTxType, AccessListTxType, DynamicFeeTxType} {
+		want := newTestReceipt(txType)
+
+		enc, err := rlp.EncodeToBytes(want)
+		if err != nil {
+			t.Fatalf("type %d: failed to encode receipt: %v", txType, err)
+		}
+		have := new(Receipt)
+		if err := rlp.DecodeBytes(enc, have); err != nil {
+			t.Fatalf("type %d: failed to decode receipt: %v", txType, err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("type %d: RLP round trip mismatch: have %+v, want %+v", txType, have, want)
+		}
+
+		bin, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("type %d: failed to marshal receipt: %v", txType, err)
+		}
+		have = new(Receipt)
+		if err := have.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("type %d: failed to unmarshal receipt: %v", txType, err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("type %d: binary round trip mismatch: have %+v, want %+v", txType, have, want)
+		}
+	}
+}
+
+// Tests that the receipts of unknown transaction types are rejected.
+func TestReceiptUnknownType(t *testing.T) {
+	bin, err := newTestReceipt(DynamicFeeTxType + 1).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal receipt: %v", err)
+	}
+	if err := new(Receipt).UnmarshalBinary(bin); err != ErrTxTypeNotSupported {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	if err := new(Receipt).UnmarshalBinary(nil); err != errEmptyTypedReceipt {
+		t.Fatalf("error mismatch: have %v, want %v", err, errEmptyTypedReceipt)
+	}
+}
+
+// Tests that the storage encoding keeps the type of the receipts, and leaves it
+// out for the legacy receipts.
+func TestReceiptStorageRoundTrip(t *testing.T) {
+	for _, txType := range []uint8{LegacyTxType, AccessListTxType, DynamicFeeTxType} {
+		want := newTestReceipt(txType)
+		want.TxHash = common.HexToHash("0x01")
+		want.ContractAddress = common.BytesToAddress([]byte{0x22})
+		want.GasUsed = 21000
+
+		enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(want))
+		if err != nil {
+			t.Fatalf("type %d: failed to encode receipt: %v", txType, err)
+		}
+		have := new(ReceiptForStorage)
+		if err := rlp.DecodeBytes(enc, have); err != nil {
+			t.Fatalf("type %d: failed to decode receipt: %v", txType, err)
+		}
+		if !reflect.DeepEqual((*Receipt)(have), want) {
+			t.Errorf("type %d: storage round trip mismatch: have %+v, want %+v", txType, have, want)
+		}
+	}
+	// The legacy receipts are stored without the type
+	receipt := newTestReceipt(LegacyTxType)
+	enc, err := rlp.EncodeToBytes((*ReceiptForStorage)(receipt))
+	if err != nil {
+		t.Fatalf("failed to encode receipt: %v", err)
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(enc, &fields); err != nil {
+		t.Fatalf("failed to decode receipt fields: %v", err)
+	}
+	if len(fields) != 6 {
+		t.Errorf("legacy receipt field count mismatch: have %d, want %d", len(fields), 6)
+	}
+}
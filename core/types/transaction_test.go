@@ -52,6 +52,23 @@ var (
 		HomesteadSigner{},
 		common.Hex2Bytes("98ff921201554726367d2be8c804a7ff89ccf285ebc57dff8ae4c44b9c19ac4a8887321be575c8095f789dd4c743dfe42c1820f9231f98a962b210e3ac2452a301"),
 	)
+
+	testAddr = common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+
+	emptyEip2718Tx = NewTx(&AccessListTx{
+		ChainID:  big.NewInt(1),
+		Nonce:    3,
+		To:       &testAddr,
+		Value:    big.NewInt(10),
+		Gas:      25000,
+		GasPrice: big.NewInt(1),
+		Data:     common.FromHex("5544"),
+	})
+
+	signedEip2718Tx, _ = emptyEip2718Tx.WithSignature(
+		NewEIP2930Signer(big.NewInt(1)),
+		common.Hex2Bytes("c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b266032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d3752101"),
+	)
 )
 
 func TestTransactionSigHash(t *testing.T) {
@@ -75,6 +92,182 @@ func TestTransactionEncode(t *testing.T) {
 	}
 }
 
+func TestEIP2718TransactionSigHash(t *testing.T) {
+	s := NewEIP2930Signer(big.NewInt(1))
+	if s.Hash(emptyEip2718Tx) != common.HexToHash("49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3") {
+		t.Errorf("empty EIP-2718 transaction hash mismatch, got %x", s.Hash(emptyEip2718Tx))
+	}
+	if s.Hash(signedEip2718Tx) != common.HexToHash("49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3") {
+		t.Errorf("signed EIP-2718 transaction hash mismatch, got %x", s.Hash(signedEip2718Tx))
+	}
+}
+
+func TestEIP2718TransactionEncode(t *testing.T) {
+	// RLP representation
+	have, err := rlp.EncodeToBytes(signedEip2718Tx)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	want := common.FromHex("b86601f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521")
+	if !bytes.Equal(have, want) {
+		t.Errorf("encoded RLP mismatch, got %x", have)
+	}
+	// Binary representation
+	have, err = signedEip2718Tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	want = common.FromHex("01f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521")
+	if !bytes.Equal(have, want) {
+		t.Errorf("encoded binary mismatch, got %x", have)
+	}
+}
+
+func TestAccessListTxSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	signer := NewEIP2930Signer(big.NewInt(2047))
+
+	tx, err := SignTx(NewTx(&AccessListTx{
+		ChainID:  big.NewInt(2047),
+		Nonce:    1,
+		To:       &testAddr,
+		Gas:      50000,
+		GasPrice: big.NewInt(1),
+		AccessList: AccessList{
+			{Address: testAddr, StorageKeys: []common.Hash{{1}, {2}}},
+		},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	// Decode the canonical encoding, the hash, the sender and the access list must
+	// survive the round trip
+	blob, _ := tx.MarshalBinary()
+	var dec Transaction
+	if err := dec.UnmarshalBinary(blob); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	if dec.Type() != AccessListTxType || dec.AccessList().StorageKeys() != 2 {
+		t.Errorf("type or access list mismatch: type %d, access list %v", dec.Type(), dec.AccessList())
+	}
+	if from, err := Sender(signer, &dec); err != nil || from != addr {
+		t.Errorf("sender mismatch: have %x (%v), want %x", from, err, addr)
+	}
+	// The legacy signers reject the typed transactions
+	if _, err := Sender(NewEIP155Signer(big.NewInt(2047)), &dec); err != ErrTxTypeNotSupported {
+		t.Errorf("legacy signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	// The signer of another chain rejects the transaction
+	if _, err := Sender(NewEIP2930Signer(big.NewInt(2048)), &dec); err != ErrInvalidChainId {
+		t.Errorf("chain id error mismatch: have %v, want %v", err, ErrInvalidChainId)
+	}
+	// JSON round trip
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("json encode error: %v", err)
+	}
+	var parsed Transaction
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("json decode error: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("json hash mismatch: have %x, want %x", parsed.Hash(), tx.Hash())
+	}
+}
+
+func TestDynamicFeeTxSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	signer := NewLondonSigner(big.NewInt(2047))
+
+	tx, err := SignTx(NewTx(&DynamicFeeTx{
+		ChainID:   big.NewInt(2047),
+		Nonce:     1,
+		To:        &testAddr,
+		Gas:       50000,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(10),
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	blob, _ := tx.MarshalBinary()
+	var dec Transaction
+	if err := dec.UnmarshalBinary(blob); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if dec.Hash() != tx.Hash() || dec.Type() != DynamicFeeTxType {
+		t.Errorf("decoded mismatch: hash %x, type %d", dec.Hash(), dec.Type())
+	}
+	if from, err := Sender(signer, &dec); err != nil || from != addr {
+		t.Errorf("sender mismatch: have %x (%v), want %x", from, err, addr)
+	}
+	// The Berlin signer does not know about the dynamic fee transactions
+	if _, err := Sender(NewEIP2930Signer(big.NewInt(2047)), &dec); err != ErrTxTypeNotSupported {
+		t.Errorf("berlin signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	// The effective tip is capped by the fee cap minus the base fee
+	for _, test := range []struct {
+		baseFee *big.Int
+		tip     int64
+		err     error
+	}{
+		{nil, 2, nil},
+		{big.NewInt(5), 2, nil},
+		{big.NewInt(9), 1, nil},
+		{big.NewInt(11), -1, ErrGasFeeCapTooLow},
+	} {
+		tip, err := dec.EffectiveGasTip(test.baseFee)
+		if tip.Int64() != test.tip || err != test.err {
+			t.Errorf("base fee %v: have tip %v (%v), want %d (%v)", test.baseFee, tip, err, test.tip, test.err)
+		}
+	}
+	// The message pays the tip on top of the base fee, up to the fee cap
+	msg, err := dec.AsMessage(signer, big.NewInt(5))
+	if err != nil {
+		t.Fatalf("failed to convert to message: %v", err)
+	}
+	if msg.GasPrice().Int64() != 7 {
+		t.Errorf("message gas price mismatch: have %v, want 7", msg.GasPrice())
+	}
+	// JSON round trip
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("json encode error: %v", err)
+	}
+	var parsed Transaction
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("json decode error: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("json hash mismatch: have %x, want %x", parsed.Hash(), tx.Hash())
+	}
+}
+
+func TestTypedReceiptEncoding(t *testing.T) {
+	receipt := &Receipt{Type: AccessListTxType, Status: ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*Log{}}
+
+	enc, err := rlp.EncodeToBytes(receipt)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	var dec Receipt
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if dec.Type != AccessListTxType || dec.Status != ReceiptStatusSuccessful || dec.CumulativeGasUsed != 21000 {
+		t.Errorf("receipt mismatch: %+v", dec)
+	}
+	// The derived root hashes the type prefixed encoding
+	if blob := (Receipts{receipt}).GetRlp(0); blob[0] != AccessListTxType {
+		t.Errorf("receipt consensus encoding not typed: %x", blob)
+	}
+}
+
 func decodeTx(data []byte) (*Transaction, error) {
 	var tx Transaction
 	t, err := &tx, rlp.Decode(bytes.NewReader(data), &tx)
@@ -147,7 +340,7 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 		}
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(signer, groups)
+	txset := NewTransactionsByPriceAndNonce(signer, groups, nil)
 
 	txs := Transactions{}
 	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
@@ -288,19 +481,14 @@ func TestSignTx(t *testing.T) {
 	signer := NewEIP155Signer(big.NewInt(2047)) // mainnet 2047，testnet 2048
 	address := common.HexToAddress("0xafe79676ba4ce82e60bfe843bdcab00aa306ad70")
 
-	d := txdata{
-		AccountNonce: 29,
-		Recipient:    &address,
-		Payload:      []byte(""),
-		Amount:       big.NewInt(10),
-		GasLimit:     30000,
-		Price:        big.NewInt(5000000000000),
-		V:            new(big.Int),
-		R:            new(big.Int),
-		S:            new(big.Int),
-	}
-
-	tx := &Transaction{data: d}
+	tx := NewTx(&LegacyTx{
+		Nonce:    29,
+		To:       &address,
+		Data:     []byte(""),
+		Value:    big.NewInt(10),
+		Gas:      30000,
+		GasPrice: big.NewInt(5000000000000),
+	})
 	t.Logf("tx before sign  %v\n", tx)
 	signTx, err := SignTx(tx, signer, key)
 	if err != nil {
@@ -308,7 +496,7 @@ func TestSignTx(t *testing.T) {
 	}
 	t.Logf("tx after sign  %v\n", signTx)
 
-	enc, err := rlp.EncodeToBytes(&signTx.data)
+	enc, err := signTx.MarshalBinary()
 	if err != nil {
 		t.Logf("rlp encode to byte err %v\n", err)
 	}
@@ -331,20 +519,15 @@ func TestDelegateTx(t *testing.T) {
 		t.Errorf("could not pack data, err %v\n", err)
 	}
 
-	d := txdata{
-		AccountNonce: 1,
-		Recipient:    &address,
-		//Payload:      []byte(""),
-		Payload:  input,
-		Amount:   big.NewInt(1000000000000000000),
-		GasLimit: 50000,
-		Price:    big.NewInt(10000000000),
-		V:        new(big.Int),
-		R:        new(big.Int),
-		S:        new(big.Int),
-	}
-
-	tx := &Transaction{data: d}
+	tx := NewTx(&LegacyTx{
+		Nonce: 1,
+		To:    &address,
+		//Data:     []byte(""),
+		Data:     input,
+		Value:    big.NewInt(1000000000000000000),
+		Gas:      50000,
+		GasPrice: big.NewInt(10000000000),
+	})
 	t.Logf("tx before sign  %v\n", tx)
 	signTx, err := SignTx(tx, signer, key)
 	if err != nil {
@@ -352,7 +535,7 @@ func TestDelegateTx(t *testing.T) {
 	}
 	t.Logf("tx after sign  %v\n", signTx)
 
-	enc, err := rlp.EncodeToBytes(&signTx.data)
+	enc, err := signTx.MarshalBinary()
 	if err != nil {
 		t.Logf("rlp encode to byte err %v\n", err)
 	}
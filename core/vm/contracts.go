@@ -27,6 +27,7 @@ import (
 	"github.com/intfoundation/intchain/common/math"
 	"github.com/intfoundation/intchain/crypto"
 	"github.com/intfoundation/intchain/crypto/bn256"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
 	"github.com/intfoundation/intchain/params"
 	"golang.org/x/crypto/ripemd160"
 )
@@ -75,6 +76,60 @@ var PrecompiledContractsIstanbul = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{9}): &blake2F{},
 }
 
+// PrecompiledContractsIntChain contains the default set of pre-compiled Ethereum
+// contracts and the IntChain native contracts used after the IntChain precompile fork.
+var PrecompiledContractsIntChain = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{},
+	common.BytesToAddress([]byte{6}): &bn256AddIstanbul{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMulIstanbul{},
+	common.BytesToAddress([]byte{8}): &bn256PairingIstanbul{},
+	common.BytesToAddress([]byte{9}): &blake2F{},
+	intAbi.StakingContractAddr:       &intStaking{},
+	intAbi.EpochContractAddr:         &intEpoch{},
+	intAbi.BLSContractAddr:           &blsVerify{},
+}
+
+var (
+	PrecompiledAddressesIntChain  []common.Address
+	PrecompiledAddressesIstanbul  []common.Address
+	PrecompiledAddressesByzantium []common.Address
+	PrecompiledAddressesHomestead []common.Address
+)
+
+func init() {
+	for k := range PrecompiledContractsHomestead {
+		PrecompiledAddressesHomestead = append(PrecompiledAddressesHomestead, k)
+	}
+	for k := range PrecompiledContractsByzantium {
+		PrecompiledAddressesByzantium = append(PrecompiledAddressesByzantium, k)
+	}
+	for k := range PrecompiledContractsIstanbul {
+		PrecompiledAddressesIstanbul = append(PrecompiledAddressesIstanbul, k)
+	}
+	for k := range PrecompiledContractsIntChain {
+		PrecompiledAddressesIntChain = append(PrecompiledAddressesIntChain, k)
+	}
+}
+
+// ActivePrecompiles returns the precompiles enabled with the current configuration,
+// they are warm from the start of the transactions after the Berlin fork.
+func ActivePrecompiles(rules params.Rules) []common.Address {
+	switch {
+	case rules.IsIntPrecompile:
+		return PrecompiledAddressesIntChain
+	case rules.IsIstanbul:
+		return PrecompiledAddressesIstanbul
+	case rules.IsByzantium:
+		return PrecompiledAddressesByzantium
+	default:
+		return PrecompiledAddressesHomestead
+	}
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -0,0 +1,281 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/intfoundation/bls"
+	goCrypto "github.com/intfoundation/go-crypto"
+	"github.com/intfoundation/intchain/common"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"github.com/intfoundation/intchain/params"
+)
+
+var (
+	errStatefulPrecompile  = errors.New("precompiled contract requires the EVM state")
+	errStakingInput        = errors.New("invalid staking contract input")
+	errStakingUnavailable  = errors.New("staking functions are unavailable")
+	errStakingDelegateCall = errors.New("staking functions can not be called by delegate call")
+	errStakingValue        = errors.New("staking function does not accept value")
+	errEpochInput          = errors.New("invalid epoch contract input")
+	errEpochUnavailable    = errors.New("epoch is unavailable")
+	errBLSInput            = errors.New("invalid BLS contract input")
+	errBLSInvalidPubKey    = errors.New("invalid BLS public key")
+	errBLSInvalidSignature = errors.New("invalid BLS signature")
+)
+
+// statefulPrecompiledContract is a precompiled contract which reads or
+// modifies the state of the EVM, it is run with RunStateful instead of Run.
+type statefulPrecompiledContract interface {
+	PrecompiledContract
+	RunStateful(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error)
+}
+
+// runStatefulPrecompiledContract runs and evaluates the output of a stateful precompiled contract.
+func runStatefulPrecompiledContract(evm *EVM, p statefulPrecompiledContract, input []byte, contract *Contract, readOnly bool) (ret []byte, err error) {
+	gas := p.RequiredGas(input)
+	if contract.UseGas(gas) {
+		return p.RunStateful(evm, contract, input, readOnly)
+	}
+	return nil, ErrOutOfGas
+}
+
+// StakingBackend performs the IntChain staking functions on behalf of the
+// contracts calling the staking precompiled contract. The functions run the
+// same validations as the staking transactions sent to the IntChain contract.
+type StakingBackend interface {
+	Delegate(db StateDB, delegator, candidate common.Address, amount *big.Int) error
+	UnDelegate(db StateDB, delegator, candidate common.Address, amount *big.Int) error
+	WithdrawReward(db StateDB, delegator, candidate common.Address, amount *big.Int) error
+}
+
+// stakingStateDB is the part of the state database queried by the staking
+// precompiled contract.
+type stakingStateDB interface {
+	GetDepositProxiedBalanceByUser(addr, user common.Address) *big.Int
+	GetProxiedBalanceByUser(addr, user common.Address) *big.Int
+	GetPendingRefundBalanceByUser(addr, user common.Address) *big.Int
+	GetRewardBalanceByDelegateAddress(addr common.Address, deleAddress common.Address) *big.Int
+	GetCommission(addr common.Address) uint8
+	IsCandidate(addr common.Address) bool
+}
+
+// intStaking implemented as a native contract, it lets the contracts query the
+// staking state and delegate, undelegate or withdraw rewards as the delegator.
+type intStaking struct{}
+
+func (c *intStaking) RequiredGas(input []byte) uint64 {
+	if len(input) < 4 {
+		return params.IntStakingQueryGas
+	}
+	method, err := intAbi.StakingABI.MethodById(input[:4])
+	if err != nil {
+		return params.IntStakingQueryGas
+	}
+	switch method.Name {
+	case intAbi.StakingDelegate:
+		return intAbi.Delegate.RequiredGas()
+	case intAbi.StakingUnDelegate:
+		return intAbi.UnDelegate.RequiredGas()
+	case intAbi.StakingWithdrawReward:
+		return intAbi.WithdrawReward.RequiredGas()
+	default:
+		return params.IntStakingQueryGas
+	}
+}
+
+func (c *intStaking) Run(input []byte) ([]byte, error) {
+	return nil, errStatefulPrecompile
+}
+
+func (c *intStaking) RunStateful(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, errStakingInput
+	}
+	method, err := intAbi.StakingABI.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	db, ok := evm.StateDB.(stakingStateDB)
+	if !ok {
+		return nil, errStakingUnavailable
+	}
+	// Only the delegation moves the value sent along with the call
+	value := contract.Value()
+	if method.Name != intAbi.StakingDelegate && value != nil && value.Sign() != 0 {
+		return nil, errStakingValue
+	}
+
+	switch method.Name {
+	case intAbi.StakingGetDepositProxiedBalance:
+		return method.Outputs.Pack(db.GetDepositProxiedBalanceByUser(args[0].(common.Address), args[1].(common.Address)))
+	case intAbi.StakingGetProxiedBalance:
+		return method.Outputs.Pack(db.GetProxiedBalanceByUser(args[0].(common.Address), args[1].(common.Address)))
+	case intAbi.StakingGetPendingRefundBalance:
+		return method.Outputs.Pack(db.GetPendingRefundBalanceByUser(args[0].(common.Address), args[1].(common.Address)))
+	case intAbi.StakingGetCommission:
+		return method.Outputs.Pack(db.GetCommission(args[0].(common.Address)))
+	case intAbi.StakingGetRewardBalance:
+		return method.Outputs.Pack(db.GetRewardBalanceByDelegateAddress(args[0].(common.Address), args[1].(common.Address)))
+	case intAbi.StakingIsCandidate:
+		return method.Outputs.Pack(db.IsCandidate(args[0].(common.Address)))
+	}
+
+	// The remaining functions modify the staking state of the caller
+	if readOnly {
+		return nil, ErrWriteProtection
+	}
+	if contract.CodeAddr == nil || *contract.CodeAddr != contract.Address() {
+		return nil, errStakingDelegateCall
+	}
+	if evm.Staking == nil {
+		return nil, errStakingUnavailable
+	}
+	delegator := contract.Caller()
+
+	switch method.Name {
+	case intAbi.StakingDelegate:
+		// The value was transferred to the precompiled contract by the call,
+		// hand it back to the delegator before it is locked as delegation.
+		if value == nil {
+			value = new(big.Int)
+		}
+		evm.StateDB.SubBalance(contract.Address(), value)
+		evm.StateDB.AddBalance(delegator, value)
+		err = evm.Staking.Delegate(evm.StateDB, delegator, args[0].(common.Address), value)
+	case intAbi.StakingUnDelegate:
+		err = evm.Staking.UnDelegate(evm.StateDB, delegator, args[0].(common.Address), args[1].(*big.Int))
+	case intAbi.StakingWithdrawReward:
+		err = evm.Staking.WithdrawReward(evm.StateDB, delegator, args[0].(common.Address), args[1].(*big.Int))
+	default:
+		err = errStakingInput
+	}
+	return nil, err
+}
+
+// EpochBackend provides the IntChain epoch containing the executing block to
+// the epoch precompiled contract.
+type EpochBackend interface {
+	// GetEpoch returns the number, the first and last block and the reward per
+	// block of the epoch containing the given block.
+	GetEpoch(blockNumber uint64) (number, startBlock, endBlock uint64, rewardPerBlock *big.Int, err error)
+	// IsValidator reports whether the address is a validator of the epoch
+	// containing the given block.
+	IsValidator(blockNumber uint64, addr common.Address) (bool, error)
+}
+
+// intEpoch implemented as a native contract, it lets the contracts query the
+// epoch and the validators of the executing block.
+type intEpoch struct{}
+
+func (c *intEpoch) RequiredGas(input []byte) uint64 {
+	return params.IntEpochQueryGas
+}
+
+func (c *intEpoch) Run(input []byte) ([]byte, error) {
+	return nil, errStatefulPrecompile
+}
+
+func (c *intEpoch) RunStateful(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, errEpochInput
+	}
+	method, err := intAbi.EpochABI.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	if evm.Epoch == nil {
+		return nil, errEpochUnavailable
+	}
+	// The epoch is always the one of the executing block, the result does not
+	// depend on the progress of the local consensus engine.
+	blockNumber := evm.BlockNumber.Uint64()
+
+	switch method.Name {
+	case intAbi.EpochGetEpoch:
+		number, startBlock, endBlock, rewardPerBlock, err := evm.Epoch.GetEpoch(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(number, startBlock, endBlock, rewardPerBlock)
+	case intAbi.EpochIsValidator:
+		validator, err := evm.Epoch.IsValidator(blockNumber, args[0].(common.Address))
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(validator)
+	}
+	return nil, errEpochInput
+}
+
+// blsVerify implemented as a native contract, it verifies the single and the
+// aggregate BLS signatures of the IPBFT consensus, so the contracts are able to
+// check the commits of IntChain child chains and other IPBFT networks.
+type blsVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blsVerify) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := intAbi.BLSABI.MethodById(input[:4]); err == nil && method.Name == intAbi.BLSVerifyAggregate {
+			if args, err := method.Inputs.UnpackValues(input[4:]); err == nil {
+				return params.BLSVerifyBaseGas + uint64(len(args[0].([][]byte)))*params.BLSVerifyPerKeyGas
+			}
+		}
+	}
+	return params.BLSVerifyBaseGas + params.BLSVerifyPerKeyGas
+}
+
+func (c *blsVerify) Run(input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, errBLSInput
+	}
+	method, err := intAbi.BLSABI.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	var pubKeys [][]byte
+	switch method.Name {
+	case intAbi.BLSVerify:
+		pubKeys = [][]byte{args[0].([]byte)}
+	case intAbi.BLSVerifyAggregate:
+		pubKeys = args[0].([][]byte)
+	default:
+		return nil, errBLSInput
+	}
+	message, signature := args[1].([]byte), args[2].([]byte)
+	if len(pubKeys) == 0 {
+		return nil, errBLSInput
+	}
+	if len(signature) != 64 {
+		return nil, errBLSInvalidSignature
+	}
+	pks := make([]*goCrypto.PubKey, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		// go-crypto does not report the malformed keys, check them before the aggregation
+		if len(pubKey) != len(goCrypto.BLSPubKey{}) || new(bls.PublicKey).Unmarshal(pubKey) != nil {
+			return nil, errBLSInvalidPubKey
+		}
+		var blsPK goCrypto.BLSPubKey
+		copy(blsPK[:], pubKey)
+		pk := goCrypto.PubKey(blsPK)
+		pks[i] = &pk
+	}
+	pubKey := *pks[0]
+	if len(pks) > 1 {
+		pubKey = *goCrypto.BLSPubKeyAggregate(pks)
+	}
+	return method.Outputs.Pack(pubKey.VerifyBytes(message, goCrypto.BLSSignature(signature)))
+}
@@ -0,0 +1,239 @@
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/bls"
+	goCrypto "github.com/intfoundation/go-crypto"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/state"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"github.com/intfoundation/intchain/params"
+)
+
+type stakingTestStatedb struct {
+	state.StateDB
+}
+
+func (*stakingTestStatedb) IsCandidate(addr common.Address) bool { return addr == common.Address{1} }
+func (*stakingTestStatedb) GetCommission(addr common.Address) uint8 {
+	return 10
+}
+
+type stakingContractRef struct {
+	addr common.Address
+}
+
+func (r stakingContractRef) Address() common.Address { return r.addr }
+
+func newStakingContract(caller common.Address, value *big.Int) *Contract {
+	contract := NewContract(stakingContractRef{caller}, stakingContractRef{intAbi.StakingContractAddr}, value, 100000)
+	contract.CodeAddr = &intAbi.StakingContractAddr
+	return contract
+}
+
+func packStaking(t *testing.T, method string, args ...interface{}) []byte {
+	input, err := intAbi.StakingABI.Pack(method, args...)
+	if err != nil {
+		t.Fatalf("failed to pack %s: %v", method, err)
+	}
+	return input
+}
+
+func TestStakingRequiredGas(t *testing.T) {
+	p := &intStaking{}
+	tests := []struct {
+		input []byte
+		gas   uint64
+	}{
+		{nil, params.IntStakingQueryGas},
+		{[]byte{0x01, 0x02, 0x03, 0x04}, params.IntStakingQueryGas},
+		{packStaking(t, intAbi.StakingIsCandidate, common.Address{1}), params.IntStakingQueryGas},
+		{packStaking(t, intAbi.StakingDelegate, common.Address{1}), intAbi.Delegate.RequiredGas()},
+		{packStaking(t, intAbi.StakingUnDelegate, common.Address{1}, big.NewInt(1)), intAbi.UnDelegate.RequiredGas()},
+		{packStaking(t, intAbi.StakingWithdrawReward, common.Address{1}, big.NewInt(1)), intAbi.WithdrawReward.RequiredGas()},
+	}
+	for i, test := range tests {
+		if gas := p.RequiredGas(test.input); gas != test.gas {
+			t.Errorf("test %d: gas mismatch: have %d, want %d", i, gas, test.gas)
+		}
+	}
+}
+
+func TestStakingQuery(t *testing.T) {
+	var (
+		p   = &intStaking{}
+		env = NewEVM(Context{}, &stakingTestStatedb{}, params.TestChainConfig, Config{})
+	)
+	input := packStaking(t, intAbi.StakingIsCandidate, common.Address{1})
+	ret, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, true)
+	if err != nil {
+		t.Fatalf("isCandidate failed: %v", err)
+	}
+	if want := common.LeftPadBytes([]byte{1}, 32); common.Bytes2Hex(ret) != common.Bytes2Hex(want) {
+		t.Errorf("isCandidate output mismatch: have %x, want %x", ret, want)
+	}
+
+	input = packStaking(t, intAbi.StakingGetCommission, common.Address{1})
+	ret, err = p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, true)
+	if err != nil {
+		t.Fatalf("getCommission failed: %v", err)
+	}
+	if want := common.LeftPadBytes([]byte{10}, 32); common.Bytes2Hex(ret) != common.Bytes2Hex(want) {
+		t.Errorf("getCommission output mismatch: have %x, want %x", ret, want)
+	}
+
+	if _, err := p.RunStateful(env, newStakingContract(common.Address{2}, big.NewInt(1)), input, false); err != errStakingValue {
+		t.Errorf("query with value: have %v, want %v", err, errStakingValue)
+	}
+	if _, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input[:3], true); err != errStakingInput {
+		t.Errorf("short input: have %v, want %v", err, errStakingInput)
+	}
+}
+
+func TestStakingWriteRestrictions(t *testing.T) {
+	var (
+		p     = &intStaking{}
+		env   = NewEVM(Context{}, &stakingTestStatedb{}, params.TestChainConfig, Config{})
+		input = packStaking(t, intAbi.StakingUnDelegate, common.Address{1}, big.NewInt(1))
+	)
+	if _, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, true); err != ErrWriteProtection {
+		t.Errorf("static call: have %v, want %v", err, ErrWriteProtection)
+	}
+
+	contract := newStakingContract(common.Address{2}, new(big.Int))
+	contract.CodeAddr = &common.Address{3}
+	if _, err := p.RunStateful(env, contract, input, false); err != errStakingDelegateCall {
+		t.Errorf("delegate call: have %v, want %v", err, errStakingDelegateCall)
+	}
+
+	if _, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, false); err != errStakingUnavailable {
+		t.Errorf("missing staking backend: have %v, want %v", err, errStakingUnavailable)
+	}
+}
+
+type epochTestBackend struct{}
+
+func (epochTestBackend) GetEpoch(blockNumber uint64) (uint64, uint64, uint64, *big.Int, error) {
+	return blockNumber / 100, blockNumber / 100 * 100, blockNumber/100*100 + 99, big.NewInt(1e18), nil
+}
+
+func (epochTestBackend) IsValidator(blockNumber uint64, addr common.Address) (bool, error) {
+	return addr == common.Address{1}, nil
+}
+
+func TestEpochQuery(t *testing.T) {
+	var (
+		p   = &intEpoch{}
+		env = NewEVM(Context{BlockNumber: big.NewInt(250), Epoch: epochTestBackend{}}, nil, params.TestChainConfig, Config{})
+	)
+	input, _ := intAbi.EpochABI.Pack(intAbi.EpochGetEpoch)
+	ret, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, true)
+	if err != nil {
+		t.Fatalf("getEpoch failed: %v", err)
+	}
+	values, err := intAbi.EpochABI.Methods[intAbi.EpochGetEpoch].Outputs.UnpackValues(ret)
+	if err != nil {
+		t.Fatalf("failed to unpack getEpoch output: %v", err)
+	}
+	if values[0].(uint64) != 2 || values[1].(uint64) != 200 || values[2].(uint64) != 299 || values[3].(*big.Int).Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("getEpoch output mismatch: have %v", values)
+	}
+
+	for addr, want := range map[common.Address]bool{{1}: true, {2}: false} {
+		input, _ := intAbi.EpochABI.Pack(intAbi.EpochIsValidator, addr)
+		ret, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, true)
+		if err != nil {
+			t.Fatalf("isValidator failed: %v", err)
+		}
+		if have := ret[31] == 1; have != want {
+			t.Errorf("isValidator(%x) mismatch: have %v, want %v", addr, have, want)
+		}
+	}
+
+	env.Epoch = nil
+	if _, err := p.RunStateful(env, newStakingContract(common.Address{2}, new(big.Int)), input, true); err != errEpochUnavailable {
+		t.Errorf("missing epoch backend: have %v, want %v", err, errEpochUnavailable)
+	}
+}
+
+// blsTestInput signs the message with n keys derived from fixed seeds and packs
+// the verification of the aggregate signature against the checked message.
+func blsTestInput(t testing.TB, n int, message, checked []byte) []byte {
+	var (
+		pubKeys = make([][]byte, n)
+		sigs    = make([]*bls.Signature, n)
+	)
+	for i := 0; i < n; i++ {
+		var privKey goCrypto.BLSPrivKey
+		copy(privKey[:], bls.KeyFromSeed([]byte(fmt.Sprintf("bls-precompile-%d", i))).MarshalPrivate())
+		pubKeys[i] = privKey.PubKey().Bytes()
+
+		sigs[i] = new(bls.Signature)
+		if err := sigs[i].Unmarshal(privKey.Sign(message).Bytes()); err != nil {
+			t.Fatalf("failed to decode signature: %v", err)
+		}
+	}
+	signature := new(bls.Signature).Aggregate(sigs...).Marshal()
+
+	var (
+		input []byte
+		err   error
+	)
+	if n == 1 {
+		input, err = intAbi.BLSABI.Pack(intAbi.BLSVerify, pubKeys[0], checked, signature)
+	} else {
+		input, err = intAbi.BLSABI.Pack(intAbi.BLSVerifyAggregate, pubKeys, checked, signature)
+	}
+	if err != nil {
+		t.Fatalf("failed to pack input: %v", err)
+	}
+	return input
+}
+
+func TestPrecompiledBLSVerify(t *testing.T) {
+	p := &blsVerify{}
+	for _, n := range []int{1, 4, 16} {
+		input := blsTestInput(t, n, []byte("commit"), []byte("commit"))
+		if gas := p.RequiredGas(input); gas != params.BLSVerifyBaseGas+uint64(n)*params.BLSVerifyPerKeyGas {
+			t.Errorf("%d keys: gas mismatch: have %d", n, gas)
+		}
+		ret, err := p.Run(input)
+		if err != nil {
+			t.Fatalf("%d keys: verification failed: %v", n, err)
+		}
+		if ret[31] != 1 {
+			t.Errorf("%d keys: valid signature rejected", n)
+		}
+		// The signature must not verify another message
+		input = blsTestInput(t, n, []byte("commit"), []byte("prevote"))
+		if ret, err = p.Run(input); err != nil || ret[31] != 0 {
+			t.Errorf("%d keys: invalid signature accepted: %x %v", n, ret, err)
+		}
+	}
+
+	input, _ := intAbi.BLSABI.Pack(intAbi.BLSVerify, make([]byte, 127), []byte("commit"), make([]byte, 64))
+	if _, err := p.Run(input); err != errBLSInvalidPubKey {
+		t.Errorf("short public key: have %v, want %v", err, errBLSInvalidPubKey)
+	}
+	input, _ = intAbi.BLSABI.Pack(intAbi.BLSVerifyAggregate, [][]byte{}, []byte("commit"), make([]byte, 64))
+	if _, err := p.Run(input); err != errBLSInput {
+		t.Errorf("no public keys: have %v, want %v", err, errBLSInput)
+	}
+}
+
+func BenchmarkPrecompiledBLSVerify(bench *testing.B) {
+	p := &blsVerify{}
+	for _, n := range []int{1, 4, 16, 64} {
+		input := blsTestInput(bench, n, []byte("commit"), []byte("commit"))
+		bench.Run(fmt.Sprintf("%d-Gas=%d", n, p.RequiredGas(input)), func(bench *testing.B) {
+			for i := 0; i < bench.N; i++ {
+				if _, err := p.Run(input); err != nil {
+					bench.Fatal(err)
+				}
+			}
+		})
+	}
+}
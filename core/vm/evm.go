@@ -43,14 +43,10 @@ type (
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContractsHomestead
-		if evm.chainRules.IsByzantium {
-			precompiles = PrecompiledContractsByzantium
-		}
-		if evm.chainRules.IsIstanbul {
-			precompiles = PrecompiledContractsIstanbul
-		}
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p := evm.precompile(*contract.CodeAddr); p != nil {
+			if sp, ok := p.(statefulPrecompiledContract); ok {
+				return runStatefulPrecompiledContract(evm, sp, input, contract, readOnly)
+			}
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -91,6 +87,11 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+	BaseFee     *big.Int       // Provides information for BASEFEE
+
+	// IntChain information
+	Staking StakingBackend // Performs the staking functions of the staking precompiled contract
+	Epoch   EpochBackend   // Provides the epoch of the executing block to the epoch precompiled contract
 }
 
 // EVM is the Ethereum Virtual Machine base object and provides
@@ -133,6 +134,10 @@ type EVM struct {
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
 // only ever be used *once*.
 func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmConfig Config) *EVM {
+	// The zero price calls run without the base fee if it is disabled
+	if vmConfig.NoBaseFee && (ctx.GasPrice == nil || ctx.GasPrice.Sign() == 0) {
+		ctx.BaseFee = new(big.Int)
+	}
 	evm := &EVM{
 		Context:      ctx,
 		StateDB:      statedb,
@@ -166,6 +171,28 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 	return evm
 }
 
+// precompile returns the precompiled contract at the given address which is
+// active under the current chain rules, or nil if there is none.
+func (evm *EVM) precompile(addr common.Address) PrecompiledContract {
+	var precompiles map[common.Address]PrecompiledContract
+	switch {
+	case evm.chainRules.IsIntPrecompile:
+		precompiles = PrecompiledContractsIntChain
+	case evm.chainRules.IsIstanbul:
+		precompiles = PrecompiledContractsIstanbul
+	case evm.chainRules.IsByzantium:
+		precompiles = PrecompiledContractsByzantium
+	default:
+		precompiles = PrecompiledContractsHomestead
+	}
+	return precompiles[addr]
+}
+
+// Config returns the configuration of the EVM.
+func (evm *EVM) Config() Config {
+	return evm.vmConfig
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -205,14 +232,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContractsHomestead
-		if evm.chainRules.IsByzantium {
-			precompiles = PrecompiledContractsByzantium
-		}
-		if evm.chainRules.IsIstanbul {
-			precompiles = PrecompiledContractsIstanbul
-		}
-		if precompiles[addr] == nil && evm.chainRules.IsEIP158 && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && evm.chainRules.IsEIP158 && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -392,6 +412,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	}
 	nonce := evm.StateDB.GetNonce(caller.Address())
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
+	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
+	// the access-list change should not be rolled back
+	if evm.chainRules.IsBerlin {
+		evm.StateDB.AddAddressToAccessList(address)
+	}
 
 	// Ensure there's no existing contract already at the designated address
 	contractHash := evm.StateDB.GetCodeHash(address)
@@ -24,6 +24,7 @@ var (
 	WithdrawFromMainChain  = FunctionType{5, true, true, false}
 	SaveDataToMainChain    = FunctionType{6, true, true, false}
 	SetBlockReward         = FunctionType{7, true, false, true}
+	SetChildChainGenesis   = FunctionType{8, true, true, false}
 	// Non-Cross Chain Function
 	VoteNextEpoch  = FunctionType{10, false, true, true}
 	RevealVote     = FunctionType{11, false, true, true}
@@ -36,6 +37,10 @@ var (
 	UnForbidden    = FunctionType{18, false, true, true}
 	SetCommission  = FunctionType{19, false, true, true}
 	SetAddress     = FunctionType{20, false, true, true}
+	// Token Bridge Function
+	MintTokenInChildChain  = FunctionType{30, true, false, true}
+	BurnTokenInChildChain  = FunctionType{31, true, false, true}
+	UnlockTokenInMainChain = FunctionType{32, true, true, false}
 	// Unknown
 	Unknown = FunctionType{-1, false, false, false}
 )
@@ -76,6 +81,8 @@ func (t FunctionType) RequiredGas() uint64 {
 		return 21000
 	case SetBlockReward:
 		return 21000
+	case SetChildChainGenesis:
+		return 21000
 	case EditValidator:
 		return 21000
 	case WithdrawReward:
@@ -86,6 +93,12 @@ func (t FunctionType) RequiredGas() uint64 {
 		return 21000
 	case SetAddress:
 		return 21000
+	case MintTokenInChildChain:
+		return 0
+	case BurnTokenInChildChain:
+		return 42000
+	case UnlockTokenInMainChain:
+		return 0
 	default:
 		return 0
 	}
@@ -121,6 +134,8 @@ func (t FunctionType) String() string {
 		return "UnRegister"
 	case SetBlockReward:
 		return "SetBlockReward"
+	case SetChildChainGenesis:
+		return "SetChildChainGenesis"
 	case EditValidator:
 		return "EditValidator"
 	case WithdrawReward:
@@ -131,6 +146,12 @@ func (t FunctionType) String() string {
 		return "SetCommission"
 	case SetAddress:
 		return "SetAddress"
+	case MintTokenInChildChain:
+		return "MintTokenInChildChain"
+	case BurnTokenInChildChain:
+		return "BurnTokenInChildChain"
+	case UnlockTokenInMainChain:
+		return "UnlockTokenInMainChain"
 	default:
 		return "UnKnown"
 	}
@@ -166,6 +187,8 @@ func StringToFunctionType(s string) FunctionType {
 		return UnRegister
 	case "SetBlockReward":
 		return SetBlockReward
+	case "SetChildChainGenesis":
+		return SetChildChainGenesis
 	case "EditValidator":
 		return EditValidator
 	case "WithdrawReward":
@@ -176,6 +199,12 @@ func StringToFunctionType(s string) FunctionType {
 		return SetCommission
 	case "SetAddress":
 		return SetAddress
+	case "MintTokenInChildChain":
+		return MintTokenInChildChain
+	case "BurnTokenInChildChain":
+		return BurnTokenInChildChain
+	case "UnlockTokenInMainChain":
+		return UnlockTokenInMainChain
 	default:
 		return Unknown
 	}
@@ -189,6 +218,11 @@ type CreateChildChainArgs struct {
 	EndBlock         *big.Int
 }
 
+type SetChildChainGenesisArgs struct {
+	ChainId         string
+	GenesisTemplate []byte
+}
+
 type JoinChildChainArgs struct {
 	PubKey    []byte
 	ChainId   string
@@ -268,6 +302,22 @@ type SetAddressArgs struct {
 	FAddress common.Address
 }
 
+type MintTokenInChildChainArgs struct {
+	ChainId string
+	TxHash  common.Hash
+}
+
+type BurnTokenInChildChainArgs struct {
+	ChainId string
+	Token   common.Address
+	Amount  *big.Int
+}
+
+type UnlockTokenInMainChainArgs struct {
+	ChainId string
+	TxHash  common.Hash
+}
+
 const jsonChainABI = `
 [
 	{
@@ -297,6 +347,21 @@ const jsonChainABI = `
 			}
 		]
 	},
+	{
+		"type": "function",
+		"name": "SetChildChainGenesis",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "chainId",
+				"type": "string"
+			},
+			{
+				"name": "genesisTemplate",
+				"type": "bytes"
+			}
+		]
+	},
 	{
 		"type": "function",
 		"name": "JoinChildChain",
@@ -548,6 +613,55 @@ const jsonChainABI = `
 				"type": "address"
 			}
 		]
+	},
+	{
+		"type": "function",
+		"name": "MintTokenInChildChain",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "chainId",
+				"type": "string"
+			},
+			{
+				"name": "txHash",
+				"type": "bytes32"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "BurnTokenInChildChain",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "chainId",
+				"type": "string"
+			},
+			{
+				"name": "token",
+				"type": "address"
+			},
+			{
+				"name": "amount",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "UnlockTokenInMainChain",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "chainId",
+				"type": "string"
+			},
+			{
+				"name": "txHash",
+				"type": "bytes32"
+			}
+		]
 	}
 ]`
 
@@ -567,6 +681,12 @@ func init() {
 	}
 }
 
+// IsTX3Function reports whether the function is a child chain tx that has to be
+// proven to the main chain (the so-called TX3).
+func IsTX3Function(function FunctionType) bool {
+	return function == WithdrawFromChildChain || function == BurnTokenInChildChain
+}
+
 func IsIntChainContractAddr(addr *common.Address) bool {
 	return addr != nil && *addr == ChainContractMagicAddr
 }
@@ -0,0 +1,86 @@
+package abi
+
+import (
+	"strings"
+
+	"github.com/intfoundation/intchain/accounts/abi"
+	"github.com/intfoundation/intchain/common"
+)
+
+// INT Chain BLS Precompiled Contract Address, callable by the contracts after the IntChain precompile fork
+var BLSContractAddr = common.HexToAddress("0x0000000000000000000000000000000000001012")
+
+// Methods of the BLS precompiled contract
+const (
+	BLSVerify          = "verify"
+	BLSVerifyAggregate = "verifyAggregate"
+)
+
+const jsonBLSABI = `
+[
+	{
+		"type": "function",
+		"name": "verify",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "pubKey",
+				"type": "bytes"
+			},
+			{
+				"name": "message",
+				"type": "bytes"
+			},
+			{
+				"name": "signature",
+				"type": "bytes"
+			}
+		],
+		"outputs": [
+			{
+				"name": "valid",
+				"type": "bool"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "verifyAggregate",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "pubKeys",
+				"type": "bytes[]"
+			},
+			{
+				"name": "message",
+				"type": "bytes"
+			},
+			{
+				"name": "signature",
+				"type": "bytes"
+			}
+		],
+		"outputs": [
+			{
+				"name": "valid",
+				"type": "bool"
+			}
+		]
+	}
+]`
+
+var BLSABI abi.ABI
+
+func init() {
+	var err error
+	BLSABI, err = abi.JSON(strings.NewReader(jsonBLSABI))
+	if err != nil {
+		panic("fail to create the BLS ABI: " + err.Error())
+	}
+}
+
+// IsBLSContractAddr reports whether the address is the BLS precompiled contract.
+func IsBLSContractAddr(addr *common.Address) bool {
+	return addr != nil && *addr == BLSContractAddr
+}
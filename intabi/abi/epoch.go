@@ -0,0 +1,77 @@
+package abi
+
+import (
+	"strings"
+
+	"github.com/intfoundation/intchain/accounts/abi"
+	"github.com/intfoundation/intchain/common"
+)
+
+// INT Chain Epoch Precompiled Contract Address, callable by the contracts after the IntChain precompile fork
+var EpochContractAddr = common.HexToAddress("0x0000000000000000000000000000000000001011")
+
+// Methods of the epoch precompiled contract
+const (
+	EpochGetEpoch    = "getEpoch"
+	EpochIsValidator = "isValidator"
+)
+
+const jsonEpochABI = `
+[
+	{
+		"type": "function",
+		"name": "getEpoch",
+		"constant": true,
+		"inputs": [],
+		"outputs": [
+			{
+				"name": "number",
+				"type": "uint64"
+			},
+			{
+				"name": "startBlock",
+				"type": "uint64"
+			},
+			{
+				"name": "endBlock",
+				"type": "uint64"
+			},
+			{
+				"name": "rewardPerBlock",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "isValidator",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "addr",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "validator",
+				"type": "bool"
+			}
+		]
+	}
+]`
+
+var EpochABI abi.ABI
+
+func init() {
+	var err error
+	EpochABI, err = abi.JSON(strings.NewReader(jsonEpochABI))
+	if err != nil {
+		panic("fail to create the epoch ABI: " + err.Error())
+	}
+}
+
+// IsEpochContractAddr reports whether the address is the epoch precompiled contract.
+func IsEpochContractAddr(addr *common.Address) bool {
+	return addr != nil && *addr == EpochContractAddr
+}
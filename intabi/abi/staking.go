@@ -0,0 +1,206 @@
+package abi
+
+import (
+	"strings"
+
+	"github.com/intfoundation/intchain/accounts/abi"
+	"github.com/intfoundation/intchain/common"
+)
+
+// INT Chain Staking Precompiled Contract Address, callable by the contracts after the IntChain precompile fork
+var StakingContractAddr = common.HexToAddress("0x0000000000000000000000000000000000001010")
+
+// Methods of the staking precompiled contract
+const (
+	StakingGetDepositProxiedBalance = "getDepositProxiedBalance"
+	StakingGetProxiedBalance        = "getProxiedBalance"
+	StakingGetPendingRefundBalance  = "getPendingRefundBalance"
+	StakingGetCommission            = "getCommission"
+	StakingGetRewardBalance         = "getRewardBalance"
+	StakingIsCandidate              = "isCandidate"
+	StakingDelegate                 = "delegate"
+	StakingUnDelegate               = "unDelegate"
+	StakingWithdrawReward           = "withdrawReward"
+)
+
+const jsonStakingABI = `
+[
+	{
+		"type": "function",
+		"name": "getDepositProxiedBalance",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "delegator",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "balance",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "getProxiedBalance",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "delegator",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "balance",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "getPendingRefundBalance",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "delegator",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "balance",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "getCommission",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "commission",
+				"type": "uint8"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "getRewardBalance",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "delegator",
+				"type": "address"
+			},
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "balance",
+				"type": "uint256"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "isCandidate",
+		"constant": true,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": [
+			{
+				"name": "candidate",
+				"type": "bool"
+			}
+		]
+	},
+	{
+		"type": "function",
+		"name": "delegate",
+		"constant": false,
+		"payable": true,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "unDelegate",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "amount",
+				"type": "uint256"
+			}
+		],
+		"outputs": []
+	},
+	{
+		"type": "function",
+		"name": "withdrawReward",
+		"constant": false,
+		"inputs": [
+			{
+				"name": "candidate",
+				"type": "address"
+			},
+			{
+				"name": "amount",
+				"type": "uint256"
+			}
+		],
+		"outputs": []
+	}
+]`
+
+var StakingABI abi.ABI
+
+func init() {
+	var err error
+	StakingABI, err = abi.JSON(strings.NewReader(jsonStakingABI))
+	if err != nil {
+		panic("fail to create the staking ABI: " + err.Error())
+	}
+}
+
+// IsStakingContractAddr reports whether the address is the staking precompiled contract.
+func IsStakingContractAddr(addr *common.Address) bool {
+	return addr != nil && *addr == StakingContractAddr
+}
@@ -0,0 +1,106 @@
+package intclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/rpc"
+)
+
+// ChainStatus is the status of a chain running in the node
+type ChainStatus struct {
+	ChainId     string
+	MainChain   bool
+	RPCPath     string // path of the chain rpc on the http/ws endpoint
+	GenesisHash common.Hash
+	Height      uint64
+	Epoch       uint64
+}
+
+type rpcChainStatus struct {
+	ChainId     string         `json:"chainId"`
+	MainChain   bool           `json:"mainChain"`
+	RPCPath     string         `json:"rpcPath"`
+	GenesisHash common.Hash    `json:"genesisHash"`
+	Height      hexutil.Uint64 `json:"height"`
+	Epoch       hexutil.Uint64 `json:"epoch"`
+}
+
+// Chains returns the main chain and the child chains running in the node.
+func (ec *Client) Chains(ctx context.Context) ([]*ChainStatus, error) {
+	var raw []*rpcChainStatus
+	if err := ec.c.CallContext(ctx, &raw, "int_chains"); err != nil {
+		return nil, err
+	}
+
+	chains := make([]*ChainStatus, len(raw))
+	for i, c := range raw {
+		chains[i] = &ChainStatus{
+			ChainId:     c.ChainId,
+			MainChain:   c.MainChain,
+			RPCPath:     c.RPCPath,
+			GenesisHash: c.GenesisHash,
+			Height:      uint64(c.Height),
+			Epoch:       uint64(c.Epoch),
+		}
+	}
+	return chains, nil
+}
+
+// DialChain connects a client to the chain with the given id, which is served by
+// the same http/ws endpoint of this client. The client must be created by Dial.
+func (ec *Client) DialChain(ctx context.Context, chainId string) (*Client, error) {
+	if ec.url == "" {
+		return nil, fmt.Errorf("endpoint unknown, the client is not created by Dial")
+	}
+
+	chains, err := ec.Chains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, chain := range chains {
+		if chain.ChainId != chainId {
+			continue
+		}
+
+		rawurl, err := chainURL(ec.url, chain.RPCPath)
+		if err != nil {
+			return nil, err
+		}
+		c, err := rpc.DialContext(ctx, rawurl)
+		if err != nil {
+			return nil, err
+		}
+		return &Client{c: c, url: rawurl}, nil
+	}
+	return nil, fmt.Errorf("chain %s not running on %s", chainId, ec.url)
+}
+
+// DialChain connects to the endpoint of the node and then to the chain with the given id.
+func DialChain(rawurl string, chainId string) (*Client, error) {
+	main, err := Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	defer main.Close()
+
+	return main.DialChain(context.Background(), chainId)
+}
+
+// chainURL replaces the path of the endpoint with the chain rpc path
+func chainURL(rawurl string, path string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return "", fmt.Errorf("no chain rpc path on endpoint %s, http or websocket endpoint required", rawurl)
+	}
+	u.Path = path
+	return u.String(), nil
+}
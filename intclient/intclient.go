@@ -28,13 +28,13 @@ import (
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/common/hexutil"
 	"github.com/intfoundation/intchain/core/types"
-	"github.com/intfoundation/intchain/rlp"
 	"github.com/intfoundation/intchain/rpc"
 )
 
 // Client defines typed wrappers for the Ethereum RPC API.
 type Client struct {
-	c *rpc.Client
+	c   *rpc.Client
+	url string // endpoint dialed by Dial, used to dial the other chains on the same endpoint
 }
 
 // Dial connects a client to the given URL.
@@ -43,12 +43,17 @@ func Dial(rawurl string) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewClient(c), nil
+	return &Client{c: c, url: rawurl}, nil
 }
 
 // NewClient creates a client that uses the given RPC client.
 func NewClient(c *rpc.Client) *Client {
-	return &Client{c}
+	return &Client{c: c}
+}
+
+// Close closes the underlying RPC connection.
+func (ec *Client) Close() {
+	ec.c.Close()
 }
 
 // Blockchain Access
@@ -470,7 +475,7 @@ func (ec *Client) EstimateGas(ctx context.Context, msg intchain.CallMsg) (uint64
 // contract address after the transaction has been mined.
 func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
 	fmt.Printf("transaction args Client tx %v\n", tx)
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
@@ -494,5 +499,8 @@ func toCallArg(msg intchain.CallMsg) interface{} {
 	if msg.GasPrice != nil {
 		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
 	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
 	return arg
 }
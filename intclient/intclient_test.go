@@ -16,7 +16,11 @@
 
 package intclient
 
-import "github.com/intfoundation/intchain"
+import (
+	"testing"
+
+	"github.com/intfoundation/intchain"
+)
 
 // Verify that Client implements the ethereum interfaces.
 var (
@@ -32,3 +36,25 @@ var (
 	// _ = ethereum.PendingStateEventer(&Client{})
 	_ = ethereum.PendingContractCaller(&Client{})
 )
+
+func TestChainURL(t *testing.T) {
+	tests := []struct {
+		rawurl, path, want string
+		ok                 bool
+	}{
+		{"http://localhost:8555", "/child_0", "http://localhost:8555/child_0", true},
+		{"http://localhost:8555/intchain", "/child_0", "http://localhost:8555/child_0", true},
+		{"ws://127.0.0.1:8556/", "/intchain", "ws://127.0.0.1:8556/intchain", true},
+		{"/tmp/intchain.ipc", "/child_0", "", false},
+	}
+	for i, test := range tests {
+		have, err := chainURL(test.rawurl, test.path)
+		if test.ok != (err == nil) {
+			t.Errorf("test %d: error mismatch: %v", i, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("test %d: url mismatch: have %s, want %s", i, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+// +build arm64 amd64
+
+// Package pebble implements the key-value database layer based on Pebble.
+package pebble
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/log"
+	"github.com/intfoundation/intchain/metrics"
+)
+
+const (
+	// minCache is the minimum amount of memory in megabytes to allocate to pebble
+	// read and write caching, split half and half.
+	minCache = 16
+
+	// minHandles is the minimum number of files handles to allocate to the open
+	// database files.
+	minHandles = 16
+
+	// metricsGatheringInterval specifies the interval to retrieve pebble database
+	// compaction, io and pause stats to report to the user.
+	metricsGatheringInterval = 3 * time.Second
+)
+
+// Database is a persistent key-value store based on the pebble storage engine.
+// Apart from basic data storage functionality it also supports batch writes and
+// iterating over the keyspace in binary-alphabetical order.
+type Database struct {
+	fn string     // filename for reporting
+	db *pebble.DB // Underlying pebble storage engine
+
+	compTimeMeter    metrics.Meter // Meter for measuring the total time spent in database compaction
+	compReadMeter    metrics.Meter // Meter for measuring the data read during compaction
+	compWriteMeter   metrics.Meter // Meter for measuring the data written during compaction
+	writeDelayNMeter metrics.Meter // Meter for measuring the write delay number due to database compaction
+	writeDelayMeter  metrics.Meter // Meter for measuring the write delay duration due to database compaction
+	diskWriteMeter   metrics.Meter // Meter for measuring the effective amount of data written
+
+	compTime       int64 // Total time spent in compaction in ns, updated atomically
+	writeDelayN    int64 // Total number of write stalls, updated atomically
+	writeDelayTime int64 // Total time spent in write stalls in ns, updated atomically
+	writeDelayBeg  int64 // Start time of the ongoing write stall in ns, updated atomically
+
+	quitLock sync.Mutex      // Mutex protecting the quit channel access
+	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
+
+	log log.Logger // Contextual logger tracking the database path
+}
+
+// New returns a wrapped pebble DB object. The namespace is the prefix that the
+// metrics reporting should use for surfacing internal stats.
+func New(file string, cache int, handles int, namespace string) (*Database, error) {
+	// Ensure we have some minimal caching and file guarantees
+	if cache < minCache {
+		cache = minCache
+	}
+	if handles < minHandles {
+		handles = minHandles
+	}
+	logger := log.New("database", file)
+	logger.Info("Allocated cache and file handles", "cache", common.StorageSize(cache*1024*1024), "handles", handles)
+
+	pdb := &Database{
+		fn:       file,
+		log:      logger,
+		quitChan: make(chan chan error),
+	}
+	// The memory table is a quarter of the cache like the leveldb write buffer,
+	// the rest is used for the block cache
+	memTableSize := cache * 1024 * 1024 / 4
+
+	opt := &pebble.Options{
+		Cache:                       pebble.NewCache(int64(cache * 1024 * 1024 / 2)),
+		MaxOpenFiles:                handles,
+		MemTableSize:                memTableSize,
+		MemTableStopWritesThreshold: 2,
+		MaxConcurrentCompactions:    2,
+		Levels: []pebble.LevelOptions{
+			{TargetFileSize: 2 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+			{TargetFileSize: 4 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+			{TargetFileSize: 8 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+			{TargetFileSize: 16 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+			{TargetFileSize: 32 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+			{TargetFileSize: 64 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+			{TargetFileSize: 128 * 1024 * 1024, FilterPolicy: bloom.FilterPolicy(10)},
+		},
+		Logger: &pebbleLogger{logger},
+		EventListener: pebble.EventListener{
+			CompactionEnd:   pdb.onCompactionEnd,
+			WriteStallBegin: pdb.onWriteStallBegin,
+			WriteStallEnd:   pdb.onWriteStallEnd,
+		},
+	}
+	// The cache is referenced by the database once opened
+	defer opt.Cache.Unref()
+
+	db, err := pebble.Open(file, opt)
+	if err != nil {
+		return nil, err
+	}
+	pdb.db = db
+
+	pdb.compTimeMeter = metrics.NewRegisteredMeter(namespace+"compact/time", nil)
+	pdb.compReadMeter = metrics.NewRegisteredMeter(namespace+"compact/input", nil)
+	pdb.compWriteMeter = metrics.NewRegisteredMeter(namespace+"compact/output", nil)
+	pdb.diskWriteMeter = metrics.NewRegisteredMeter(namespace+"disk/write", nil)
+	pdb.writeDelayMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/duration", nil)
+	pdb.writeDelayNMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/counter", nil)
+
+	// Start up the metrics gathering and return
+	go pdb.meter(metricsGatheringInterval)
+	return pdb, nil
+}
+
+// onCompactionEnd accumulates the time spent in the compactions.
+func (d *Database) onCompactionEnd(info pebble.CompactionInfo) {
+	atomic.AddInt64(&d.compTime, int64(info.Duration))
+}
+
+// onWriteStallBegin records the start of a write stall.
+func (d *Database) onWriteStallBegin(pebble.WriteStallBeginInfo) {
+	atomic.StoreInt64(&d.writeDelayBeg, time.Now().UnixNano())
+}
+
+// onWriteStallEnd accumulates the time spent in the write stall just ended.
+func (d *Database) onWriteStallEnd() {
+	atomic.AddInt64(&d.writeDelayN, 1)
+	atomic.AddInt64(&d.writeDelayTime, time.Now().UnixNano()-atomic.LoadInt64(&d.writeDelayBeg))
+}
+
+// Close stops the metrics collection, flushes any pending data to disk and closes
+// all io accesses to the underlying key-value store.
+func (d *Database) Close() error {
+	d.quitLock.Lock()
+	defer d.quitLock.Unlock()
+
+	if d.quitChan != nil {
+		errc := make(chan error)
+		d.quitChan <- errc
+		if err := <-errc; err != nil {
+			d.log.Error("Metrics collection failed", "err", err)
+		}
+		d.quitChan = nil
+	}
+	err := d.db.Close()
+	if err == nil {
+		d.log.Info("Database closed")
+	} else {
+		d.log.Error("Failed to close database", "err", err)
+	}
+	return err
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (d *Database) Has(key []byte) (bool, error) {
+	_, closer, err := d.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (d *Database) Get(key []byte) ([]byte, error) {
+	dat, closer, err := d.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	ret := common.CopyBytes(dat)
+	closer.Close()
+	return ret, nil
+}
+
+// Put inserts the given value into the key-value store.
+func (d *Database) Put(key []byte, value []byte) error {
+	return d.db.Set(key, value, pebble.NoSync)
+}
+
+// Delete removes the key from the key-value store.
+func (d *Database) Delete(key []byte) error {
+	return d.db.Delete(key, pebble.NoSync)
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (d *Database) NewBatch() intdb.Batch {
+	return &batch{
+		b: d.db.NewBatch(),
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the pebble database.
+func (d *Database) NewIterator() intdb.Iterator {
+	return d.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (d *Database) NewIteratorWithPrefix(prefix []byte) intdb.Iterator {
+	return &pebbleIterator{
+		iter: d.db.NewIter(&pebble.IterOptions{
+			LowerBound: prefix,
+			UpperBound: upperBound(prefix),
+		}),
+	}
+}
+
+// upperBound returns the upper bound for the given prefix, nil if the prefix
+// covers the end of the keyspace.
+func upperBound(prefix []byte) []byte {
+	limit := common.CopyBytes(prefix)
+	for i := len(limit) - 1; i >= 0; i-- {
+		if limit[i] == 0xff {
+			continue
+		}
+		limit[i]++
+		return limit[:i+1]
+	}
+	return nil
+}
+
+// Stat returns the internal metrics of pebble in a text format, the property is
+// ignored since pebble exposes a single metrics report.
+func (d *Database) Stat(property string) (string, error) {
+	return d.db.Metrics().String(), nil
+}
+
+// Compact flattens the underlying data store for the given key range. In essence,
+// deleted and overwritten versions are discarded, and the data is rearranged to
+// reduce the cost of operations needed to access them.
+//
+// A nil start is treated as a key before all keys in the data store; a nil limit
+// is treated as a key after all keys in the data store. If both is nil then it
+// will compact entire data store.
+func (d *Database) Compact(start []byte, limit []byte) error {
+	// Pebble has no key to represent the end of the keyspace, use a key larger
+	// than any entry, even the trie nodes starting with a run of 0xff bytes
+	if limit == nil {
+		limit = bytes.Repeat([]byte{0xff}, 2*common.HashLength)
+	}
+	return d.db.Compact(start, limit)
+}
+
+// Path returns the path to the database directory.
+func (d *Database) Path() string {
+	return d.fn
+}
+
+// meter periodically retrieves internal pebble counters and reports them to
+// the metrics subsystem.
+func (d *Database) meter(refresh time.Duration) {
+	var (
+		errc chan error
+
+		compTime, compRead, compWrite [2]int64
+		delayN, delayTime             [2]int64
+		diskWrite                     [2]int64
+	)
+	for i := 1; errc == nil; i++ {
+		var (
+			m  = d.db.Metrics()
+			cr int64
+			cw int64
+		)
+		for _, level := range m.Levels {
+			cr += int64(level.BytesRead)
+			cw += int64(level.BytesCompacted)
+		}
+		compTime[i%2] = atomic.LoadInt64(&d.compTime)
+		compRead[i%2], compWrite[i%2] = cr, cw
+		delayN[i%2] = atomic.LoadInt64(&d.writeDelayN)
+		delayTime[i%2] = atomic.LoadInt64(&d.writeDelayTime)
+		diskWrite[i%2] = int64(m.WAL.BytesWritten) + cw
+
+		d.compTimeMeter.Mark(compTime[i%2] - compTime[(i-1)%2])
+		d.compReadMeter.Mark(compRead[i%2] - compRead[(i-1)%2])
+		d.compWriteMeter.Mark(compWrite[i%2] - compWrite[(i-1)%2])
+		d.writeDelayNMeter.Mark(delayN[i%2] - delayN[(i-1)%2])
+		d.writeDelayMeter.Mark(delayTime[i%2] - delayTime[(i-1)%2])
+		d.diskWriteMeter.Mark(diskWrite[i%2] - diskWrite[(i-1)%2])
+
+		// Sleep a bit, then repeat the stats collection
+		select {
+		case errc = <-d.quitChan:
+			// Quit requesting, stop hammering the database
+		case <-time.After(refresh):
+			// Timeout, gather a new set of stats
+		}
+	}
+	errc <- nil
+}
+
+// batch is a write-only pebble batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	b    *pebble.Batch
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.b.Set(key, value, nil)
+	b.size += len(value)
+	return nil
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.b.Delete(key, nil)
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	return b.b.Commit(pebble.NoSync)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.b.Reset()
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w intdb.Writer) error {
+	reader := b.b.Reader()
+	for {
+		kind, k, v, ok := reader.Next()
+		if !ok {
+			break
+		}
+		// The (k,v) slices might be overwritten if the batch is reset/reused,
+		// and the receiver should copy them if they are to be retained long-term.
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			if err := w.Put(k, v); err != nil {
+				return err
+			}
+		case pebble.InternalKeyKindDelete:
+			if err := w.Delete(k); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unhandled operation, keytype: %v", kind)
+		}
+	}
+	return nil
+}
+
+// pebbleIterator is a wrapper of the underlying iterator in the storage engine,
+// positioning on the first entry at the first call to Next.
+type pebbleIterator struct {
+	iter  *pebble.Iterator
+	moved bool
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (iter *pebbleIterator) Next() bool {
+	if !iter.moved {
+		iter.moved = true
+		return iter.iter.First()
+	}
+	return iter.iter.Next()
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (iter *pebbleIterator) Error() error {
+	return iter.iter.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (iter *pebbleIterator) Key() []byte {
+	if !iter.iter.Valid() {
+		return nil
+	}
+	return iter.iter.Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (iter *pebbleIterator) Value() []byte {
+	if !iter.iter.Valid() {
+		return nil
+	}
+	return iter.iter.Value()
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (iter *pebbleIterator) Release() {
+	if iter.iter != nil {
+		iter.iter.Close()
+		iter.iter = nil
+	}
+}
+
+// pebbleLogger routes the pebble logs into the database logger.
+type pebbleLogger struct {
+	log log.Logger
+}
+
+// Infof logs the informational message of pebble.
+func (l *pebbleLogger) Infof(format string, args ...interface{}) {
+	l.log.Debug(fmt.Sprintf(format, args...))
+}
+
+// Fatalf logs the fatal error of pebble and exits.
+func (l *pebbleLogger) Fatalf(format string, args ...interface{}) {
+	l.log.Crit(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,227 @@
+// +build arm64 amd64
+
+package pebble
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/intfoundation/intchain/crypto"
+	"github.com/intfoundation/intchain/intdb"
+	"github.com/intfoundation/intchain/intdb/leveldb"
+	"github.com/intfoundation/intchain/intdb/memorydb"
+)
+
+// newTestDatabase creates a pebble database in a temporary directory, returning
+// it along with a cleanup function.
+func newTestDatabase(t testing.TB) (*Database, func()) {
+	dir, err := ioutil.TempDir("", "pebble-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	db, err := New(dir, 0, 0, "")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// Tests that the basic operations and the batch behave like the memory database.
+func TestPebbleDB(t *testing.T) {
+	db, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	if err := db.Put([]byte("key"), []byte("val")); err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	if has, err := db.Has([]byte("key")); !has || err != nil {
+		t.Fatalf("inserted key missing: %v %v", has, err)
+	}
+	if val, err := db.Get([]byte("key")); !bytes.Equal(val, []byte("val")) || err != nil {
+		t.Fatalf("value mismatch: have %q, want %q (%v)", val, "val", err)
+	}
+	if err := db.Delete([]byte("key")); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if has, err := db.Has([]byte("key")); has || err != nil {
+		t.Fatalf("deleted key present: %v %v", has, err)
+	}
+	if _, err := db.Get([]byte("key")); err == nil {
+		t.Fatalf("deleted key retrievable")
+	}
+	// Batches are only written on request and can be replayed
+	batch := db.NewBatch()
+	batch.Put([]byte("k1"), []byte("v1"))
+	batch.Put([]byte("k2"), []byte("v2"))
+	batch.Delete([]byte("k1"))
+	if has, _ := db.Has([]byte("k2")); has {
+		t.Fatalf("batch written before commit")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("failed to write batch: %v", err)
+	}
+	mem := memorydb.New()
+	if err := batch.Replay(mem); err != nil {
+		t.Fatalf("failed to replay batch: %v", err)
+	}
+	for _, store := range []intdb.Reader{db, mem} {
+		if has, _ := store.Has([]byte("k1")); has {
+			t.Fatalf("deleted batch key present")
+		}
+		if val, _ := store.Get([]byte("k2")); !bytes.Equal(val, []byte("v2")) {
+			t.Fatalf("batch value mismatch: have %q, want %q", val, "v2")
+		}
+	}
+	batch.Reset()
+	if batch.ValueSize() != 0 {
+		t.Fatalf("batch size not reset: %d", batch.ValueSize())
+	}
+}
+
+// Tests that key-value iteration on top of a pebble database works.
+func TestPebbleDBIterator(t *testing.T) {
+	tests := []struct {
+		content map[string]string
+		prefix  string
+		order   []string
+	}{
+		{map[string]string{}, "", nil},
+		{map[string]string{"key": "val"}, "l", nil},
+		{map[string]string{"k1": "v1", "k3": "v3", "k2": "v2"}, "", []string{"k1", "k2", "k3"}},
+		{map[string]string{"ka1": "va1", "kb1": "vb1", "ka2": "va2", "kc1": "vc1"}, "ka", []string{"ka1", "ka2"}},
+		{map[string]string{"\xff\xff1": "v1", "\xff\xff2": "v2", "\xfe": "v"}, "\xff\xff", []string{"\xff\xff1", "\xff\xff2"}},
+	}
+	for i, tt := range tests {
+		db, cleanup := newTestDatabase(t)
+		for key, val := range tt.content {
+			if err := db.Put([]byte(key), []byte(val)); err != nil {
+				t.Fatalf("test %d: failed to insert item %s:%s into database: %v", i, key, val, err)
+			}
+		}
+		it, idx := db.NewIteratorWithPrefix([]byte(tt.prefix)), 0
+		for it.Next() {
+			if idx >= len(tt.order) {
+				t.Errorf("test %d: prefix=%q more items than expected: checking idx=%d (key %q), expecting len=%d", i, tt.prefix, idx, it.Key(), len(tt.order))
+				break
+			}
+			if !bytes.Equal(it.Key(), []byte(tt.order[idx])) {
+				t.Errorf("test %d: item %d: key mismatch: have %s, want %s", i, idx, string(it.Key()), tt.order[idx])
+			}
+			if !bytes.Equal(it.Value(), []byte(tt.content[tt.order[idx]])) {
+				t.Errorf("test %d: item %d: value mismatch: have %s, want %s", i, idx, string(it.Value()), tt.content[tt.order[idx]])
+			}
+			idx++
+		}
+		if err := it.Error(); err != nil {
+			t.Errorf("test %d: iteration failed: %v", i, err)
+		}
+		if idx != len(tt.order) {
+			t.Errorf("test %d: iteration terminated prematurely: have %d, want %d", i, idx, len(tt.order))
+		}
+		it.Release()
+		cleanup()
+	}
+}
+
+// benchmarkDatabases runs the benchmark on both database engines.
+func benchmarkDatabases(b *testing.B, bench func(b *testing.B, db intdb.KeyValueStore)) {
+	b.Run("leveldb", func(b *testing.B) {
+		dir, err := ioutil.TempDir("", "leveldb-bench")
+		if err != nil {
+			b.Fatalf("failed to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		db, err := leveldb.New(dir, 0, 0, "")
+		if err != nil {
+			b.Fatalf("failed to open database: %v", err)
+		}
+		defer db.Close()
+		bench(b, db)
+	})
+	b.Run("pebble", func(b *testing.B) {
+		db, cleanup := newTestDatabase(b)
+		defer cleanup()
+		bench(b, db)
+	})
+}
+
+// importBlock writes the batch of a block import: a few headers and bodies keyed
+// by number, then a batch of trie nodes keyed by their hash.
+func importBlock(db intdb.KeyValueStore, number uint64) error {
+	batch := db.NewBatch()
+
+	key := make([]byte, 9)
+	binary.BigEndian.PutUint64(key[1:], number)
+	for _, prefix := range []byte("hbr") {
+		key[0] = prefix
+		batch.Put(key, make([]byte, 512))
+	}
+	key[0] = 't'
+	for i := uint64(0); i < 64; i++ {
+		binary.BigEndian.PutUint64(key[1:], number*64+i)
+		batch.Put(crypto.Keccak256(key), make([]byte, 128))
+	}
+	return batch.Write()
+}
+
+// BenchmarkImport measures the writes of a block import.
+func BenchmarkImport(b *testing.B) {
+	benchmarkDatabases(b, func(b *testing.B, db intdb.KeyValueStore) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := importBlock(db, uint64(i)); err != nil {
+				b.Fatalf("failed to import block %d: %v", i, err)
+			}
+		}
+	})
+}
+
+// BenchmarkPrune measures the pruning of imported blocks, which iterates over
+// the block data and deletes it along with the trie nodes, then compacts.
+func BenchmarkPrune(b *testing.B) {
+	benchmarkDatabases(b, func(b *testing.B, db intdb.KeyValueStore) {
+		for i := 0; i < b.N; i++ {
+			if err := importBlock(db, uint64(i)); err != nil {
+				b.Fatalf("failed to import block %d: %v", i, err)
+			}
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		batch := db.NewBatch()
+		it := db.NewIteratorWithPrefix([]byte("h"))
+		for it.Next() {
+			number := binary.BigEndian.Uint64(it.Key()[1:])
+			for _, prefix := range []byte("hbr") {
+				batch.Delete(append([]byte{prefix}, it.Key()[1:]...))
+			}
+			key := []byte{'t', 0, 0, 0, 0, 0, 0, 0, 0}
+			for i := uint64(0); i < 64; i++ {
+				binary.BigEndian.PutUint64(key[1:], number*64+i)
+				batch.Delete(crypto.Keccak256(key))
+			}
+			if batch.ValueSize() >= intdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					b.Fatalf("failed to write batch: %v", err)
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			b.Fatalf("failed to write batch: %v", err)
+		}
+		if err := db.Compact(nil, nil); err != nil {
+			b.Fatalf("failed to compact: %v", err)
+		}
+	})
+}
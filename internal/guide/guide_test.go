@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"github.com/intfoundation/intchain/accounts/keystore"
+	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/core/types"
 )
 
@@ -75,7 +76,7 @@ func TestAccountManagement(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create signer account: %v", err)
 	}
-	tx, chain := new(types.Transaction), big.NewInt(1)
+	tx, chain := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil), big.NewInt(1)
 
 	// Sign a transaction with a single authorization
 	if _, err := ks.SignTxWithPassphrase(signer, "Signer password", tx, chain); err != nil {
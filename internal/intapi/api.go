@@ -35,6 +35,7 @@ import (
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/common/hexutil"
 	"github.com/intfoundation/intchain/common/math"
+	"github.com/intfoundation/intchain/consensus/misc"
 	"github.com/intfoundation/intchain/core"
 	"github.com/intfoundation/intchain/core/rawdb"
 	"github.com/intfoundation/intchain/core/types"
@@ -81,6 +82,42 @@ func (s *PublicINTChainAPI) GasPrice(ctx context.Context) (*hexutil.Big, error)
 	return (*hexutil.Big)(price), err
 }
 
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the fee market history of the given range of blocks,
+// ending with lastBlock.
+func (s *PublicINTChainAPI) FeeHistory(ctx context.Context, blockCount hexutil.Uint, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, int(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	return results, nil
+}
+
 // ProtocolVersion returns the current intchain protocol version this node supports
 func (s *PublicINTChainAPI) ProtocolVersion() hexutil.Uint {
 	return hexutil.Uint(s.b.ProtocolVersion())
@@ -191,6 +228,171 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// TxPoolFilter is the criteria of the transaction pool content filtering. Empty
+// fields match any transaction.
+type TxPoolFilter struct {
+	From        *common.Address `json:"from"`
+	To          *common.Address `json:"to"`
+	Function    string          `json:"function"`
+	MinGasPrice *hexutil.Big    `json:"minGasPrice"`
+	MaxGasPrice *hexutil.Big    `json:"maxGasPrice"`
+}
+
+// matches checks whether the transaction satisfies the filter criteria.
+func (f *TxPoolFilter) matches(tx *types.Transaction, function intAbi.FunctionType) bool {
+	if f.To != nil && (tx.To() == nil || *tx.To() != *f.To) {
+		return false
+	}
+	if f.Function != "" {
+		if !intAbi.IsIntChainContractAddr(tx.To()) || len(tx.Data()) < 4 {
+			return false
+		}
+		if fn, err := intAbi.FunctionTypeFromId(tx.Data()[:4]); err != nil || fn != function {
+			return false
+		}
+	}
+	if f.MinGasPrice != nil && tx.GasPrice().Cmp(f.MinGasPrice.ToInt()) < 0 {
+		return false
+	}
+	if f.MaxGasPrice != nil && tx.GasPrice().Cmp(f.MaxGasPrice.ToInt()) > 0 {
+		return false
+	}
+	return true
+}
+
+// Filter returns the transactions contained within the transaction pool which
+// match the given criteria.
+func (s *PublicTxPoolAPI) Filter(filter TxPoolFilter) (map[string]map[string]map[string]*RPCTransaction, error) {
+	function := intAbi.Unknown
+	if filter.Function != "" {
+		if function = intAbi.StringToFunctionType(filter.Function); function == intAbi.Unknown {
+			return nil, fmt.Errorf("unknown function %q", filter.Function)
+		}
+	}
+	content := map[string]map[string]map[string]*RPCTransaction{
+		"pending": make(map[string]map[string]*RPCTransaction),
+		"queued":  make(map[string]map[string]*RPCTransaction),
+	}
+	pending, queue := s.b.TxPoolContent()
+
+	// Flatten the matching transactions of the requested accounts
+	flatten := func(accounts map[common.Address]types.Transactions, dest map[string]map[string]*RPCTransaction) {
+		for account, txs := range accounts {
+			if filter.From != nil && account != *filter.From {
+				continue
+			}
+			dump := make(map[string]*RPCTransaction)
+			for _, tx := range txs {
+				if filter.matches(tx, function) {
+					dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+				}
+			}
+			if len(dump) > 0 {
+				dest[account.Hex()] = dump
+			}
+		}
+	}
+	flatten(pending, content["pending"])
+	flatten(queue, content["queued"])
+
+	return content, nil
+}
+
+// RPCNonceGap is an inclusive range of nonces missing from the transaction pool.
+type RPCNonceGap struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// RPCQueuedAccount describes why the queued transactions of an account are not
+// executable.
+type RPCQueuedAccount struct {
+	Nonce   hexutil.Uint64    `json:"nonce"`
+	Gaps    []RPCNonceGap     `json:"gaps"`
+	Reasons map[string]string `json:"reasons"`
+}
+
+// Queued returns the nonce gaps of the accounts with queued transactions, along
+// with the reason of each queued transaction (keyed by nonce) not being executable.
+func (s *PublicTxPoolAPI) Queued() map[string]*RPCQueuedAccount {
+	queued := make(map[string]*RPCQueuedAccount)
+	for account, status := range s.b.TxPoolQueued() {
+		dump := &RPCQueuedAccount{
+			Nonce:   hexutil.Uint64(status.Nonce),
+			Gaps:    make([]RPCNonceGap, 0, len(status.Gaps)),
+			Reasons: make(map[string]string),
+		}
+		for _, gap := range status.Gaps {
+			dump.Gaps = append(dump.Gaps, RPCNonceGap{From: hexutil.Uint64(gap.From), To: hexutil.Uint64(gap.To)})
+		}
+		for nonce, reason := range status.Reasons {
+			if reason == nil {
+				dump.Reasons[fmt.Sprintf("%d", nonce)] = "awaiting promotion"
+			} else {
+				dump.Reasons[fmt.Sprintf("%d", nonce)] = reason.Error()
+			}
+		}
+		queued[account.Hex()] = dump
+	}
+	return queued
+}
+
+// RPCTxDrop is the notification of a transaction dropped from the transaction pool.
+type RPCTxDrop struct {
+	Hash        common.Hash    `json:"hash"`
+	From        common.Address `json:"from"`
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	Reason      string         `json:"reason"`
+	Replacement *common.Hash   `json:"replacement"`
+}
+
+// Drops creates a subscription that is triggered each time a transaction is
+// dropped or replaced in the transaction pool, notifying the reason of the drop.
+func (s *PublicTxPoolAPI) Drops(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		drops := make(chan core.TxDropEvent, 128)
+		dropSub := s.b.SubscribeTxDropEvent(drops)
+
+		for {
+			select {
+			case ev := <-drops:
+				var signer types.Signer = types.FrontierSigner{}
+				if ev.Tx.Protected() {
+					signer = types.LatestSignerForChainID(ev.Tx.ChainId())
+				}
+				from, _ := types.Sender(signer, ev.Tx)
+
+				drop := &RPCTxDrop{
+					Hash:   ev.Tx.Hash(),
+					From:   from,
+					Nonce:  hexutil.Uint64(ev.Tx.Nonce()),
+					Reason: string(ev.Reason),
+				}
+				if ev.Replacement != nil {
+					hash := ev.Replacement.Hash()
+					drop.Replacement = &hash
+				}
+				notifier.Notify(rpcSub.ID, drop)
+			case <-rpcSub.Err():
+				dropSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				dropSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -402,8 +604,8 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	if args.Gas == nil {
 		return nil, fmt.Errorf("gas not specified")
 	}
-	if args.GasPrice == nil {
-		return nil, fmt.Errorf("gasPrice not specified")
+	if args.GasPrice == nil && (args.MaxPriorityFeePerGas == nil || args.MaxFeePerGas == nil) {
+		return nil, fmt.Errorf("missing gasPrice or maxFeePerGas/maxPriorityFeePerGas")
 	}
 	if args.Nonce == nil {
 		return nil, fmt.Errorf("nonce not specified")
@@ -412,7 +614,7 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(signed)
+	data, err := signed.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -423,7 +625,8 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 // safely used to calculate a signature from.
 //
 // The hash is calulcated as
-//   keccak256("\x19INT Chain Signed Message:\n"${message length}${message}).
+//
+//	keccak256("\x19INT Chain Signed Message:\n"${message length}${message}).
 //
 // This gives context to the signed message and prevents signing of transactions.
 func signHash(data []byte) []byte {
@@ -700,35 +903,132 @@ type CallArgs struct {
 	GasPrice hexutil.Big     `json:"gasPrice"`
 	Value    hexutil.Big     `json:"value"`
 	Data     hexutil.Bytes   `json:"data"`
+
+	// Introduced by AccessListTxType transaction.
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+
+	// Introduced by DynamicFeeTxType transaction.
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas,omitempty"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) (*core.ExecutionResult, error) {
+// ToMessage converts the call arguments to the message executed by the EVM. The
+// call fees default to the legacy gas price before the London fork, and to zero
+// after it, in which case the message has to run with the base fee disabled.
+func (args *CallArgs) ToMessage(baseFee *big.Int) (types.Message, error) {
+	if args.GasPrice.ToInt().Sign() != 0 && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
+		return types.Message{}, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	// Set default gas & gas price if none were set
+	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
+	if gas == 0 {
+		gas = math.MaxUint64 / 2
+	}
+	gasFeeCap, gasTipCap := gasPrice, gasPrice
+	switch {
+	case baseFee == nil:
+		// Legacy execution before the London fork
+		if gasPrice.Sign() == 0 {
+			gasPrice = new(big.Int).SetUint64(defaultGasPrice)
+		}
+		gasFeeCap, gasTipCap = gasPrice, gasPrice
+
+	case gasPrice.Sign() == 0:
+		// Dynamic fee execution, a call without any fee runs with a zero base fee
+		gasFeeCap, gasTipCap = new(big.Int), new(big.Int)
+		if args.MaxFeePerGas != nil {
+			gasFeeCap = args.MaxFeePerGas.ToInt()
+		}
+		if args.MaxPriorityFeePerGas != nil {
+			gasTipCap = args.MaxPriorityFeePerGas.ToInt()
+		}
+		gasPrice = new(big.Int)
+		if gasFeeCap.BitLen() > 0 || gasTipCap.BitLen() > 0 {
+			gasPrice = math.BigMin(new(big.Int).Add(gasTipCap, baseFee), gasFeeCap)
+		}
+	}
+
+	var accessList types.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+	return types.NewMessage(args.From, args.To, 0, args.Value.ToInt(), gas, gasPrice, gasFeeCap, gasTipCap, args.Data, accessList, false), nil
+}
+
+// OverrideAccount specifies the fields of an account to replace before a call
+// is executed. Missing fields are left untouched.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   **hexutil.Big                `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of overridden accounts.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of the specified accounts in the given state. The
+// state must be a private copy, the overrides are never meant to be committed.
+func (diff *StateOverride) Apply(state *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		// Override account nonce.
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		// Override account (contract) code.
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		// Override account balance.
+		if account.Balance != nil {
+			state.SetBalance(addr, (*big.Int)(*account.Balance))
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		// Replace entire state if caller requires.
+		if account.State != nil {
+			state.SetStorage(addr, *account.State)
+		}
+		// Apply state diff into specified accounts.
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				state.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration) (*core.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, err
 	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
 	// Set sender address or use a default if none specified
-	addr := args.From
-	if addr == (common.Address{}) {
+	if args.From == (common.Address{}) {
 		if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
 			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
-				addr = accounts[0].Address
+				args.From = accounts[0].Address
 			}
 		}
 	}
-	// Set default gas & gas price if none were set
-	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
-	if gas == 0 {
-		gas = math.MaxUint64 / 2
-	}
-	if gasPrice.Sign() == 0 {
-		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
-	}
-
 	// Create new call message
-	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+	msg, err := args.ToMessage(header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	// Calls without any fee run with a zero base fee, others are checked against it
+	vmCfg.NoBaseFee = true
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -804,8 +1104,9 @@ func (e *revertError) ErrorData() interface{} {
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
+// The fields of any account can be overridden for the call, on a copy of the state.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (hexutil.Bytes, error) {
+	result, err := s.doCall(ctx, args, blockNr, overrides, vm.Config{}, 5*time.Second)
 	//return (hexutil.Bytes)(result), err
 
 	if err != nil {
@@ -850,7 +1151,7 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	executable := func(gas uint64) (bool, *core.ExecutionResult, error) {
 		args.Gas = hexutil.Uint64(gas)
 
-		result, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{}, 0)
+		result, err := s.doCall(ctx, args, rpc.PendingBlockNumber, nil, vm.Config{}, 0)
 		//if err != nil || failed {
 		//	return false
 		//}
@@ -996,6 +1297,9 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 		"transactionsRoot": head.TxHash,
 		"receiptsRoot":     head.ReceiptHash,
 	}
+	if head.BaseFee != nil {
+		fields["baseFeePerGas"] = (*hexutil.Big)(head.BaseFee)
+	}
 
 	if inclTx {
 		formatTx := func(tx *types.Transaction) (interface{}, error) {
@@ -1031,33 +1335,41 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        common.Hash     `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
+	BlockHash        common.Hash       `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Gas              hexutil.Uint64    `json:"gas"`
+	GasPrice         *hexutil.Big      `json:"gasPrice"`
+	GasFeeCap        *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	GasTipCap        *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	TransactionIndex hexutil.Uint      `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	Type             hexutil.Uint64    `json:"type"`
+	Accesses         *types.AccessList `json:"accessList,omitempty"`
+	ChainID          *hexutil.Big      `json:"chainId,omitempty"`
+	V                *hexutil.Big      `json:"v"`
+	R                *hexutil.Big      `json:"r"`
+	S                *hexutil.Big      `json:"s"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
-// representation, with the given location metadata set (if available).
-func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
+// representation, with the given location metadata set (if available). The
+// base fee of the including block is used to report the effective gas price
+// of dynamic fee transactions.
+func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64, baseFee *big.Int) *RPCTransaction {
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.LatestSignerForChainID(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 	v, r, s := tx.RawSignatureValues()
 
 	result := &RPCTransaction{
+		Type:     hexutil.Uint64(tx.Type()),
 		From:     from,
 		Gas:      hexutil.Uint64(tx.Gas()),
 		GasPrice: (*hexutil.Big)(tx.GasPrice()),
@@ -1070,6 +1382,24 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
 	}
+	switch tx.Type() {
+	case types.AccessListTxType:
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+	case types.DynamicFeeTxType:
+		al := tx.AccessList()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// Report the effective price once the transaction is included,
+		// otherwise the fee cap is the best available estimate.
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			price := math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee), tx.GasFeeCap())
+			result.GasPrice = (*hexutil.Big)(price)
+		}
+	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -1080,7 +1410,7 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 
 // newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
 func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
-	return newRPCTransaction(tx, common.Hash{}, 0, 0)
+	return newRPCTransaction(tx, common.Hash{}, 0, 0, nil)
 }
 
 // newRPCTransactionFromBlockIndex returns a transaction that will serialize to the RPC representation.
@@ -1089,7 +1419,7 @@ func newRPCTransactionFromBlockIndex(b *types.Block, index uint64) *RPCTransacti
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	return newRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index)
+	return newRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index, b.BaseFee())
 }
 
 // newRPCRawTransactionFromBlockIndex returns the bytes of a transaction given a block and a transaction index.
@@ -1098,7 +1428,7 @@ func newRPCRawTransactionFromBlockIndex(b *types.Block, index uint64) hexutil.By
 	if index >= uint64(len(txs)) {
 		return nil
 	}
-	blob, _ := rlp.EncodeToBytes(txs[index])
+	blob, _ := txs[index].MarshalBinary()
 	return blob
 }
 
@@ -1187,7 +1517,11 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *RPCTransaction {
 	// Try to return an already finalized transaction
 	if tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash); tx != nil {
-		return newRPCTransaction(tx, blockHash, blockNumber, index)
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+		if err != nil || header == nil {
+			return nil
+		}
+		return newRPCTransaction(tx, blockHash, blockNumber, index, header.BaseFee)
 	}
 	// No finalized transaction, try to retrieve it from the pool
 	if tx := s.b.GetPoolTransaction(hash); tx != nil {
@@ -1208,8 +1542,8 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 			return nil, nil
 		}
 	}
-	// Serialize to RLP and return
-	return rlp.EncodeToBytes(tx)
+	// Serialize to the canonical encoding and return
+	return tx.MarshalBinary()
 }
 
 type Log struct {
@@ -1256,11 +1590,12 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainId())
+		signer = types.LatestSignerForChainID(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
 
 	fields := map[string]interface{}{
+		"type":              hexutil.Uint(tx.Type()),
 		"blockHash":         blockHash,
 		"blockNumber":       hexutil.Uint64(blockNumber),
 		"transactionHash":   hash,
@@ -1274,6 +1609,18 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		"logsBloom":         receipt.Bloom,
 	}
 
+	// Assign the effective gas price paid, which is lower than the fee cap
+	// for dynamic fee transactions included after the London fork.
+	header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if header == nil || header.BaseFee == nil {
+		fields["effectiveGasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		gasPrice := new(big.Int).Add(header.BaseFee, tx.EffectiveGasTipValue(header.BaseFee))
+		fields["effectiveGasPrice"] = (*hexutil.Big)(gasPrice)
+	}
 	// Assign receipt status or post state.
 	if len(receipt.PostState) > 0 {
 		fields["root"] = hexutil.Bytes(receipt.PostState)
@@ -1320,6 +1667,14 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+
+	// For non-legacy transactions
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
+
+	// For dynamic fee transactions
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1355,7 +1710,34 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		}
 	}
 
-	if args.GasPrice == nil {
+	dynamicFee := args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil
+	if args.GasPrice != nil && dynamicFee {
+		return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	if dynamicFee {
+		head := b.CurrentBlock().Header()
+		if !b.ChainConfig().IsLondon(new(big.Int).Add(head.Number, big.NewInt(1))) {
+			return errors.New(`dynamic fee transactions are not supported before the London fork`)
+		}
+		if args.MaxPriorityFeePerGas == nil {
+			tip, err := b.SuggestPrice(ctx)
+			if err != nil {
+				return err
+			}
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(tip)
+		}
+		if args.MaxFeePerGas == nil {
+			// Leave room for the base fee to double before the transaction is included
+			gasFeeCap := new(big.Int).Add(
+				(*big.Int)(args.MaxPriorityFeePerGas),
+				new(big.Int).Mul(misc.CalcBaseFee(b.ChainConfig(), head), big.NewInt(2)),
+			)
+			args.MaxFeePerGas = (*hexutil.Big)(gasFeeCap)
+		}
+		if args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) < 0 {
+			return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", args.MaxFeePerGas, args.MaxPriorityFeePerGas)
+		}
+	} else if args.GasPrice == nil {
 		price, err := b.SuggestPrice(ctx)
 		if err != nil {
 			return err
@@ -1389,6 +1771,18 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		args.Nonce = (*hexutil.Uint64)(&nonce)
 	}
 
+	// The chain id is only encoded by the typed transactions
+	if args.AccessList != nil || dynamicFee {
+		if !b.ChainConfig().IsBerlin(new(big.Int).Add(b.CurrentBlock().Number(), big.NewInt(1))) {
+			return errors.New(`access list transactions are not supported before the Berlin fork`)
+		}
+		if args.ChainID == nil {
+			args.ChainID = (*hexutil.Big)(b.ChainConfig().ChainId)
+		} else if args.ChainID.ToInt().Cmp(b.ChainConfig().ChainId) != 0 {
+			return fmt.Errorf("chainId does not match node's (have=%v, want=%v)", args.ChainID, b.ChainConfig().ChainId)
+		}
+	}
+
 	return nil
 }
 
@@ -1399,10 +1793,46 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Input != nil {
 		input = *args.Input
 	}
-	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	var data types.TxData
+	switch {
+	case args.MaxFeePerGas != nil:
+		al := types.AccessList{}
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		data = &types.DynamicFeeTx{
+			To:         args.To,
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			Gas:        uint64(*args.Gas),
+			GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
+			GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
+			Value:      (*big.Int)(args.Value),
+			Data:       input,
+			AccessList: al,
+		}
+	case args.AccessList == nil:
+		data = &types.LegacyTx{
+			To:       args.To,
+			Nonce:    uint64(*args.Nonce),
+			Gas:      uint64(*args.Gas),
+			GasPrice: (*big.Int)(args.GasPrice),
+			Value:    (*big.Int)(args.Value),
+			Data:     input,
+		}
+	default:
+		data = &types.AccessListTx{
+			To:         args.To,
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			Gas:        uint64(*args.Gas),
+			GasPrice:   (*big.Int)(args.GasPrice),
+			Value:      (*big.Int)(args.Value),
+			Data:       input,
+			AccessList: *args.AccessList,
+		}
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	return types.NewTx(data)
 }
 
 // submitTransaction is a helper function that submits tx to txPool and logs a message.
@@ -1500,12 +1930,38 @@ func SendTransaction(ctx context.Context, args SendTxArgs, am *accounts.Manager,
 // The sender is responsible for signing the transaction and using the correct nonce.
 func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
 	tx := new(types.Transaction)
-	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
 		return common.Hash{}, err
 	}
 	return submitTransaction(ctx, s.b, tx)
 }
 
+// defaultPrivateTxBlocks is the number of blocks a private transaction waits for
+// the inclusion if not requested otherwise.
+const defaultPrivateTxBlocks = 25
+
+// SendPrivateRawTransaction will add the signed transaction to the transaction pool
+// as a private one. It is only sent to the proposers of the upcoming blocks, never
+// gossiped, and dropped if not included within maxBlocks blocks.
+func (s *PublicTransactionPoolAPI) SendPrivateRawTransaction(ctx context.Context, encodedTx hexutil.Bytes, maxBlocks *hexutil.Uint64) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
+		return common.Hash{}, err
+	}
+	blocks := uint64(defaultPrivateTxBlocks)
+	if maxBlocks != nil {
+		blocks = uint64(*maxBlocks)
+	}
+	if blocks == 0 {
+		return common.Hash{}, errors.New("maxBlocks must be positive")
+	}
+	if err := s.b.SendPrivateTx(ctx, tx, blocks); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted private transaction", "fullhash", tx.Hash().Hex(), "recipient", tx.To(), "blocks", blocks)
+	return tx.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19INT Chain Signed Message:\n" + len(message) + message).
 //
@@ -1513,7 +1969,6 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 // where the V value will be 27 or 28 for legacy reasons.
 //
 // The account associated with addr must be unlocked.
-//
 func (s *PublicTransactionPoolAPI) Sign(addr common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
 	// Look up the wallet containing the requested signer
 	account := accounts.Account{Address: addr}
@@ -1556,7 +2011,7 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return nil, err
 	}
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
@@ -1575,7 +2030,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 	for _, tx := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if tx.Protected() {
-			signer = types.NewEIP155Signer(tx.ChainId())
+			signer = types.LatestSignerForChainID(tx.ChainId())
 		}
 		from, _ := types.Sender(signer, tx)
 		if _, err := s.b.AccountManager().Find(accounts.Account{Address: from}); err == nil {
@@ -1603,14 +2058,18 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	for _, p := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if p.Protected() {
-			signer = types.NewEIP155Signer(p.ChainId())
+			signer = types.LatestSignerForChainID(p.ChainId())
 		}
 		wantSigHash := signer.Hash(matchTx)
 
 		if pFrom, err := types.Sender(signer, p); err == nil && pFrom == sendArgs.From && signer.Hash(p) == wantSigHash {
 			// Match. Re-sign and send the transaction.
 			if gasPrice != nil && (*big.Int)(gasPrice).Sign() != 0 {
-				sendArgs.GasPrice = gasPrice
+				if sendArgs.MaxFeePerGas != nil {
+					sendArgs.MaxFeePerGas = gasPrice
+				} else {
+					sendArgs.GasPrice = gasPrice
+				}
 			}
 			if gasLimit != nil && *gasLimit != 0 {
 				sendArgs.Gas = gasLimit
@@ -1974,6 +2433,12 @@ func init() {
 	// Set Address
 	core.RegisterValidateCb(intAbi.SetAddress, setAddressValidateCb)
 	core.RegisterApplyCb(intAbi.SetAddress, setAddressApplyCb)
+
+	// Staking Precompiled Contract
+	core.RegisterEVMStakingCb(intAbi.Delegate, delegateEVMStakingCb)
+	core.RegisterEVMStakingCb(intAbi.UnDelegate, unDelegateEVMStakingCb)
+	core.RegisterEVMStakingCb(intAbi.WithdrawReward, withdrawRewardEVMStakingCb)
+	core.RegisterUpdateNextEpochCb(updateNextEpochValidatorVoteSet)
 }
 
 func withdrawRewardValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
@@ -1994,9 +2459,17 @@ func withdrawRewardApplyCb(tx *types.Transaction, state *state.StateDB, bc *core
 		return err
 	}
 
-	//reward := state.GetRewardBalanceByDelegateAddress(from, args.DelegateAddress)
-	state.SubRewardBalanceByDelegateAddress(from, args.DelegateAddress, args.Amount)
-	state.AddBalance(from, args.Amount)
+	applyWithdrawReward(from, args.DelegateAddress, args.Amount, state)
+
+	return nil
+}
+
+func withdrawRewardEVMStakingCb(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *core.BlockChain) error {
+	if err := checkWithdrawReward(from, candidate, amount, state); err != nil {
+		return err
+	}
+
+	applyWithdrawReward(from, candidate, amount, state)
 
 	return nil
 }
@@ -2009,20 +2482,33 @@ func withDrawRewardValidation(from common.Address, tx *types.Transaction, state
 		return nil, err
 	}
 
-	reward := state.GetRewardBalanceByDelegateAddress(from, args.DelegateAddress)
+	if err := checkWithdrawReward(from, args.DelegateAddress, args.Amount, state); err != nil {
+		return nil, err
+	}
+	return &args, nil
+}
+
+func checkWithdrawReward(from, delegateAddress common.Address, amount *big.Int, state *state.StateDB) error {
+	reward := state.GetRewardBalanceByDelegateAddress(from, delegateAddress)
 
 	if reward.Sign() < 1 {
-		return nil, fmt.Errorf("have no reward to withdraw")
+		return fmt.Errorf("have no reward to withdraw")
 	}
 
-	if args.Amount.Sign() == -1 {
-		return nil, fmt.Errorf("widthdraw amount can not be negative")
+	if amount.Sign() == -1 {
+		return fmt.Errorf("widthdraw amount can not be negative")
 	}
 
-	if args.Amount.Cmp(reward) == 1 {
-		return nil, fmt.Errorf("reward balance not enough, withdraw amount %v, but balance %v, delegate address %v", args.Amount, reward, args.DelegateAddress)
+	if amount.Cmp(reward) == 1 {
+		return fmt.Errorf("reward balance not enough, withdraw amount %v, but balance %v, delegate address %v", amount, reward, delegateAddress)
 	}
-	return &args, nil
+	return nil
+}
+
+func applyWithdrawReward(from, delegateAddress common.Address, amount *big.Int, state *state.StateDB) {
+	//reward := state.GetRewardBalanceByDelegateAddress(from, args.DelegateAddress)
+	state.SubRewardBalanceByDelegateAddress(from, delegateAddress, amount)
+	state.AddBalance(from, amount)
 }
 
 // register and unregister
@@ -2066,7 +2552,7 @@ func registerApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Block
 	// mark address candidate
 	//state.MarkAddressCandidate(from)
 
-	verror = updateNextEpochValidatorVoteSet(tx, state, bc, from, ops)
+	verror = updateNextEpochValidatorVoteSet(from, tx.Hash(), state, bc, ops)
 	if verror != nil {
 		return verror
 	}
@@ -2214,14 +2700,9 @@ func delegateApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Block
 	}
 
 	// Do job
-	amount := tx.Value()
-	// Move Balance to delegate balance
-	state.SubBalance(from, amount)
-	state.AddDelegateBalance(from, amount)
-	// Add Balance to Candidate's Proxied Balance
-	state.AddProxiedBalanceByUser(args.Candidate, from, amount)
+	applyDelegate(from, args.Candidate, tx.Value(), state)
 
-	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.Candidate, ops)
+	verror = updateNextEpochValidatorVoteSet(args.Candidate, tx.Hash(), state, bc, ops)
 	if verror != nil {
 		return verror
 	}
@@ -2229,30 +2710,62 @@ func delegateApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Block
 	return nil
 }
 
-func delegateValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*intAbi.DelegateArgs, error) {
-	// Check minimum delegate amount
-	if tx.Value().Sign() == -1 {
-		return nil, core.ErrDelegateAmount
+// delegateEVMStakingCb delegates for the contract calling the staking precompiled contract,
+// the next epoch vote set is updated by the state processor after the transaction succeeded
+func delegateEVMStakingCb(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *core.BlockChain) error {
+	if err := checkDelegate(from, candidate, amount, state, bc); err != nil {
+		return err
 	}
 
+	// block height validation
+	if err := updateValidation(bc); err != nil {
+		return err
+	}
+
+	applyDelegate(from, candidate, amount, state)
+
+	return nil
+}
+
+func applyDelegate(from, candidate common.Address, amount *big.Int, state *state.StateDB) {
+	// Move Balance to delegate balance
+	state.SubBalance(from, amount)
+	state.AddDelegateBalance(from, amount)
+	// Add Balance to Candidate's Proxied Balance
+	state.AddProxiedBalanceByUser(candidate, from, amount)
+}
+
+func delegateValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*intAbi.DelegateArgs, error) {
 	var args intAbi.DelegateArgs
 	data := tx.Data()
 	if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.Delegate.String(), data[4:]); err != nil {
 		return nil, err
 	}
 
+	if err := checkDelegate(from, args.Candidate, tx.Value(), state, bc); err != nil {
+		return nil, err
+	}
+	return &args, nil
+}
+
+func checkDelegate(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *core.BlockChain) error {
+	// Check minimum delegate amount
+	if amount.Sign() == -1 {
+		return core.ErrDelegateAmount
+	}
+
 	// Check Candidate
-	if !state.IsCandidate(args.Candidate) {
-		return nil, core.ErrNotCandidate
+	if !state.IsCandidate(candidate) {
+		return core.ErrNotCandidate
 	}
 
-	depositBalance := state.GetDepositProxiedBalanceByUser(args.Candidate, from)
+	depositBalance := state.GetDepositProxiedBalanceByUser(candidate, from)
 	if depositBalance.Sign() == 0 {
 		// Check if exceed the limit of delegated addresses
 		// if exceed the limit of delegation address number, return error
-		delegatedAddressNumber := state.GetProxiedAddressNumber(args.Candidate)
+		delegatedAddressNumber := state.GetProxiedAddressNumber(candidate)
 		if delegatedAddressNumber >= maxDelegationAddresses {
-			return nil, core.ErrExceedDelegationAddressLimit
+			return core.ErrExceedDelegationAddressLimit
 		}
 	}
 
@@ -2261,17 +2774,17 @@ func delegateValidation(from common.Address, tx *types.Transaction, state *state
 	if tdm, ok := bc.Engine().(consensus.IPBFT); ok {
 		ep = tdm.GetEpoch().GetEpochByBlockNumber(bc.CurrentBlock().NumberU64())
 	}
-	if _, supernode := ep.Validators.GetByAddress(args.Candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
+	if _, supernode := ep.Validators.GetByAddress(candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
 		if depositBalance.Sign() == 0 {
-			return nil, core.ErrCannotDelegate
+			return core.ErrCannotDelegate
 		}
 	}
 
 	// Check Epoch Height
 	//if _, err := getEpoch(bc); err != nil {
-	//	return nil, err
+	//	return err
 	//}
-	return &args, nil
+	return nil
 }
 
 func unDelegateValidateCb(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) error {
@@ -2298,30 +2811,51 @@ func unDelegateApplyCb(tx *types.Transaction, state *state.StateDB, bc *core.Blo
 	}
 
 	// Apply Logic
+	applyUnDelegate(from, args.Candidate, args.Amount, state)
+
+	verror = updateNextEpochValidatorVoteSet(args.Candidate, tx.Hash(), state, bc, ops)
+	if verror != nil {
+		return verror
+	}
+
+	return nil
+}
+
+// unDelegateEVMStakingCb cancels the delegation of the contract calling the staking precompiled contract,
+// the next epoch vote set is updated by the state processor after the transaction succeeded
+func unDelegateEVMStakingCb(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *core.BlockChain) error {
+	if err := checkUnDelegate(from, candidate, amount, state, bc); err != nil {
+		return err
+	}
+
+	// block height validation
+	if err := updateValidation(bc); err != nil {
+		return err
+	}
+
+	applyUnDelegate(from, candidate, amount, state)
+
+	return nil
+}
+
+func applyUnDelegate(from, candidate common.Address, amount *big.Int, state *state.StateDB) {
 	// if request amount < proxied amount, refund it immediately
 	// otherwise, refund the proxied amount, and put the rest to pending refund balance
-	proxiedBalance := state.GetProxiedBalanceByUser(args.Candidate, from)
+	proxiedBalance := state.GetProxiedBalanceByUser(candidate, from)
 	var immediatelyRefund *big.Int
-	if args.Amount.Cmp(proxiedBalance) <= 0 {
-		immediatelyRefund = args.Amount
+	if amount.Cmp(proxiedBalance) <= 0 {
+		immediatelyRefund = amount
 	} else {
 		immediatelyRefund = proxiedBalance
-		restRefund := new(big.Int).Sub(args.Amount, proxiedBalance)
-		state.AddPendingRefundBalanceByUser(args.Candidate, from, restRefund)
+		restRefund := new(big.Int).Sub(amount, proxiedBalance)
+		state.AddPendingRefundBalanceByUser(candidate, from, restRefund)
 		// TODO Add Pending Refund Set, Commit the Refund Set
-		state.MarkDelegateAddressRefund(args.Candidate)
+		state.MarkDelegateAddressRefund(candidate)
 	}
 
-	state.SubProxiedBalanceByUser(args.Candidate, from, immediatelyRefund)
+	state.SubProxiedBalanceByUser(candidate, from, immediatelyRefund)
 	state.SubDelegateBalance(from, immediatelyRefund)
 	state.AddBalance(from, immediatelyRefund)
-
-	verror = updateNextEpochValidatorVoteSet(tx, state, bc, args.Candidate, ops)
-	if verror != nil {
-		return verror
-	}
-
-	return nil
 }
 
 func unDelegateValidation(from common.Address, tx *types.Transaction, state *state.StateDB, bc *core.BlockChain) (*intAbi.UnDelegateArgs, error) {
@@ -2332,13 +2866,21 @@ func unDelegateValidation(from common.Address, tx *types.Transaction, state *sta
 		return nil, err
 	}
 
-	if args.Amount.Sign() == -1 {
-		return nil, fmt.Errorf("undelegate amount can not be negative")
+	if err := checkUnDelegate(from, args.Candidate, args.Amount, state, bc); err != nil {
+		return nil, err
+	}
+
+	return &args, nil
+}
+
+func checkUnDelegate(from, candidate common.Address, amount *big.Int, state *state.StateDB, bc *core.BlockChain) error {
+	if amount.Sign() == -1 {
+		return fmt.Errorf("undelegate amount can not be negative")
 	}
 
 	// Check Self Address
-	if from == args.Candidate {
-		return nil, core.ErrCancelSelfDelegate
+	if from == candidate {
+		return core.ErrCancelSelfDelegate
 	}
 
 	// Super node Candidate can't decrease balance
@@ -2346,20 +2888,20 @@ func unDelegateValidation(from common.Address, tx *types.Transaction, state *sta
 	if tdm, ok := bc.Engine().(consensus.IPBFT); ok {
 		ep = tdm.GetEpoch().GetEpochByBlockNumber(bc.CurrentBlock().NumberU64())
 	}
-	if _, supernode := ep.Validators.GetByAddress(args.Candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
-		return nil, core.ErrCannotUnBond
+	if _, supernode := ep.Validators.GetByAddress(candidate.Bytes()); supernode != nil && supernode.RemainingEpoch > 0 {
+		return core.ErrCannotUnBond
 	}
 
 	// Check Proxied Amount in Candidate Balance
-	proxiedBalance := state.GetProxiedBalanceByUser(args.Candidate, from)
-	depositProxiedBalance := state.GetDepositProxiedBalanceByUser(args.Candidate, from)
-	pendingRefundBalance := state.GetPendingRefundBalanceByUser(args.Candidate, from)
+	proxiedBalance := state.GetProxiedBalanceByUser(candidate, from)
+	depositProxiedBalance := state.GetDepositProxiedBalanceByUser(candidate, from)
+	pendingRefundBalance := state.GetPendingRefundBalanceByUser(candidate, from)
 	// net = deposit - pending refund
 	netDeposit := new(big.Int).Sub(depositProxiedBalance, pendingRefundBalance)
 	// available = proxied + net
 	availableRefundBalance := new(big.Int).Add(proxiedBalance, netDeposit)
-	if args.Amount.Cmp(availableRefundBalance) == 1 {
-		return nil, core.ErrInsufficientProxiedBalance
+	if amount.Cmp(availableRefundBalance) == 1 {
+		return core.ErrInsufficientProxiedBalance
 	}
 
 	// if left, the left must be greater than the min delegate amount
@@ -2370,10 +2912,10 @@ func unDelegateValidation(from common.Address, tx *types.Transaction, state *sta
 
 	// Check Epoch Height
 	if _, err := getEpoch(bc); err != nil {
-		return nil, err
+		return err
 	}
 
-	return &args, nil
+	return nil
 }
 
 // set commission
@@ -2499,7 +3041,7 @@ func getEpoch(bc *core.BlockChain) (*epoch.Epoch, error) {
 }
 
 func derivedAddressFromTx(tx *types.Transaction) (from common.Address) {
-	signer := types.NewEIP155Signer(tx.ChainId())
+	signer := types.LatestSignerForChainID(tx.ChainId())
 	from, _ = types.Sender(signer, tx)
 	return
 }
@@ -2519,7 +3061,7 @@ func updateValidation(bc *core.BlockChain) error {
 	return nil
 }
 
-func updateNextEpochValidatorVoteSet(tx *types.Transaction, state *state.StateDB, bc *core.BlockChain, candidate common.Address, ops *types.PendingOps) error {
+func updateNextEpochValidatorVoteSet(candidate common.Address, txHash common.Hash, state *state.StateDB, bc *core.BlockChain, ops *types.PendingOps) error {
 	var update bool
 	ep, err := getEpoch(bc)
 	if err != nil {
@@ -2583,7 +3125,7 @@ func updateNextEpochValidatorVoteSet(tx *types.Transaction, state *state.StateDB
 			PubKey: blsPK,
 			Amount: netProxied,
 			Salt:   "intchain",
-			TxHash: tx.Hash(),
+			TxHash: txHash,
 		}
 
 		if ok := ops.Append(&op); !ok {
@@ -6,8 +6,12 @@ import (
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/common/hexutil"
 	"github.com/intfoundation/intchain/common/math"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/crypto"
 	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -147,3 +151,98 @@ func TestGoTime(t *testing.T) {
 	fmt.Printf("duration string %v\n", d.String())
 	fmt.Printf("duration seconds %v\n", d.Seconds())
 }
+
+func TestTxPoolFilter(t *testing.T) {
+	delegate, _ := intAbi.ChainABI.Pack(intAbi.Delegate.String(), common.HexToAddress("0x26ee0906f135303a0ab66b3196efabd0853c481b"))
+
+	var (
+		other   = common.HexToAddress("0x01")
+		plain   = types.NewTransaction(0, other, big.NewInt(1), 21000, big.NewInt(10), nil)
+		special = types.NewTransaction(1, intAbi.ChainContractMagicAddr, big.NewInt(1), 21000, big.NewInt(20), delegate)
+	)
+	tests := []struct {
+		filter  TxPoolFilter
+		plain   bool
+		special bool
+	}{
+		{TxPoolFilter{}, true, true},
+		{TxPoolFilter{To: &other}, true, false},
+		{TxPoolFilter{To: &intAbi.ChainContractMagicAddr}, false, true},
+		{TxPoolFilter{Function: intAbi.Delegate.String()}, false, true},
+		{TxPoolFilter{Function: intAbi.UnDelegate.String()}, false, false},
+		{TxPoolFilter{MinGasPrice: (*hexutil.Big)(big.NewInt(15))}, false, true},
+		{TxPoolFilter{MaxGasPrice: (*hexutil.Big)(big.NewInt(15))}, true, false},
+		{TxPoolFilter{MinGasPrice: (*hexutil.Big)(big.NewInt(10)), MaxGasPrice: (*hexutil.Big)(big.NewInt(20))}, true, true},
+	}
+	for i, tt := range tests {
+		function := intAbi.StringToFunctionType(tt.filter.Function)
+		if have := tt.filter.matches(plain, function); have != tt.plain {
+			t.Errorf("test %d: plain transaction match mismatch: have %v, want %v", i, have, tt.plain)
+		}
+		if have := tt.filter.matches(special, function); have != tt.special {
+			t.Errorf("test %d: special transaction match mismatch: have %v, want %v", i, have, tt.special)
+		}
+	}
+}
+
+func TestStateOverride(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		replaced   = common.HexToAddress("0x01")
+		patched    = common.HexToAddress("0x02")
+		one, two   = common.HexToHash("0x01"), common.HexToHash("0x02")
+		value      = common.HexToHash("0xff")
+	)
+	statedb.SetState(replaced, one, one)
+	statedb.SetState(replaced, two, two)
+	statedb.SetState(patched, one, one)
+	statedb.SetState(patched, two, two)
+
+	nonce, code, balance := hexutil.Uint64(5), hexutil.Bytes{0x60, 0x00}, (*hexutil.Big)(big.NewInt(100))
+	overrides := StateOverride{
+		replaced: {Nonce: &nonce, Code: &code, Balance: &balance, State: &map[common.Hash]common.Hash{one: value}},
+		patched:  {StateDiff: &map[common.Hash]common.Hash{one: value}},
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		t.Fatalf("failed to apply overrides: %v", err)
+	}
+	if have := statedb.GetNonce(replaced); have != 5 {
+		t.Errorf("nonce mismatch: have %d, want 5", have)
+	}
+	if have := statedb.GetCode(replaced); !bytes.Equal(have, code) {
+		t.Errorf("code mismatch: have %x, want %x", have, code)
+	}
+	if have := statedb.GetBalance(replaced); have.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("balance mismatch: have %v, want 100", have)
+	}
+	// The replaced storage drops every other slot, the patched one retains them
+	if have := statedb.GetState(replaced, one); have != value {
+		t.Errorf("replaced slot mismatch: have %x, want %x", have, value)
+	}
+	if have := statedb.GetState(replaced, two); have != (common.Hash{}) {
+		t.Errorf("dropped slot mismatch: have %x, want empty", have)
+	}
+	if have := statedb.GetState(patched, one); have != value {
+		t.Errorf("patched slot mismatch: have %x, want %x", have, value)
+	}
+	if have := statedb.GetState(patched, two); have != two {
+		t.Errorf("retained slot mismatch: have %x, want %x", have, two)
+	}
+	// Writes to the replaced storage must be visible and revertible
+	snapshot := statedb.Snapshot()
+	statedb.SetState(replaced, two, value)
+	if have := statedb.GetState(replaced, two); have != value {
+		t.Errorf("written slot mismatch: have %x, want %x", have, value)
+	}
+	statedb.RevertToSnapshot(snapshot)
+	if have := statedb.GetState(replaced, two); have != (common.Hash{}) {
+		t.Errorf("reverted slot mismatch: have %x, want empty", have)
+	}
+	// Replacing and patching the same storage is rejected
+	invalid := StateOverride{
+		patched: {State: &map[common.Hash]common.Hash{}, StateDiff: &map[common.Hash]common.Hash{}},
+	}
+	if err := invalid.Apply(statedb); err == nil {
+		t.Errorf("conflicting storage overrides accepted")
+	}
+}
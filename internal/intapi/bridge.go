@@ -0,0 +1,292 @@
+package intapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/contracts/bridge"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/crypto"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"github.com/intfoundation/intchain/rpc"
+)
+
+// MintToken mints the tokens locked by the lock tx of the main chain bridge contract on the child chain
+func (api *PublicINTAPI) MintToken(ctx context.Context, from common.Address, chainId string, txHash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+	input, err := intAbi.ChainABI.Pack(intAbi.MintTokenInChildChain.String(), chainId, txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := intAbi.MintTokenInChildChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &intAbi.ChainContractMagicAddr,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+// BurnToken burns the child chain tokens, which can be unlocked on the main chain later
+func (api *PublicINTAPI) BurnToken(ctx context.Context, from common.Address, chainId string, token common.Address, amount *hexutil.Big, gasPrice *hexutil.Big) (common.Hash, error) {
+	input, err := intAbi.ChainABI.Pack(intAbi.BurnTokenInChildChain.String(), chainId, token, (*big.Int)(amount))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := intAbi.BurnTokenInChildChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &intAbi.ChainContractMagicAddr,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+// UnlockToken credits the tokens burned by the child chain burn tx to the main chain bridge contract
+func (api *PublicINTAPI) UnlockToken(ctx context.Context, from common.Address, chainId string, txHash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+	input, err := intAbi.ChainABI.Pack(intAbi.UnlockTokenInMainChain.String(), chainId, txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := intAbi.UnlockTokenInMainChain.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &intAbi.ChainContractMagicAddr,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+// GetTokenBalance returns the child chain token balance of the holder
+func (api *PublicINTAPI) GetTokenBalance(ctx context.Context, token, holder common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	state, _, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(bridge.TokenBalance(state, token, holder)), state.Error()
+}
+
+func init() {
+	// Mint Token
+	core.RegisterValidateCb(intAbi.MintTokenInChildChain, mintTokenValidateCb)
+	core.RegisterApplyCb(intAbi.MintTokenInChildChain, mintTokenApplyCb)
+
+	// Burn Token
+	core.RegisterValidateCb(intAbi.BurnTokenInChildChain, burnTokenValidateCb)
+	core.RegisterApplyCb(intAbi.BurnTokenInChildChain, burnTokenApplyCb)
+
+	// Unlock Token
+	core.RegisterValidateCb(intAbi.UnlockTokenInMainChain, unlockTokenValidateCb)
+	core.RegisterApplyCb(intAbi.UnlockTokenInMainChain, unlockTokenApplyCb)
+}
+
+func mintTokenValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, _, err := mintTokenValidation(from, tx, state, cch)
+	return err
+}
+
+func mintTokenApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, locks, err := mintTokenValidation(from, tx, state, cch)
+	if err != nil {
+		return err
+	}
+
+	for _, lock := range locks {
+		bridge.MintToken(state, lock.ChildToken, from, lock.Amount)
+		// mark the lock log as minted
+		bridge.MarkLockMinted(state, lock.ChildToken, args.TxHash, lock.logIndex)
+	}
+
+	return nil
+}
+
+// tokenLock is a TokenLocked log of the lock tx with its index in the receipt
+type tokenLock struct {
+	*bridge.TokenLocked
+	logIndex uint
+}
+
+func mintTokenValidation(from common.Address, tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) (*intAbi.MintTokenInChildChainArgs, []*tokenLock, error) {
+	var args intAbi.MintTokenInChildChainArgs
+	data := tx.Data()
+	if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.MintTokenInChildChain.String(), data[4:]); err != nil {
+		return nil, nil, err
+	}
+
+	if !isCurrentChain(tx, args.ChainId) {
+		return nil, nil, core.ErrInvalidBridgeChainId
+	}
+
+	bridgeAddr := cch.GetMainChainBridge()
+	if bridgeAddr == (common.Address{}) {
+		return nil, nil, core.ErrTokenBridgeDisabled
+	}
+
+	// the lock receipt has to be included by the canonical main chain
+	receipt := cch.GetReceiptFromMainChain(args.TxHash)
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, nil, core.ErrTokenLockNotFound
+	}
+
+	// every token locked for the sender and this chain by the lock tx is minted together
+	var locks []*tokenLock
+	for i, log := range receipt.Logs {
+		locked, err := bridge.ParseTokenLocked(bridgeAddr, log)
+		if err != nil {
+			continue
+		}
+		if locked.From != from || locked.ChainId != args.ChainId {
+			continue
+		}
+		if bridge.IsLockMinted(state, locked.ChildToken, args.TxHash, uint(i)) {
+			return nil, nil, core.ErrTokenAlreadyMinted
+		}
+		locks = append(locks, &tokenLock{locked, uint(i)})
+	}
+
+	if len(locks) == 0 {
+		return nil, nil, core.ErrTokenLockNotFound
+	}
+
+	return &args, locks, nil
+}
+
+func burnTokenValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, err := burnTokenValidation(from, tx, state)
+	return err
+}
+
+func burnTokenApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, err := burnTokenValidation(from, tx, state)
+	if err != nil {
+		return err
+	}
+
+	return bridge.BurnToken(state, args.Token, from, args.Amount)
+}
+
+func burnTokenValidation(from common.Address, tx *types.Transaction, state *state.StateDB) (*intAbi.BurnTokenInChildChainArgs, error) {
+	var args intAbi.BurnTokenInChildChainArgs
+	data := tx.Data()
+	if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.BurnTokenInChildChain.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if !isCurrentChain(tx, args.ChainId) {
+		return nil, core.ErrInvalidBridgeChainId
+	}
+
+	if args.Amount.Sign() <= 0 {
+		return nil, core.ErrInvalidTokenAmount
+	}
+
+	if bridge.TokenBalance(state, args.Token, from).Cmp(args.Amount) < 0 {
+		return nil, bridge.ErrInsufficientTokenBalance
+	}
+
+	return &args, nil
+}
+
+func unlockTokenValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, _, _, err := unlockTokenValidation(from, tx, state, cch)
+	return err
+}
+
+func unlockTokenApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, burnArgs, mainToken, err := unlockTokenValidation(from, tx, state, cch)
+	if err != nil {
+		return err
+	}
+
+	bridge.CreditWithdrawable(state, cch.GetMainChainBridge(), mainToken, from, burnArgs.Amount)
+	// mark the burn tx as unlocked
+	state.AddTX3(from, args.TxHash)
+
+	return nil
+}
+
+func unlockTokenValidation(from common.Address, tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) (*intAbi.UnlockTokenInMainChainArgs, *intAbi.BurnTokenInChildChainArgs, common.Address, error) {
+	var args intAbi.UnlockTokenInMainChainArgs
+	data := tx.Data()
+	if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.UnlockTokenInMainChain.String(), data[4:]); err != nil {
+		return nil, nil, common.Address{}, err
+	}
+
+	if state.HasTX3(from, args.TxHash) {
+		return nil, nil, common.Address{}, core.ErrTokenAlreadyUnlocked
+	}
+
+	bridgeAddr := cch.GetMainChainBridge()
+	if bridgeAddr == (common.Address{}) {
+		return nil, nil, common.Address{}, core.ErrTokenBridgeDisabled
+	}
+
+	// the burn tx (tx3) has to be proven by the child chain already, the block carries its proof
+	// data which the consensus validates and writes to the local cache before executing the block
+	tx3 := cch.GetTX3(args.ChainId, args.TxHash)
+	if tx3 == nil {
+		return nil, nil, common.Address{}, core.ErrTokenBurnNotFound
+	}
+
+	if derivedAddressFromTx(tx3) != from {
+		return nil, nil, common.Address{}, core.ErrTokenBurnNotFound
+	}
+
+	tx3Data := tx3.Data()
+	function, err := intAbi.FunctionTypeFromId(tx3Data[:4])
+	if err != nil || function != intAbi.BurnTokenInChildChain {
+		return nil, nil, common.Address{}, core.ErrTokenBurnNotFound
+	}
+
+	var burnArgs intAbi.BurnTokenInChildChainArgs
+	if err := intAbi.ChainABI.UnpackMethodInputs(&burnArgs, intAbi.BurnTokenInChildChain.String(), tx3Data[4:]); err != nil {
+		return nil, nil, common.Address{}, err
+	}
+
+	if burnArgs.ChainId != args.ChainId {
+		return nil, nil, common.Address{}, core.ErrInvalidBridgeChainId
+	}
+
+	mainToken := bridge.MainToken(state, bridgeAddr, args.ChainId, burnArgs.Token)
+	if mainToken == (common.Address{}) {
+		return nil, nil, common.Address{}, core.ErrTokenNotMapped
+	}
+
+	return &args, &burnArgs, mainToken, nil
+}
+
+// isCurrentChain checks the chain id in the tx args is the chain the tx signed for
+func isCurrentChain(tx *types.Transaction, chainId string) bool {
+	digest := crypto.Keccak256([]byte(chainId))
+	return tx.ChainId().Cmp(new(big.Int).SetBytes(digest)) == 0
+}
@@ -0,0 +1,81 @@
+package intapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+)
+
+// SetChildChainGenesis customizes the genesis of the pending child chain with the json genesis template,
+// it has to be sent by the owner of the child chain before the chain launches
+func (api *PublicINTAPI) SetChildChainGenesis(ctx context.Context, from common.Address, chainId string, genesisTemplate hexutil.Bytes, gasPrice *hexutil.Big) (common.Hash, error) {
+	input, err := intAbi.ChainABI.Pack(intAbi.SetChildChainGenesis.String(), chainId, []byte(genesisTemplate))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	defaultGas := intAbi.SetChildChainGenesis.RequiredGas()
+
+	args := SendTxArgs{
+		From:     from,
+		To:       &intAbi.ChainContractMagicAddr,
+		Gas:      (*hexutil.Uint64)(&defaultGas),
+		GasPrice: gasPrice,
+		Value:    nil,
+		Input:    (*hexutil.Bytes)(&input),
+		Nonce:    nil,
+	}
+
+	return SendTransaction(ctx, args, api.am, api.b, api.nonceLock)
+}
+
+func init() {
+	// Set Child Chain Genesis
+	core.RegisterValidateCb(intAbi.SetChildChainGenesis, setChildChainGenesisValidateCb)
+	core.RegisterApplyCb(intAbi.SetChildChainGenesis, setChildChainGenesisApplyCb)
+}
+
+func setChildChainGenesisValidateCb(tx *types.Transaction, state *state.StateDB, cch core.CrossChainHelper) error {
+	from := derivedAddressFromTx(tx)
+	_, err := setChildChainGenesisValidation(from, tx, cch)
+	return err
+}
+
+func setChildChainGenesisApplyCb(tx *types.Transaction, state *state.StateDB, ops *types.PendingOps, cch core.CrossChainHelper, mining bool) error {
+	from := derivedAddressFromTx(tx)
+	args, err := setChildChainGenesisValidation(from, tx, cch)
+	if err != nil {
+		return err
+	}
+
+	op := types.SetChildChainGenesisOp{
+		From:            from,
+		ChainId:         args.ChainId,
+		GenesisTemplate: args.GenesisTemplate,
+	}
+	if ok := ops.Append(&op); !ok {
+		return fmt.Errorf("pending ops conflict: %v", op)
+	}
+
+	return nil
+}
+
+func setChildChainGenesisValidation(from common.Address, tx *types.Transaction, cch core.CrossChainHelper) (*intAbi.SetChildChainGenesisArgs, error) {
+	var args intAbi.SetChildChainGenesisArgs
+	data := tx.Data()
+	if err := intAbi.ChainABI.UnpackMethodInputs(&args, intAbi.SetChildChainGenesis.String(), data[4:]); err != nil {
+		return nil, err
+	}
+
+	if err := cch.ValidateSetChildChainGenesis(from, args.ChainId, args.GenesisTemplate); err != nil {
+		return nil, err
+	}
+
+	return &args, nil
+}
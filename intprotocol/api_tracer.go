@@ -19,6 +19,7 @@ package intprotocol
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -55,9 +56,17 @@ const (
 // TraceConfig holds extra parameters to trace functions.
 type TraceConfig struct {
 	*vm.LogConfig
-	Tracer  *string
-	Timeout *string
-	Reexec  *uint64
+	Tracer       *string
+	TracerConfig json.RawMessage // Configuration of the native tracers
+	Timeout      *string
+	Reexec       *uint64
+}
+
+// TraceCallConfig holds extra parameters to the call trace function, the state
+// overrides are applied before the call is traced.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *intapi.StateOverride
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -203,8 +212,9 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
-					msg, _ := tx.AsMessage(signer)
+					msg, _ := tx.AsMessage(signer, task.block.BaseFee())
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain, nil)
+					vmctx.Staking = core.NewEVMStaking(api.eth.blockchain)
 
 					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 					if err != nil {
@@ -440,8 +450,9 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 
 			// Fetch and execute the next transaction trace tasks
 			for task := range jobs {
-				msg, _ := txs[task.index].AsMessage(signer)
+				msg, _ := txs[task.index].AsMessage(signer, block.BaseFee())
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
+				vmctx.Staking = core.NewEVMStaking(api.eth.blockchain)
 
 				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 				if err != nil {
@@ -459,8 +470,9 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
 
 		// Generate the next state snapshot fast without tracing
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
+		vmctx.Staking = core.NewEVMStaking(api.eth.blockchain)
 
 		vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{})
 		if _, _, err := core.ApplyMessageEx(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
@@ -680,6 +692,56 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
 
+// TraceCall returns the trace of the given call executed on top of the state of
+// the given block, with the state of any account optionally overridden. The
+// return value is tracer dependent, the same as for a transaction.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args intapi.CallArgs, number rpc.BlockNumber, config *TraceCallConfig) (interface{}, error) {
+	// Fetch the block and its state the call is executed on
+	var (
+		block   *types.Block
+		statedb *state.StateDB
+		err     error
+	)
+	switch number {
+	case rpc.PendingBlockNumber:
+		block, statedb = api.eth.miner.Pending()
+	case rpc.LatestBlockNumber:
+		block = api.eth.blockchain.CurrentBlock()
+	default:
+		block = api.eth.blockchain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	var traceConfig *TraceConfig
+	if config != nil {
+		traceConfig = &config.TraceConfig
+	}
+	if statedb == nil {
+		reexec := defaultTraceReexec
+		if traceConfig != nil && traceConfig.Reexec != nil {
+			reexec = *traceConfig.Reexec
+		}
+		if statedb, err = api.computeStateDB(block, reexec); err != nil {
+			return nil, err
+		}
+	}
+	// The state is a private copy, apply the overrides directly on it
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+	}
+	msg, err := args.ToMessage(block.BaseFee())
+	if err != nil {
+		return nil, err
+	}
+	vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
+	vmctx.Staking = core.NewEVMStaking(api.eth.blockchain)
+
+	return api.traceTx(ctx, msg, vmctx, statedb, traceConfig)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
@@ -698,15 +760,20 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer); err != nil {
+		// Constuct the native or JavaScript tracer to execute with
+		if tracers.IsNative(*config.Tracer) {
+			tracer, err = tracers.NewNative(*config.Tracer, config.TracerConfig)
+		} else {
+			tracer, err = tracers.New(*config.Tracer)
+		}
+		if err != nil {
 			return nil, err
 		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			tracer.(interface{ Stop(err error) }).Stop(errors.New("execution timeout"))
 		}()
 		defer cancel()
 
@@ -716,8 +783,12 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	default:
 		tracer = vm.NewStructLogger(config.LogConfig)
 	}
-	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+	// Run the transaction with tracing enabled. Calls without any fee run with a
+	// zero base fee, included transactions always pay it.
+	vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer, NoBaseFee: true})
+	if native, ok := tracer.(tracers.Native); ok {
+		native.CaptureTxStart(vmenv, message.From(), message.To(), message.Data())
+	}
 
 	result, _, err := core.ApplyMessageEx(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
 	if err != nil {
@@ -736,6 +807,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	case *tracers.Tracer:
 		return tracer.GetResult()
 
+	case tracers.Native:
+		return tracer.GetResult()
+
 	default:
 		panic(fmt.Sprintf("bad tracer type %T", tracer))
 	}
@@ -766,8 +840,9 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		context := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
+		context.Staking = core.NewEVMStaking(api.eth.blockchain)
 		if idx == txIndex {
 			return msg, context, statedb, nil
 		}
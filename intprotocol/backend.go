@@ -76,6 +76,8 @@ type IntChain struct {
 	chainDb intdb.Database // Block chain database
 	pruneDb intdb.Database // Prune data database
 
+	pruneProcessor *datareduction.PruneProcessor // Data reduction running in background
+
 	eventMux       *event.TypeMux
 	engine         consensus.IPBFT
 	accountManager *accounts.Manager
@@ -104,7 +106,11 @@ func New(ctx *node.ServiceContext, config *Config, cliCtx *cli.Context,
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
-	chainDb, err := ctx.OpenDatabase("chaindata", config.DatabaseCache, config.DatabaseHandles, "intchain/db/chaindata/")
+	// The freezer appends every block in order, the pruned block data would stop it
+	if config.DatabaseFreezer != "" && config.PruneBlockData {
+		return nil, errors.New("the freezer can't be combined with the block data pruning")
+	}
+	chainDb, err := ctx.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "intchain/db/chaindata/")
 	if err != nil {
 		return nil, err
 	}
@@ -169,6 +175,8 @@ func New(ctx *node.ServiceContext, config *Config, cliCtx *cli.Context,
 			TrieDirtyLimit:    config.TrieDirtyCache,
 			TrieDirtyDisabled: config.NoPruning,
 			TrieTimeLimit:     config.TrieTimeout,
+			SnapshotLimit:     config.SnapshotCache,
+			TxLookupLimit:     config.TxLookupLimit,
 		}
 	)
 	//eth.engine = CreateConsensusEngine(ctx, config, chainConfig, chainDb, cliCtx, cch)
@@ -194,7 +202,7 @@ func New(ctx *node.ServiceContext, config *Config, cliCtx *cli.Context,
 	if intChain.protocolManager, err = NewProtocolManager(intChain.chainConfig, config.SyncMode, config.NetworkId, intChain.eventMux, intChain.txPool, intChain.engine, intChain.blockchain, chainDb, cch); err != nil {
 		return nil, err
 	}
-	intChain.miner = miner.New(intChain, intChain.chainConfig, intChain.EventMux(), intChain.engine, config.MinerGasFloor, config.MinerGasCeil, cch)
+	intChain.miner = miner.New(intChain, intChain.chainConfig, intChain.EventMux(), intChain.engine, config.MinerGasFloor, config.MinerGasCeil, config.MinerSpecialGasReserve, cch)
 	intChain.miner.SetExtra(makeExtraData(config.ExtraData))
 
 	intChain.ApiBackend = &EthApiBackend{intChain, nil, cch}
@@ -425,6 +433,7 @@ func (s *IntChain) Start(srvr *p2p.Server) error {
 	maxPeers := srvr.MaxPeers
 
 	// Start the networking layer and the light server if requested
+	s.protocolManager.validators = srvr
 	s.protocolManager.Start(maxPeers)
 
 	// Start the Auto Mining Loop
@@ -432,7 +441,7 @@ func (s *IntChain) Start(srvr *p2p.Server) error {
 
 	// Start the Data Reduction
 	if s.config.PruneStateData && s.chainConfig.IntChainId == "child_0" {
-		go s.StartScanAndPrune(0)
+		s.StartScanAndPrune(0)
 	}
 
 	return nil
@@ -441,6 +450,12 @@ func (s *IntChain) Start(srvr *p2p.Server) error {
 // Stop implements node.Service, terminating all internal goroutines used by the
 // IntChain protocol.
 func (s *IntChain) Stop() error {
+	s.lock.Lock()
+	if s.pruneProcessor != nil {
+		s.pruneProcessor.Stop()
+	}
+	s.lock.Unlock()
+
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
@@ -497,6 +512,9 @@ func (s *IntChain) loopForMiningEvent() {
 	}
 }
 
+// StartScanAndPrune starts the data reduction in background, pruning the state up
+// to the given block number, or following the chain head if 0. The data reduction
+// resumes from the last scan/prune numbers committed.
 func (s *IntChain) StartScanAndPrune(blockNumber uint64) {
 
 	if datareduction.StartPruning() {
@@ -508,36 +526,21 @@ func (s *IntChain) StartScanAndPrune(blockNumber uint64) {
 
 	latestBlockNumber := s.blockchain.CurrentHeader().Number.Uint64()
 	if blockNumber == 0 || blockNumber >= latestBlockNumber {
-		blockNumber = latestBlockNumber
-		log.Infof("Data Reduction - Last block number %v", blockNumber)
+		blockNumber = 0
+		log.Info("Data Reduction - Follow the chain head", "number", latestBlockNumber)
 	} else {
 		log.Infof("Data Reduction - User defined Last block number %v", blockNumber)
 	}
 
-	ps := rawdb.ReadHeadScanNumber(s.pruneDb)
-	var scanNumber uint64
-	if ps != nil {
-		scanNumber = *ps
-	}
+	status := datareduction.GetLatestStatus(s.pruneDb)
+	log.Infof("Data Reduction - Last scan number %v, prune number %v", status.LatestScanNumber, status.LatestPruneNumber)
 
-	pp := rawdb.ReadHeadPruneNumber(s.pruneDb)
-	var pruneNumber uint64
-	if pp != nil {
-		pruneNumber = *pp
-	}
-	log.Infof("Data Reduction - Last scan number %v, prune number %v", scanNumber, pruneNumber)
-
-	pruneProcessor := datareduction.NewPruneProcessor(s.chainDb, s.pruneDb, s.blockchain, s.config.PruneBlockData)
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
-	lastScanNumber, lastPruneNumber := pruneProcessor.Process(blockNumber, scanNumber, pruneNumber)
-	log.Infof("Data Reduction - After prune, last number scan %v, prune number %v", lastScanNumber, lastPruneNumber)
-	if s.config.PruneBlockData {
-		for i := uint64(1); i < lastPruneNumber; i++ {
-			rawdb.DeleteBody(s.chainDb, rawdb.ReadCanonicalHash(s.chainDb, i), i)
-		}
-		log.Infof("deleted block from 1 to %v", lastPruneNumber)
+	if s.pruneProcessor != nil {
+		s.pruneProcessor.Stop()
 	}
-	log.Info("Data Reduction - Completed")
-
-	datareduction.StopPruning()
+	s.pruneProcessor = datareduction.NewPruneProcessor(s.chainDb, s.pruneDb, s.blockchain, s.config.PruneBlockData, blockNumber, s.config.PruneIOBudget)
+	s.pruneProcessor.Start()
 }
@@ -42,15 +42,20 @@ var DefaultConfig = Config{
 	TrieCleanCache: 256,
 	TrieDirtyCache: 256,
 	TrieTimeout:    60 * time.Minute,
+	SnapshotCache:  102,
 	MinerGasFloor:  100000000,
 	MinerGasCeil:   100000000,
 	MinerGasPrice:  big.NewInt(5000 * params.GWei),
 
+	MinerSpecialGasReserve: 10,
+
 	TxPool: core.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
 		Blocks:     20,
 		Percentile: 60,
 	},
+
+	PruneIOBudget: 20000,
 }
 
 func init() {
@@ -84,10 +89,14 @@ type Config struct {
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
+	DatabaseFreezer    string
 
 	TrieCleanCache int
 	TrieDirtyCache int
 	TrieTimeout    time.Duration
+	SnapshotCache  int // Megabytes of the state snapshot cache, 0 disables the snapshot
+
+	TxLookupLimit uint64 `toml:",omitempty"` // Number of recent blocks to retain the receipts and transaction indexes of, 0 retains all
 
 	// Mining-related options
 	Coinbase      common.Address `toml:",omitempty"`
@@ -96,6 +105,8 @@ type Config struct {
 	MinerGasCeil  uint64
 	MinerGasPrice *big.Int
 
+	MinerSpecialGasReserve uint64 // Percentage of the block gas reserved for the special IntChain transactions
+
 	// Solidity compiler path
 	SolcPath string
 
@@ -117,6 +128,7 @@ type Config struct {
 	// Data Reduction options
 	PruneStateData bool
 	PruneBlockData bool
+	PruneIOBudget  int // database operations per second of the data reduction, 0 for unlimited
 }
 
 type configMarshaling struct {
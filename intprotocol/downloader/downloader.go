@@ -119,6 +119,7 @@ type Downloader struct {
 	synchronising   int32
 	notified        int32
 	committed       int32
+	pivotLimit      uint64 // Fast sync pivot block pinned by the consensus engine (0 = no limit)
 
 	// Channels
 	headerCh      chan dataPack        // [intprotocol/62] Channel receiving inbound block headers
@@ -444,7 +445,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		if height <= uint64(fsMinFullBlocks) {
 			origin = 0
 		} else {
-			pivot = height - uint64(fsMinFullBlocks)
+			pivot = d.fastSyncPivot(height)
 			if pivot <= origin {
 				origin = pivot - 1
 			}
@@ -1353,6 +1354,23 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 	return nil
 }
 
+// SetPivotLimit pins the pivot block of the next fast sync cycles to the block
+// where the consensus engine can restore its own data, the blocks after it are
+// imported in full. Zero removes the limit.
+func (d *Downloader) SetPivotLimit(limit uint64) {
+	atomic.StoreUint64(&d.pivotLimit, limit)
+}
+
+// fastSyncPivot returns the pivot block for the given chain height, which must
+// be above fsMinFullBlocks. The pinned pivot is used even if it is closer to the
+// head than fsMinFullBlocks, the blocks are final once committed by IPBFT.
+func (d *Downloader) fastSyncPivot(height uint64) uint64 {
+	if limit := atomic.LoadUint64(&d.pivotLimit); limit != 0 && limit <= height {
+		return limit
+	}
+	return height - uint64(fsMinFullBlocks)
+}
+
 // processFastSyncContent takes fetch results from the queue and writes them to the
 // database. It also controls the synchronisation of state nodes of the pivot block.
 func (d *Downloader) processFastSyncContent(latest *types.Header) error {
@@ -1369,7 +1387,7 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 	// sync takes long enough for the chain head to move significantly.
 	pivot := uint64(0)
 	if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
-		pivot = height - uint64(fsMinFullBlocks)
+		pivot = d.fastSyncPivot(height)
 	}
 	// To cater for moving pivot points, track the pivot block and subsequently
 	// accumulated download results separatey.
@@ -1403,8 +1421,10 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 		if atomic.LoadInt32(&d.committed) == 0 {
 			latest = results[len(results)-1].Header
 			if height := latest.Number.Uint64(); height > pivot+2*uint64(fsMinFullBlocks) {
-				d.logger.Warn("Pivot became stale, moving", "old", pivot, "new", height-uint64(fsMinFullBlocks))
-				pivot = height - uint64(fsMinFullBlocks)
+				if newPivot := d.fastSyncPivot(height); newPivot != pivot {
+					d.logger.Warn("Pivot became stale, moving", "old", pivot, "new", newPivot)
+					pivot = newPivot
+				}
 			}
 		}
 		P, beforeP, afterP := splitAroundPivot(pivot, results)
@@ -0,0 +1,91 @@
+package downloader
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/trie"
+)
+
+// Tests that the IntChain specific sub tries and the non account entries of the
+// account trie are synced.
+func TestIntChainStateSync(t *testing.T) {
+	// Create a state with delegations, rewards and the refund set
+	srcDb := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(common.Hash{}, srcDb)
+
+	candidate := common.BytesToAddress([]byte{0x01})
+	statedb.AddBalance(candidate, big.NewInt(1))
+	for i := byte(0x10); i < 0x20; i++ {
+		user := common.BytesToAddress([]byte{i})
+		statedb.AddBalance(user, big.NewInt(int64(i)))
+		statedb.AddProxiedBalanceByUser(candidate, user, big.NewInt(int64(i)))
+		statedb.AddRewardBalanceByDelegateAddress(user, candidate, big.NewInt(int64(i)))
+		statedb.MarkAddressReward(user)
+	}
+	statedb.MarkDelegateAddressRefund(candidate)
+	srcRoot, _ := statedb.Commit(false)
+
+	// Sync the state into an empty database
+	dstDb := rawdb.NewMemoryDatabase()
+	sched := state.NewStateSync(srcRoot, dstDb)
+
+	queue := append([]common.Hash{}, sched.Missing(100)...)
+	for len(queue) > 0 {
+		results := make([]trie.SyncResult, len(queue))
+		for i, hash := range queue {
+			data, err := srcDb.TrieDB().Node(hash)
+			if err != nil {
+				t.Fatalf("failed to retrieve node data for %x", hash)
+			}
+			results[i] = trie.SyncResult{Hash: hash, Data: data}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			t.Fatalf("failed to process result #%d: %v", index, err)
+		}
+		if index, err := sched.Commit(dstDb); err != nil {
+			t.Fatalf("failed to commit data #%d: %v", index, err)
+		}
+		queue = append(queue[:0], sched.Missing(100)...)
+	}
+
+	// Cross check the synced sub tries
+	dst, err := state.New(srcRoot, state.NewDatabase(dstDb))
+	if err != nil {
+		t.Fatalf("failed to create state trie at %x: %v", srcRoot, err)
+	}
+	for i := byte(0x10); i < 0x20; i++ {
+		user := common.BytesToAddress([]byte{i})
+		if proxied := dst.GetProxiedBalanceByUser(candidate, user); proxied.Cmp(big.NewInt(int64(i))) != 0 {
+			t.Errorf("proxied balance of %x mismatch: have %v, want %v", user, proxied, i)
+		}
+		if reward := dst.GetRewardBalanceByDelegateAddress(user, candidate); reward.Cmp(big.NewInt(int64(i))) != 0 {
+			t.Errorf("reward balance of %x mismatch: have %v, want %v", user, reward, i)
+		}
+	}
+	if _, ok := dst.GetDelegateAddressRefundSet()[candidate]; !ok {
+		t.Errorf("delegate refund set not synced")
+	}
+}
+
+// Tests that the pivot pinned by the consensus engine is used while it is below
+// the chain height, even if it is closer to the head than fsMinFullBlocks.
+func TestFastSyncPivot(t *testing.T) {
+	d := &Downloader{}
+	height := uint64(fsMinFullBlocks) + 1000
+
+	if pivot := d.fastSyncPivot(height); pivot != height-uint64(fsMinFullBlocks) {
+		t.Errorf("unpinned pivot mismatch: have %d, want %d", pivot, height-uint64(fsMinFullBlocks))
+	}
+	d.SetPivotLimit(height - 1)
+	if pivot := d.fastSyncPivot(height); pivot != height-1 {
+		t.Errorf("pinned pivot mismatch: have %d, want %d", pivot, height-1)
+	}
+	d.SetPivotLimit(height + 1)
+	if pivot := d.fastSyncPivot(height); pivot != height-uint64(fsMinFullBlocks) {
+		t.Errorf("pivot above height used: have %d, want %d", pivot, height-uint64(fsMinFullBlocks))
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus/misc"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/rpc"
+)
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+)
+
+// maxFeeHistory is the maximum number of blocks that can be retrieved for a
+// fee history request.
+const maxFeeHistory = 1024
+
+// txGasAndReward is sorted in ascending order based on reward
+type txGasAndReward struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+type sortGasAndReward []txGasAndReward
+
+func (s sortGasAndReward) Len() int           { return len(s) }
+func (s sortGasAndReward) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sortGasAndReward) Less(i, j int) bool { return s[i].reward.Cmp(s[j].reward) < 0 }
+
+// processBlock takes a block and its receipts and returns the base fee, the
+// gas used ratio and the effective tips at the requested percentiles. Blocks
+// before the London fork report a zero base fee.
+func (gpo *Oracle) processBlock(block *types.Block, receipts types.Receipts, percentiles []float64) (*big.Int, float64, []*big.Int) {
+	baseFee := block.BaseFee()
+	if baseFee == nil {
+		baseFee = new(big.Int)
+	}
+	var gasUsedRatio float64
+	if block.GasLimit() > 0 {
+		gasUsedRatio = float64(block.GasUsed()) / float64(block.GasLimit())
+	}
+	if len(percentiles) == 0 {
+		return baseFee, gasUsedRatio, nil
+	}
+	reward := make([]*big.Int, len(percentiles))
+	if len(block.Transactions()) == 0 || len(receipts) != len(block.Transactions()) {
+		// Return an all zero row if there are no transactions to gather data from
+		for i := range reward {
+			reward[i] = new(big.Int)
+		}
+		return baseFee, gasUsedRatio, reward
+	}
+	sorter := make(sortGasAndReward, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		sorter[i] = txGasAndReward{gasUsed: receipts[i].GasUsed, reward: tx.EffectiveGasTipValue(block.BaseFee())}
+	}
+	sort.Sort(sorter)
+
+	var txIndex int
+	sumGasUsed := sorter[0].gasUsed
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(block.GasUsed()) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(sorter)-1 {
+			txIndex++
+			sumGasUsed += sorter[txIndex].gasUsed
+		}
+		reward[i] = sorter[txIndex].reward
+	}
+	return baseFee, gasUsedRatio, reward
+}
+
+// FeeHistory returns data relevant for fee estimation based on the specified
+// range of blocks, ending with lastBlock. The range is shortened if it would
+// extend before the genesis block. The following data is returned for each block:
+// - base fee per gas, with the base fee of the next block appended
+// - gas used ratio of the block
+// - the effective tips at the given percentiles, weighted by gas used
+func (gpo *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, percentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	if blocks < 1 {
+		return common.Big0, nil, nil, nil, nil
+	}
+	if blocks > maxFeeHistory {
+		blocks = maxFeeHistory
+	}
+	for i, p := range percentiles {
+		if p < 0 || p > 100 {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < percentiles[i-1] {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, percentiles[i-1], i, p)
+		}
+	}
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return common.Big0, nil, nil, nil, err
+	}
+	last := head.Number.Uint64()
+	switch {
+	case lastBlock == rpc.PendingBlockNumber || lastBlock == rpc.LatestBlockNumber:
+	case lastBlock < 0:
+		return common.Big0, nil, nil, nil, fmt.Errorf("%w: %d", errRequestBeyondHead, lastBlock)
+	case uint64(lastBlock) > last:
+		return common.Big0, nil, nil, nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, last)
+	default:
+		last = uint64(lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	oldest := last + 1 - uint64(blocks)
+
+	var (
+		reward       = make([][]*big.Int, 0, blocks)
+		baseFee      = make([]*big.Int, 0, blocks+1)
+		gasUsedRatio = make([]float64, 0, blocks)
+		lastBlockObj *types.Block
+	)
+	for number := oldest; number <= last; number++ {
+		block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if block == nil {
+			return common.Big0, nil, nil, nil, err
+		}
+		var receipts types.Receipts
+		if len(percentiles) != 0 {
+			if receipts, err = gpo.backend.GetReceipts(ctx, block.Hash()); err != nil {
+				return common.Big0, nil, nil, nil, err
+			}
+		}
+		blockBaseFee, ratio, blockReward := gpo.processBlock(block, receipts, percentiles)
+		baseFee = append(baseFee, blockBaseFee)
+		gasUsedRatio = append(gasUsedRatio, ratio)
+		if len(percentiles) != 0 {
+			reward = append(reward, blockReward)
+		}
+		lastBlockObj = block
+	}
+	// Append the base fee of the block following the range
+	nextBaseFee := new(big.Int)
+	if config := gpo.backend.ChainConfig(); config.IsLondon(new(big.Int).Add(lastBlockObj.Number(), big.NewInt(1))) {
+		nextBaseFee = misc.CalcBaseFee(config, lastBlockObj.Header())
+	}
+	baseFee = append(baseFee, nextBaseFee)
+
+	if len(percentiles) == 0 {
+		reward = nil
+	}
+	return new(big.Int).SetUint64(oldest), reward, baseFee, gasUsedRatio, nil
+}
@@ -24,11 +24,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		SkipBcVersionCheck      bool `toml:"-"`
 		DatabaseHandles         int  `toml:"-"`
 		DatabaseCache           int
+		DatabaseFreezer         string
 		Coinbase                common.Address `toml:",omitempty"`
 		ExtraData               hexutil.Bytes  `toml:",omitempty"`
 		MinerGasFloor           uint64
 		MinerGasCeil            uint64
 		MinerGasPrice           *big.Int
+		MinerSpecialGasReserve  uint64
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
@@ -41,11 +43,13 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
+	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.Coinbase = c.Coinbase
 	enc.ExtraData = c.ExtraData
 	enc.MinerGasFloor = c.MinerGasFloor
 	enc.MinerGasCeil = c.MinerGasCeil
 	enc.MinerGasPrice = c.MinerGasPrice
+	enc.MinerSpecialGasReserve = c.MinerSpecialGasReserve
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
@@ -63,11 +67,13 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		SkipBcVersionCheck      *bool `toml:"-"`
 		DatabaseHandles         *int  `toml:"-"`
 		DatabaseCache           *int
+		DatabaseFreezer         *string
 		Coinbase                *common.Address `toml:",omitempty"`
 		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
 		MinerGasFloor           *uint64
 		MinerGasCeil            *uint64
 		MinerGasPrice           *big.Int
+		MinerSpecialGasReserve  *uint64
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
@@ -96,6 +102,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseCache != nil {
 		c.DatabaseCache = *dec.DatabaseCache
 	}
+	if dec.DatabaseFreezer != nil {
+		c.DatabaseFreezer = *dec.DatabaseFreezer
+	}
 	if dec.Coinbase != nil {
 		c.Coinbase = *dec.Coinbase
 	}
@@ -111,6 +120,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.MinerGasPrice != nil {
 		c.MinerGasPrice = dec.MinerGasPrice
 	}
+	if dec.MinerSpecialGasReserve != nil {
+		c.MinerSpecialGasReserve = *dec.MinerSpecialGasReserve
+	}
 
 	if dec.TxPool != nil {
 		c.TxPool = *dec.TxPool
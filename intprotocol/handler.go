@@ -89,6 +89,9 @@ type ProtocolManager struct {
 	txCh     chan core.TxPreEvent
 	txSub    event.Subscription
 
+	privateTxCh chan struct{}  // Requests to relay the private transactions
+	validators  validatorNodes // Nodes of the validators receiving the private transactions
+
 	tx3PrfDtCh    chan core.Tx3ProofDataEvent
 	tx3PrfDtFeed  event.Feed
 	tx3PrfDtScope event.SubscriptionScope
@@ -102,6 +105,9 @@ type ProtocolManager struct {
 	quitSync    chan struct{}
 	noMorePeers chan struct{}
 
+	// channel for the epoch sync data requested before fast sync
+	epochSyncCh chan *epochSyncPack
+
 	// wait group is used for graceful shutdowns during downloading
 	// and processing
 	wg sync.WaitGroup
@@ -129,6 +135,8 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		noMorePeers:    make(chan struct{}),
 		txsyncCh:       make(chan *txsync),
 		quitSync:       make(chan struct{}),
+		epochSyncCh:    make(chan *epochSyncPack, 1),
+		privateTxCh:    make(chan struct{}, 1),
 		engine:         engine,
 		cch:            cch,
 		logger:         config.ChainLogger,
@@ -235,6 +243,7 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 	pm.txCh = make(chan core.TxPreEvent, txChanSize)
 	pm.txSub = pm.txpool.SubscribeTxPreEvent(pm.txCh)
 	go pm.txBroadcastLoop()
+	go pm.privateTxRelayLoop()
 
 	pm.tx3PrfDtCh = make(chan core.Tx3ProofDataEvent, tx3PrfDtChainSize)
 	pm.tx3PrfDtSub = pm.tx3PrfDtScope.Track(pm.tx3PrfDtFeed.Subscribe(pm.tx3PrfDtCh))
@@ -676,6 +685,28 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		pm.txpool.AddRemotes(txs)
 
+	case p.version >= consensus.Int66 && msg.Code == PrivateTxMsg:
+		// Private transactions are kept for the inclusion only, never propagated
+		if atomic.LoadUint32(&pm.acceptTxs) == 0 {
+			break
+		}
+		var txs []*types.Transaction
+		if err := msg.Decode(&txs); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		for i, tx := range txs {
+			if tx == nil {
+				return errResp(ErrDecode, "private transaction %d is nil", i)
+			}
+			p.MarkTransaction(tx.Hash())
+		}
+		// The expiry is decided by the local pool, not by the sender
+		for _, tx := range txs {
+			if err := pm.txpool.AddPrivate(tx, 0, false); err != nil {
+				pm.logger.Trace("Discarding private transaction", "hash", tx.Hash(), "peer", p.id, "err", err)
+			}
+		}
+
 	case msg.Code == TX3ProofDataMsg:
 		pm.logger.Debug("TX3ProofDataMsg received")
 		var proofDatas []*types.TX3ProofData
@@ -771,6 +802,25 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				pm.logger.Debugf("TrieNodeData %x already existed", thash)
 			}
 		}
+	case p.version >= consensus.Int65 && msg.Code == GetEpochSyncDataMsg:
+		fs, ok := pm.engine.(consensus.FastSyncer)
+		if !ok {
+			return nil
+		}
+		epochBytes, voteSetBytes := fs.EpochSyncData()
+		return p.SendEpochSyncData(&epochSyncData{Epoch: epochBytes, VoteSet: voteSetBytes})
+
+	case p.version >= consensus.Int65 && msg.Code == EpochSyncDataMsg:
+		var data epochSyncData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		// Drop the unrequested data
+		select {
+		case pm.epochSyncCh <- &epochSyncPack{peerId: p.id, data: &data}:
+		default:
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -907,7 +957,10 @@ func (self *ProtocolManager) txBroadcastLoop() {
 	for {
 		select {
 		case event := <-self.txCh:
-			self.BroadcastTx(event.Tx.Hash(), event.Tx)
+			// Private transactions are only relayed to the proposers
+			if !event.Private {
+				self.BroadcastTx(event.Tx.Hash(), event.Tx)
+			}
 
 		// Err() channel will be closed when unsubscribing.
 		case <-self.txSub.Err():
@@ -127,6 +127,21 @@ func (p *testTxPool) SubscribeTxPreEvent(ch chan<- core.TxPreEvent) event.Subscr
 	return p.txFeed.Subscribe(ch)
 }
 
+// AddPrivate appends the private transaction to the pool like a remote one.
+func (p *testTxPool) AddPrivate(tx *types.Transaction, maxBlocks uint64, relay bool) error {
+	return p.AddRemotes([]*types.Transaction{tx})[0]
+}
+
+// PrivateTxs returns no private transactions, the test pool doesn't track them.
+func (p *testTxPool) PrivateTxs() types.Transactions {
+	return nil
+}
+
+// IsPrivate reports no transaction as private.
+func (p *testTxPool) IsPrivate(hash common.Hash) bool {
+	return false
+}
+
 // newTestTransaction create a new dummy transaction.
 func newTestTransaction(from *ecdsa.PrivateKey, nonce uint64, datasize int) *types.Transaction {
 	tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 100000, big.NewInt(0), make([]byte, datasize))
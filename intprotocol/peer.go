@@ -190,6 +190,15 @@ func (p *peer) SendTransactions(txs types.Transactions) error {
 	return p2p.Send(p.rw, TxMsg, txs)
 }
 
+// SendPrivateTransactions sends private transactions to the peer and includes
+// the hashes in its transaction hash set for future reference.
+func (p *peer) SendPrivateTransactions(txs types.Transactions) error {
+	for _, tx := range txs {
+		p.knownTxs.Add(tx.Hash())
+	}
+	return p2p.Send(p.rw, PrivateTxMsg, txs)
+}
+
 // SendNewBlockHashes announces the availability of a number of blocks through
 // a hash notification.
 func (p *peer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
@@ -258,6 +267,11 @@ func (p *peer) SendTrieNodeData(data [][]byte) error {
 	return p2p.Send(p.rw, TrieNodeDataMsg, data)
 }
 
+// SendEpochSyncData sends the current epoch and its vote set to a fast syncing peer.
+func (p *peer) SendEpochSyncData(data *epochSyncData) error {
+	return p2p.Send(p.rw, EpochSyncDataMsg, data)
+}
+
 // RequestOneHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *peer) RequestOneHeader(hash common.Hash) error {
@@ -305,6 +319,12 @@ func (p *peer) RequestPreimages(hashes []common.Hash) error {
 	return p2p.Send(p.rw, GetPreImagesMsg, hashes)
 }
 
+// RequestEpochSyncData fetches the current epoch and its vote set from a remote node.
+func (p *peer) RequestEpochSyncData() error {
+	p.Log().Debug("Fetching epoch sync data")
+	return p2p.Send(p.rw, GetEpochSyncDataMsg, struct{}{})
+}
+
 // Handshake executes the intprotocol protocol handshake, negotiating version number,
 // network IDs, difficulties, head and genesis blocks.
 func (p *peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash) error {
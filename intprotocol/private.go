@@ -0,0 +1,103 @@
+package intprotocol
+
+import (
+	"fmt"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/p2p/discover"
+)
+
+const (
+	// privateTxRounds is the number of consensus rounds of the next block whose
+	// proposers receive the private transactions.
+	privateTxRounds = 3
+
+	// privateChainHeadChanSize is the size of channel listening to ChainHeadEvent
+	// for relaying the private transactions.
+	privateChainHeadChanSize = 10
+)
+
+// validatorNodes resolves the nodes of the validators announced through the
+// P2PValidatorNodeInfo messages.
+type validatorNodes interface {
+	// ValidatorNodes returns the known nodes of the given validators of the chain.
+	ValidatorNodes(chainId string, addresses []common.Address) []*discover.Node
+
+	// AddPeer connects to the given node and keeps the connection.
+	AddPeer(node *discover.Node)
+}
+
+// RelayPrivateTxs requests the private transactions of the pool to be sent to the
+// proposers of the next block, without waiting for a new chain head.
+func (pm *ProtocolManager) RelayPrivateTxs() {
+	select {
+	case pm.privateTxCh <- struct{}{}:
+	default:
+	}
+}
+
+// privateTxRelayLoop sends the private transactions of the pool to the proposers
+// of the next block whenever the chain head changes, until the transactions get
+// included or expire. Private transactions are never broadcast to the other peers.
+func (pm *ProtocolManager) privateTxRelayLoop() {
+	headCh := make(chan core.ChainHeadEvent, privateChainHeadChanSize)
+	headSub := pm.blockchain.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			pm.relayPrivateTxs(ev.Block.Header())
+		case <-pm.privateTxCh:
+			pm.relayPrivateTxs(pm.blockchain.CurrentHeader())
+
+		case <-headSub.Err():
+			return
+		case <-pm.quitSync:
+			return
+		}
+	}
+}
+
+// relayPrivateTxs sends the private transactions of the pool to the proposers of
+// the block on top of the given header. The proposers without a connection are
+// dialed, to be reachable for the following blocks.
+func (pm *ProtocolManager) relayPrivateTxs(header *types.Header) {
+	txs := pm.txpool.PrivateTxs()
+	if len(txs) == 0 || pm.validators == nil {
+		return
+	}
+	predictor, ok := pm.engine.(consensus.ProposerPredictor)
+	if !ok {
+		return
+	}
+	proposers := predictor.NextProposers(header, privateTxRounds)
+
+	recipients := 0
+	for _, node := range pm.validators.ValidatorNodes(pm.chainconfig.IntChainId, proposers) {
+		p := pm.peers.Peer(fmt.Sprintf("%x", node.ID[:8]))
+		if p == nil {
+			pm.logger.Debug("Dialing proposer for private transactions", "node", node.ID)
+			pm.validators.AddPeer(node)
+			continue
+		}
+		// The proposers of the older protocol versions can't receive private transactions
+		if p.version < consensus.Int66 {
+			continue
+		}
+		unknown := make(types.Transactions, 0, len(txs))
+		for _, tx := range txs {
+			if !p.knownTxs.Has(tx.Hash()) {
+				unknown = append(unknown, tx)
+			}
+		}
+		if len(unknown) > 0 {
+			p.SendPrivateTransactions(unknown)
+			recipients++
+		}
+	}
+	pm.logger.Trace("Relayed private transactions", "count", len(txs), "number", header.Number, "proposers", len(proposers), "recipients", recipients)
+}
@@ -70,6 +70,13 @@ const (
 	GetPreImagesMsg = 0x19
 	PreImagesMsg    = 0x1a
 	TrieNodeDataMsg = 0x1b
+
+	// Protocol messages belonging to the IPBFT protocol version 65
+	GetEpochSyncDataMsg = 0x1c
+	EpochSyncDataMsg    = 0x1d
+
+	// Protocol messages belonging to the IPBFT protocol version 66
+	PrivateTxMsg = 0x1e
 )
 
 type errCode int
@@ -116,6 +123,15 @@ type txPool interface {
 	// SubscribeTxPreEvent should return an event subscription of
 	// TxPreEvent and send events to the given channel.
 	SubscribeTxPreEvent(chan<- core.TxPreEvent) event.Subscription
+
+	// AddPrivate should add the given private transaction to the pool.
+	AddPrivate(tx *types.Transaction, maxBlocks uint64, relay bool) error
+
+	// PrivateTxs should return the private transactions to relay.
+	PrivateTxs() types.Transactions
+
+	// IsPrivate should report whether the transaction is a private one.
+	IsPrivate(hash common.Hash) bool
 }
 
 // statusData is the network packet for the status message.
@@ -191,3 +207,9 @@ type blockBody struct {
 
 // blockBodiesData is the network packet for block content distribution.
 type blockBodiesData []*blockBody
+
+// epochSyncData is the network packet for the consensus data needed by fast sync.
+type epochSyncData struct {
+	Epoch   []byte // Current epoch of the peer
+	VoteSet []byte // Validator vote set of the current epoch, not committed to the chain
+}
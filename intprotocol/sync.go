@@ -17,11 +17,13 @@
 package intprotocol
 
 import (
+	"errors"
 	"math/rand"
 	"sync/atomic"
 	"time"
 
 	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/consensus"
 	"github.com/intfoundation/intchain/core/types"
 	"github.com/intfoundation/intchain/intprotocol/downloader"
 	"github.com/intfoundation/intchain/log"
@@ -35,8 +37,21 @@ const (
 	// This is the target size for the packs of transactions sent by txsyncLoop.
 	// A pack can get larger than this if a single transactions exceeds this size.
 	txsyncPackSize = 100 * 1024
+
+	epochSyncTimeout = 10 * time.Second // Time allowance for a peer to reply the epoch sync data
+)
+
+var (
+	errEpochSyncTimeout     = errors.New("epoch sync data timeout")
+	errEpochSyncUnsupported = errors.New("epoch sync data not supported by peer")
 )
 
+// epochSyncPack is the epoch sync data received from a peer
+type epochSyncPack struct {
+	peerId string
+	data   *epochSyncData
+}
+
 type txsync struct {
 	p   *peer
 	txs []*types.Transaction
@@ -47,7 +62,11 @@ func (pm *ProtocolManager) syncTransactions(p *peer) {
 	var txs types.Transactions
 	pending, _ := pm.txpool.Pending()
 	for _, batch := range pending {
-		txs = append(txs, batch...)
+		for _, tx := range batch {
+			if !pm.txpool.IsPrivate(tx.Hash()) {
+				txs = append(txs, tx)
+			}
+		}
 	}
 	if len(txs) == 0 {
 		return
@@ -196,6 +215,18 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		}
 	}
 
+	// The consensus data out of the state must be restored at the pivot block
+	if fs, ok := pm.engine.(consensus.FastSyncer); ok && mode == downloader.FastSync {
+		pivot, err := pm.prepareFastSync(fs, peer)
+		if err != nil {
+			pm.logger.Warn("Fast sync unavailable, sync in full", "peer", peer.id, "err", err)
+			mode = downloader.FullSync
+		} else {
+			pm.downloader.SetPivotLimit(pivot)
+			defer fs.FinishFastSync()
+		}
+	}
+
 	// Run the sync cycle, and disable fast sync if we've went past the pivot block
 	if err := pm.downloader.Synchronise(peer.id, pHead, pTd, mode); err != nil {
 		return
@@ -215,3 +246,35 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		go pm.BroadcastBlock(head, false)
 	}
 }
+
+// prepareFastSync requests the epoch sync data from the peer and passes it to the
+// consensus engine, which returns the highest pivot block of the fast sync.
+func (pm *ProtocolManager) prepareFastSync(fs consensus.FastSyncer, peer *peer) (uint64, error) {
+	if peer.version < consensus.Int65 {
+		return 0, errEpochSyncUnsupported
+	}
+	// Drop the stale data of the previous request
+	select {
+	case <-pm.epochSyncCh:
+	default:
+	}
+	if err := peer.RequestEpochSyncData(); err != nil {
+		return 0, err
+	}
+
+	timeout := time.NewTimer(epochSyncTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case pack := <-pm.epochSyncCh:
+			if pack.peerId != peer.id {
+				continue
+			}
+			return fs.PrepareFastSync(pack.data.Epoch, pack.data.VoteSet)
+		case <-timeout.C:
+			return 0, errEpochSyncTimeout
+		case <-pm.quitSync:
+			return 0, errEpochSyncTimeout
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/vm"
+)
+
+// errNativeTracerNotFound is returned if no Go tracer is registered with the requested name.
+var errNativeTracerNotFound = errors.New("native tracer not found")
+
+// Native is a transaction tracer implemented in Go. The native tracers are much
+// faster than the JavaScript ones and are selected by name the same way.
+type Native interface {
+	vm.Tracer
+
+	// CaptureTxStart is invoked with the EVM before the message is applied on its
+	// state, so the tracer can look up the state preceding the transaction.
+	CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, input []byte)
+
+	// GetResult returns the json encoded result of the tracing.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates the tracing at the first opportunity.
+	Stop(err error)
+}
+
+// natives contains the built in Go tracers by name.
+var natives = map[string]func(config json.RawMessage) (Native, error){
+	"nativeCallTracer":     newCallTracer,
+	"nativePrestateTracer": newPrestateTracer,
+}
+
+// IsNative reports whether a Go tracer is registered with the given name.
+func IsNative(name string) bool {
+	_, ok := natives[name]
+	return ok
+}
+
+// NewNative creates the Go tracer registered with the given name, configured by
+// the tracer specific json encoded config.
+func NewNative(name string, config json.RawMessage) (Native, error) {
+	ctor, ok := natives[name]
+	if !ok {
+		return nil, errNativeTracerNotFound
+	}
+	return ctor(config)
+}
+
+// memorySlice copies the requested segment of the memory, or returns nil if the
+// segment is out of the bounds of the memory.
+func memorySlice(memory *vm.Memory, offset, size *big.Int) []byte {
+	if !offset.IsUint64() || !size.IsUint64() || size.Sign() == 0 {
+		return nil
+	}
+	start, end := offset.Uint64(), offset.Uint64()+size.Uint64()
+	if end < start || end > uint64(memory.Len()) {
+		return nil
+	}
+	return common.CopyBytes(memory.Data()[start:end])
+}
+
+// stackUint64 returns the stack item as uint64, or the maximum uint64 value if it
+// doesn't fit.
+func stackUint64(value *big.Int) uint64 {
+	if !value.IsUint64() {
+		return ^uint64(0)
+	}
+	return value.Uint64()
+}
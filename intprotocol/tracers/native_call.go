@@ -0,0 +1,247 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/core/vm"
+)
+
+// callFrame is a single call of the call tracer result.
+type callFrame struct {
+	Type    string          `json:"type"`
+	From    *common.Address `json:"from,omitempty"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     *hexutil.Uint64 `json:"gas,omitempty"`
+	GasUsed *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	Input   hexutil.Bytes   `json:"input,omitempty"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Time    string          `json:"time,omitempty"`
+	Calls   []*callFrame    `json:"calls,omitempty"`
+
+	gasIn   uint64 // Gas available before the call opcode
+	gasCost uint64 // Cost of the call opcode
+	outOff  uint64 // Memory offset of the call output
+	outLen  uint64 // Memory size of the call output
+}
+
+// callTracer is the native version of the JavaScript callTracer, it reports all
+// the internal calls made by a transaction.
+type callTracer struct {
+	callstack []*callFrame // Current recursive call stack of the EVM execution
+	descended bool         // Whether an inner call was just entered
+
+	root *callFrame // Top level call, filled from the start and end events
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newCallTracer creates a native call tracer, it has no configuration.
+func newCallTracer(config json.RawMessage) (Native, error) {
+	return &callTracer{
+		callstack: []*callFrame{{}},
+		root:      &callFrame{},
+	}, nil
+}
+
+// CaptureTxStart implements the Native interface, the call tracer doesn't need
+// the state preceding the transaction.
+func (t *callTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, input []byte) {
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *callTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.root.Type = "CALL"
+	if create {
+		t.root.Type = "CREATE"
+	}
+	t.root.From = &from
+	t.root.To = &to
+	t.root.Input = common.CopyBytes(input)
+	t.root.Gas = (*hexutil.Uint64)(&gas)
+	t.root.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *callTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	// Capture any errors immediately
+	if err != nil {
+		return t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return nil
+	}
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		// If a new contract is being created, add to the call stack
+		from := contract.Address()
+		t.callstack = append(t.callstack, &callFrame{
+			Type:    op.String(),
+			From:    &from,
+			Input:   memorySlice(memory, stack.Back(1), stack.Back(2)),
+			Value:   (*hexutil.Big)(new(big.Int).Set(stack.Back(0))),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+		t.descended = true
+		return nil
+
+	case vm.SELFDESTRUCT:
+		// If a contract is being self destructed, gather that as a subcall too
+		from, to := contract.Address(), common.BigToAddress(stack.Back(0))
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, &callFrame{
+			Type:  op.String(),
+			From:  &from,
+			To:    &to,
+			Value: (*hexutil.Big)(env.StateDB.GetBalance(from)),
+		})
+		return nil
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// Skip any pre-compile invocations, those are just fancy opcodes. The IntChain
+		// native contracts are reported as calls.
+		to := common.BigToAddress(stack.Back(1))
+		if _, ok := vm.PrecompiledContractsIstanbul[to]; ok {
+			return nil
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		from := contract.Address()
+		call := &callFrame{
+			Type:    op.String(),
+			From:    &from,
+			To:      &to,
+			Input:   memorySlice(memory, stack.Back(2+off), stack.Back(3+off)),
+			gasIn:   gas,
+			gasCost: cost,
+			outOff:  stackUint64(stack.Back(4 + off)),
+			outLen:  stackUint64(stack.Back(5 + off)),
+		}
+		if off == 1 {
+			call.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(2)))
+		}
+		t.callstack = append(t.callstack, call)
+		t.descended = true
+		return nil
+	}
+	// If we've just descended into an inner call, retrieve its true allowance. It
+	// has to be extracted from within the call as there may be funky gas dynamics
+	// with regard to requested and actually given gas (2300 stipend, 63/64 rule).
+	// Calls to plain accounts don't step into the call, their gas is unknown.
+	if t.descended {
+		if depth >= len(t.callstack) {
+			allowance := gas
+			t.callstack[len(t.callstack)-1].Gas = (*hexutil.Uint64)(&allowance)
+		}
+		t.descended = false
+	}
+	// If an existing call is returning, pop off the call stack
+	if op == vm.REVERT {
+		t.callstack[len(t.callstack)-1].Error = "execution reverted"
+		return nil
+	}
+	if depth == len(t.callstack)-1 {
+		// Pop off the last call and get the execution results
+		call := t.callstack[len(t.callstack)-1]
+		t.callstack = t.callstack[:len(t.callstack)-1]
+
+		ret := stack.Back(0)
+		if call.Type == vm.CREATE.String() || call.Type == vm.CREATE2.String() {
+			// If the call was a CREATE, retrieve the contract address and output code
+			gasUsed := call.gasIn - call.gasCost - gas
+			call.GasUsed = (*hexutil.Uint64)(&gasUsed)
+
+			if ret.Sign() != 0 {
+				to := common.BigToAddress(ret)
+				call.To = &to
+				call.Output = env.StateDB.GetCode(to)
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		} else if call.Gas != nil {
+			// If the call was a contract call, retrieve the gas usage and output
+			gasUsed := call.gasIn - call.gasCost + uint64(*call.Gas) - gas
+			call.GasUsed = (*hexutil.Uint64)(&gasUsed)
+
+			if ret.Sign() != 0 {
+				call.Output = memorySlice(memory, new(big.Int).SetUint64(call.outOff), new(big.Int).SetUint64(call.outLen))
+			} else if call.Error == "" {
+				call.Error = "internal failure"
+			}
+		}
+		// Inject the call into the previous one
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, call)
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *callTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	// If the topmost call already reverted, don't handle the additional fault again
+	if t.callstack[len(t.callstack)-1].Error != "" {
+		return nil
+	}
+	// Pop off the just failed call, consuming all its gas
+	call := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+
+	call.Error = err.Error()
+	if call.Gas != nil {
+		gasUsed := uint64(*call.Gas)
+		call.GasUsed = (*hexutil.Uint64)(&gasUsed)
+	}
+	// Flatten the failed call into its parent, unless it was the last call
+	if len(t.callstack) > 0 {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.Calls = append(parent.Calls, call)
+		return nil
+	}
+	t.callstack = append(t.callstack, call)
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.root.Output = common.CopyBytes(output)
+	t.root.GasUsed = (*hexutil.Uint64)(&gasUsed)
+	t.root.Time = d.String()
+	if err != nil {
+		t.root.Error = err.Error()
+	}
+	return nil
+}
+
+// GetResult returns the json encoded call tree of the transaction.
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil && atomic.LoadUint32(&t.interrupt) > 0 {
+		return nil, t.reason
+	}
+	result := *t.root
+	result.Calls = t.callstack[0].Calls
+	if t.callstack[0].Error != "" {
+		result.Error = t.callstack[0].Error
+	}
+	if result.Error != "" {
+		result.Output = nil
+	}
+	return json.Marshal(&result)
+}
+
+// Stop terminates the tracing at the first opportunity.
+func (t *callTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
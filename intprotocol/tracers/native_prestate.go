@@ -0,0 +1,348 @@
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/core/vm"
+	"github.com/intfoundation/intchain/crypto"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+)
+
+// intchainBalances is implemented by the state database to look up the IntChain
+// specific balances of an account.
+type intchainBalances interface {
+	GetDepositBalance(addr common.Address) *big.Int
+	GetDelegateBalance(addr common.Address) *big.Int
+	GetTotalProxiedBalance(addr common.Address) *big.Int
+	GetTotalDepositProxiedBalance(addr common.Address) *big.Int
+	GetTotalPendingRefundBalance(addr common.Address) *big.Int
+	GetTotalRewardBalance(addr common.Address) *big.Int
+}
+
+// prestateAccount is the state of a single account reported by the prestate tracer.
+type prestateAccount struct {
+	Balance               *hexutil.Big                `json:"balance,omitempty"`
+	Nonce                 uint64                      `json:"nonce,omitempty"`
+	Code                  hexutil.Bytes               `json:"code,omitempty"`
+	Storage               map[common.Hash]common.Hash `json:"storage,omitempty"`
+	DepositBalance        *hexutil.Big                `json:"depositBalance,omitempty"`
+	DelegateBalance       *hexutil.Big                `json:"delegateBalance,omitempty"`
+	ProxiedBalance        *hexutil.Big                `json:"proxiedBalance,omitempty"`
+	DepositProxiedBalance *hexutil.Big                `json:"depositProxiedBalance,omitempty"`
+	PendingRefundBalance  *hexutil.Big                `json:"pendingRefundBalance,omitempty"`
+	RewardBalance         *hexutil.Big                `json:"rewardBalance,omitempty"`
+}
+
+// prestateTracerConfig is the configuration of the prestate tracer.
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, report the pre and post state of the modified accounts
+}
+
+// prestateTracer is the native version of the JavaScript prestateTracer, it
+// reports the state of every account touched by a transaction before it ran. In
+// diff mode the state after the transaction is reported too, limited to what
+// was actually modified.
+type prestateTracer struct {
+	config prestateTracerConfig
+	env    *vm.EVM
+
+	pre     map[common.Address]*prestateAccount
+	created map[common.Address]bool // Accounts that didn't exist before the transaction
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newPrestateTracer creates a native prestate tracer, optionally in diff mode.
+func newPrestateTracer(config json.RawMessage) (Native, error) {
+	var cfg prestateTracerConfig
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	return &prestateTracer{
+		config:  cfg,
+		pre:     make(map[common.Address]*prestateAccount),
+		created: make(map[common.Address]bool),
+	}, nil
+}
+
+// CaptureTxStart implements the Native interface to record the accounts touched
+// by the transaction itself before any state is modified.
+func (t *prestateTracer) CaptureTxStart(env *vm.EVM, from common.Address, to *common.Address, input []byte) {
+	t.env = env
+
+	t.lookupAccount(from)
+	t.lookupAccount(env.Coinbase)
+	if to != nil {
+		t.lookupAccount(*to)
+
+		// The special transactions are applied without the EVM
+		if *to == intAbi.ChainContractMagicAddr {
+			t.lookupChainCandidate(input)
+		}
+	} else {
+		t.lookupAccount(crypto.CreateAddress(from, env.StateDB.GetNonce(from)))
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	if to == intAbi.StakingContractAddr {
+		t.lookupStakingCandidate(input)
+	}
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil || atomic.LoadUint32(&t.interrupt) > 0 {
+		return nil
+	}
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.EXTCODEHASH, vm.BALANCE:
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		to := common.BigToAddress(stack.Back(1))
+		t.lookupAccount(to)
+
+		// The staking contract modifies the candidate passed as the first argument
+		if to == intAbi.StakingContractAddr {
+			off := 1
+			if op == vm.DELEGATECALL || op == vm.STATICCALL {
+				off = 0
+			}
+			t.lookupStakingCandidate(memorySlice(memory, stack.Back(2+off), stack.Back(3+off)))
+		}
+
+	case vm.CREATE:
+		from := contract.Address()
+		t.lookupAccount(crypto.CreateAddress(from, env.StateDB.GetNonce(from)))
+
+	case vm.CREATE2:
+		code := memorySlice(memory, stack.Back(1), stack.Back(2))
+		salt := common.BigToHash(stack.Back(3))
+		t.lookupAccount(crypto.CreateAddress2(contract.Address(), salt, crypto.Keccak256(code)))
+
+	case vm.SELFDESTRUCT:
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(contract.Address(), common.BigToHash(stack.Back(0)))
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface, faults don't touch any new state.
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface, the post state is only retrieved
+// with the result, after the transaction fees have been settled.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the json encoded state of the touched accounts, or their pre
+// and post state in diff mode.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	if t.reason != nil && atomic.LoadUint32(&t.interrupt) > 0 {
+		return nil, t.reason
+	}
+	if !t.config.DiffMode {
+		pre := make(map[common.Address]*prestateAccount)
+		for addr, account := range t.pre {
+			if !t.created[addr] {
+				pre[addr] = account
+			}
+		}
+		return json.Marshal(pre)
+	}
+	pre, post := t.diff()
+	return json.Marshal(struct {
+		Pre  map[common.Address]*prestateAccount `json:"pre"`
+		Post map[common.Address]*prestateAccount `json:"post"`
+	}{pre, post})
+}
+
+// Stop terminates the tracing at the first opportunity.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// diff compares the recorded state with the current one, retaining only the
+// modified fields and storage slots of the modified accounts.
+func (t *prestateTracer) diff() (map[common.Address]*prestateAccount, map[common.Address]*prestateAccount) {
+	pre := make(map[common.Address]*prestateAccount)
+	post := make(map[common.Address]*prestateAccount)
+
+	for addr, prev := range t.pre {
+		// Deleted accounts only show up in the pre state
+		if t.env.StateDB.HasSuicided(addr) || !t.env.StateDB.Exist(addr) {
+			if !t.created[addr] {
+				pre[addr] = prev
+			}
+			continue
+		}
+		curr := t.account(addr)
+		before, after := &prestateAccount{}, &prestateAccount{}
+		modified := false
+
+		if diffBig(prev.Balance, curr.Balance) {
+			before.Balance, after.Balance, modified = prev.Balance, curr.Balance, true
+		}
+		if prev.Nonce != curr.Nonce {
+			before.Nonce, after.Nonce, modified = prev.Nonce, curr.Nonce, true
+		}
+		if !bytes.Equal(prev.Code, curr.Code) {
+			before.Code, after.Code, modified = prev.Code, curr.Code, true
+		}
+		if diffBig(prev.DepositBalance, curr.DepositBalance) {
+			before.DepositBalance, after.DepositBalance, modified = prev.DepositBalance, curr.DepositBalance, true
+		}
+		if diffBig(prev.DelegateBalance, curr.DelegateBalance) {
+			before.DelegateBalance, after.DelegateBalance, modified = prev.DelegateBalance, curr.DelegateBalance, true
+		}
+		if diffBig(prev.ProxiedBalance, curr.ProxiedBalance) {
+			before.ProxiedBalance, after.ProxiedBalance, modified = prev.ProxiedBalance, curr.ProxiedBalance, true
+		}
+		if diffBig(prev.DepositProxiedBalance, curr.DepositProxiedBalance) {
+			before.DepositProxiedBalance, after.DepositProxiedBalance, modified = prev.DepositProxiedBalance, curr.DepositProxiedBalance, true
+		}
+		if diffBig(prev.PendingRefundBalance, curr.PendingRefundBalance) {
+			before.PendingRefundBalance, after.PendingRefundBalance, modified = prev.PendingRefundBalance, curr.PendingRefundBalance, true
+		}
+		if diffBig(prev.RewardBalance, curr.RewardBalance) {
+			before.RewardBalance, after.RewardBalance, modified = prev.RewardBalance, curr.RewardBalance, true
+		}
+		for key, value := range prev.Storage {
+			if current := t.env.StateDB.GetState(addr, key); current != value {
+				if before.Storage == nil {
+					before.Storage = make(map[common.Hash]common.Hash)
+					after.Storage = make(map[common.Hash]common.Hash)
+				}
+				before.Storage[key], after.Storage[key], modified = value, current, true
+			}
+		}
+		if !modified {
+			continue
+		}
+		if !t.created[addr] {
+			pre[addr] = before
+		}
+		post[addr] = after
+	}
+	return pre, post
+}
+
+// lookupAccount records the current state of the account, unless it's already
+// known.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.pre[addr]; ok {
+		return
+	}
+	if !t.env.StateDB.Exist(addr) {
+		t.created[addr] = true
+	}
+	t.pre[addr] = t.account(addr)
+}
+
+// lookupStorage records the current value of the storage slot, unless it's
+// already known.
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+
+	account := t.pre[addr]
+	if account.Storage == nil {
+		account.Storage = make(map[common.Hash]common.Hash)
+	}
+	if _, ok := account.Storage[key]; ok {
+		return
+	}
+	account.Storage[key] = t.env.StateDB.GetState(addr, key)
+}
+
+// lookupStakingCandidate records the candidate account of a staking contract
+// call, passed as the first argument after the method id.
+func (t *prestateTracer) lookupStakingCandidate(input []byte) {
+	if len(input) < 36 {
+		return
+	}
+	t.lookupAccount(common.BytesToAddress(input[16:36]))
+}
+
+// lookupChainCandidate records the candidate account of a special transaction
+// sent to the chain contract.
+func (t *prestateTracer) lookupChainCandidate(input []byte) {
+	if len(input) < 4 {
+		return
+	}
+	function, err := intAbi.FunctionTypeFromId(input[:4])
+	if err != nil {
+		return
+	}
+	switch function {
+	case intAbi.Delegate:
+		var args intAbi.DelegateArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, function.String(), input[4:]); err == nil {
+			t.lookupAccount(args.Candidate)
+		}
+	case intAbi.UnDelegate:
+		var args intAbi.UnDelegateArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, function.String(), input[4:]); err == nil {
+			t.lookupAccount(args.Candidate)
+		}
+	case intAbi.WithdrawReward:
+		var args intAbi.WithdrawRewardArgs
+		if err := intAbi.ChainABI.UnpackMethodInputs(&args, function.String(), input[4:]); err == nil {
+			t.lookupAccount(args.DelegateAddress)
+		}
+	}
+}
+
+// account retrieves the current state of the account, without storage.
+func (t *prestateTracer) account(addr common.Address) *prestateAccount {
+	db := t.env.StateDB
+
+	account := &prestateAccount{
+		Balance: (*hexutil.Big)(new(big.Int).Set(db.GetBalance(addr))),
+		Nonce:   db.GetNonce(addr),
+		Code:    common.CopyBytes(db.GetCode(addr)),
+	}
+	if balances, ok := db.(intchainBalances); ok {
+		account.DepositBalance = nonZeroBig(balances.GetDepositBalance(addr))
+		account.DelegateBalance = nonZeroBig(balances.GetDelegateBalance(addr))
+		account.ProxiedBalance = nonZeroBig(balances.GetTotalProxiedBalance(addr))
+		account.DepositProxiedBalance = nonZeroBig(balances.GetTotalDepositProxiedBalance(addr))
+		account.PendingRefundBalance = nonZeroBig(balances.GetTotalPendingRefundBalance(addr))
+		account.RewardBalance = nonZeroBig(balances.GetTotalRewardBalance(addr))
+	}
+	return account
+}
+
+// nonZeroBig copies the value, or returns nil for a zero value to omit it from
+// the result.
+func nonZeroBig(value *big.Int) *hexutil.Big {
+	if value == nil || value.Sign() == 0 {
+		return nil
+	}
+	return (*hexutil.Big)(new(big.Int).Set(value))
+}
+
+// diffBig reports whether the two optional values differ.
+func diffBig(a, b *hexutil.Big) bool {
+	if a == nil || b == nil {
+		return a != b
+	}
+	return a.ToInt().Cmp(b.ToInt()) != 0
+}
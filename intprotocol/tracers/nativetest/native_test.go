@@ -0,0 +1,248 @@
+// Package nativetest runs the native tracers against the tracer test harness,
+// the tests of the tracers package depend on the state tests.
+package nativetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/common/hexutil"
+	"github.com/intfoundation/intchain/common/math"
+	"github.com/intfoundation/intchain/core"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+	"github.com/intfoundation/intchain/core/vm"
+	intAbi "github.com/intfoundation/intchain/intabi/abi"
+	"github.com/intfoundation/intchain/intprotocol/tracers"
+	"github.com/intfoundation/intchain/params"
+	"github.com/intfoundation/intchain/rlp"
+)
+
+// callTrace is the result of a callTracer run.
+type callTrace struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      common.Address  `json:"to"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output"`
+	Gas     *hexutil.Uint64 `json:"gas,omitempty"`
+	GasUsed *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []callTrace     `json:"calls,omitempty"`
+}
+
+// prestateAccount is the state of an account reported by the prestate tracer.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+type callContext struct {
+	Number     math.HexOrDecimal64   `json:"number"`
+	Difficulty *math.HexOrDecimal256 `json:"difficulty"`
+	Time       math.HexOrDecimal64   `json:"timestamp"`
+	GasLimit   math.HexOrDecimal64   `json:"gasLimit"`
+	Miner      common.Address        `json:"miner"`
+}
+
+// callTracerTest defines a single test to check the call tracer against.
+type callTracerTest struct {
+	Genesis *core.Genesis `json:"genesis"`
+	Context *callContext  `json:"context"`
+	Input   string        `json:"input"`
+	Result  *callTrace    `json:"result"`
+}
+
+// makePreState creates the state of the genesis accounts.
+func makePreState(accounts core.GenesisAlloc) *state.StateDB {
+	sdb := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(common.Hash{}, sdb)
+	for addr, a := range accounts {
+		statedb.SetCode(addr, a.Code)
+		statedb.SetNonce(addr, a.Nonce)
+		statedb.SetBalance(addr, a.Balance)
+		for k, v := range a.Storage {
+			statedb.SetState(addr, k, v)
+		}
+	}
+	// Commit and re-open to start with a clean state.
+	root, _ := statedb.Commit(false)
+	statedb, _ = state.New(root, sdb)
+	return statedb
+}
+
+// runNative runs the transaction of the test with the native tracer, returning
+// the json encoded result.
+func runNative(t *testing.T, test *callTracerTest, name string) json.RawMessage {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
+		t.Fatalf("failed to parse testcase input: %v", err)
+	}
+	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
+	origin, _ := signer.Sender(tx)
+
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Origin:      origin,
+		Coinbase:    test.Context.Miner,
+		BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
+		Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
+		Difficulty:  (*big.Int)(test.Context.Difficulty),
+		GasLimit:    uint64(test.Context.GasLimit),
+		GasPrice:    tx.GasPrice(),
+	}
+	statedb := makePreState(test.Genesis.Alloc)
+
+	tracer, err := tracers.NewNative(name, nil)
+	if err != nil {
+		t.Fatalf("failed to create tracer: %v", err)
+	}
+	evm := vm.NewEVM(context, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+
+	msg, err := tx.AsMessage(signer, nil)
+	if err == types.ErrInvalidSigner {
+		t.Skip("unprotected transactions are rejected by the signer")
+	}
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	tracer.CaptureTxStart(evm, msg.From(), msg.To(), msg.Data())
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+	if _, err = st.TransitionDb(); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	return res
+}
+
+// forEachTest runs the function against every call tracer test.
+func forEachTest(t *testing.T, run func(t *testing.T, test *callTracerTest)) {
+	dir := filepath.Join("..", "testdata")
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to retrieve tracer test suite: %v", err)
+	}
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), "call_tracer_") {
+			continue
+		}
+		file := file // capture range variable
+		t.Run(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "call_tracer_"), ".json"), func(t *testing.T) {
+			t.Parallel()
+
+			blob, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				t.Fatalf("failed to read testcase: %v", err)
+			}
+			test := new(callTracerTest)
+			if err := json.Unmarshal(blob, test); err != nil {
+				t.Fatalf("failed to parse testcase: %v", err)
+			}
+			run(t, test)
+		})
+	}
+}
+
+// Tests that the native call tracer produces the same traces as the JavaScript
+// one.
+func TestNativeCallTracer(t *testing.T) {
+	forEachTest(t, func(t *testing.T, test *callTracerTest) {
+		ret := new(callTrace)
+		if err := json.Unmarshal(runNative(t, test, "nativeCallTracer"), ret); err != nil {
+			t.Fatalf("failed to unmarshal trace result: %v", err)
+		}
+		trimErrors(ret, test.Result)
+
+		have, _ := json.Marshal(ret)
+		want, _ := json.Marshal(test.Result)
+		if !bytes.Equal(have, want) {
+			t.Fatalf("trace mismatch:\nhave %s\nwant %s", have, want)
+		}
+	})
+}
+
+// trimErrors drops the opcode details the expected traces carry after the
+// errors, which the EVM no longer reports.
+func trimErrors(have, want *callTrace) {
+	if have.Error != "" && strings.HasPrefix(want.Error, have.Error) {
+		want.Error = have.Error
+	}
+	for i := 0; i < len(have.Calls) && i < len(want.Calls); i++ {
+		trimErrors(&have.Calls[i], &want.Calls[i])
+	}
+}
+
+// Tests that the native prestate tracer reports the accounts recorded by the
+// JavaScript one as the prestate of the tests.
+func TestNativePrestateTracer(t *testing.T) {
+	forEachTest(t, func(t *testing.T, test *callTracerTest) {
+		ret := make(map[common.Address]*prestateAccount)
+		if err := json.Unmarshal(runNative(t, test, "nativePrestateTracer"), &ret); err != nil {
+			t.Fatalf("failed to unmarshal trace result: %v", err)
+		}
+		for addr, want := range test.Genesis.Alloc {
+			have, ok := ret[addr]
+			if !ok {
+				t.Errorf("account %x missing", addr)
+				continue
+			}
+			if have.Balance.ToInt().Cmp(want.Balance) != 0 || have.Nonce != want.Nonce || !bytes.Equal(have.Code, want.Code) {
+				t.Errorf("account %x mismatch: have %v %d %x, want %v %d %x", addr, have.Balance, have.Nonce, have.Code, want.Balance, want.Nonce, want.Code)
+			}
+			for key, value := range want.Storage {
+				if have.Storage[key] != value {
+					t.Errorf("account %x slot %x mismatch: have %x, want %x", addr, key, have.Storage[key], value)
+				}
+			}
+		}
+	})
+}
+
+// Tests that the prestate tracer records the candidate of the special
+// transactions, applied without the EVM.
+func TestNativePrestateChainCandidate(t *testing.T) {
+	var (
+		from      = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		candidate = common.HexToAddress("0x1000000000000000000000000000000000000002")
+	)
+	statedb := makePreState(core.GenesisAlloc{
+		from:      {Balance: big.NewInt(1000000)},
+		candidate: {Balance: big.NewInt(1000)},
+	})
+	input, err := intAbi.ChainABI.Pack(intAbi.Delegate.String(), candidate)
+	if err != nil {
+		t.Fatalf("failed to pack delegate input: %v", err)
+	}
+	tracer, err := tracers.NewNative("nativePrestateTracer", nil)
+	if err != nil {
+		t.Fatalf("failed to create tracer: %v", err)
+	}
+	evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1)}, statedb, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	tracer.CaptureTxStart(evm, from, &intAbi.ChainContractMagicAddr, input)
+
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	ret := make(map[common.Address]*prestateAccount)
+	if err := json.Unmarshal(res, &ret); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	if account, ok := ret[candidate]; !ok || account.Balance.ToInt().Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("candidate mismatch: have %+v", account)
+	}
+}
@@ -66,13 +66,13 @@ type Miner struct {
 	cch    core.CrossChainHelper
 }
 
-func New(eth Backend, config *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, gasFloor, gasCeil uint64, cch core.CrossChainHelper) *Miner {
+func New(eth Backend, config *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, gasFloor, gasCeil, specialGasReserve uint64, cch core.CrossChainHelper) *Miner {
 	miner := &Miner{
 		eth:      eth,
 		mux:      mux,
 		engine:   engine,
 		exitCh:   make(chan struct{}),
-		worker:   newWorker(config, engine, eth, mux, gasFloor, gasCeil, cch),
+		worker:   newWorker(config, engine, eth, mux, gasFloor, gasCeil, specialGasReserve, cch),
 		canStart: 1,
 		logger:   config.ChainLogger,
 		cch:      cch,
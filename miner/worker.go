@@ -26,6 +26,7 @@ import (
 	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/consensus"
 	tdmTypes "github.com/intfoundation/intchain/consensus/ipbft/types"
+	"github.com/intfoundation/intchain/consensus/misc"
 	"github.com/intfoundation/intchain/core"
 	"github.com/intfoundation/intchain/core/state"
 	"github.com/intfoundation/intchain/core/types"
@@ -96,6 +97,8 @@ type worker struct {
 	gasFloor uint64
 	gasCeil  uint64
 
+	specialGasReserve uint64 // Percentage of the block gas reserved for the special transactions
+
 	mu sync.Mutex
 
 	// update loop
@@ -133,26 +136,30 @@ type worker struct {
 	cch    core.CrossChainHelper
 }
 
-func newWorker(config *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, gasFloor, gasCeil uint64, cch core.CrossChainHelper) *worker {
+func newWorker(config *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, gasFloor, gasCeil, specialGasReserve uint64, cch core.CrossChainHelper) *worker {
+	if specialGasReserve > 100 {
+		specialGasReserve = 100
+	}
 	worker := &worker{
-		config:         config,
-		engine:         engine,
-		eth:            eth,
-		mux:            mux,
-		gasFloor:       gasFloor,
-		gasCeil:        gasCeil,
-		txCh:           make(chan core.TxPreEvent, txChanSize),
-		chainHeadCh:    make(chan core.ChainHeadEvent, chainHeadChanSize),
-		chainSideCh:    make(chan core.ChainSideEvent, chainSideChanSize),
-		resultCh:       make(chan *Result, resultQueueSize),
-		exitCh:         make(chan struct{}),
-		chain:          eth.BlockChain(),
-		proc:           eth.BlockChain().Validator(),
-		possibleUncles: make(map[common.Hash]*types.Block),
-		agents:         make(map[Agent]struct{}),
-		unconfirmed:    newUnconfirmedBlocks(eth.BlockChain(), miningLogAtDepth, config.ChainLogger),
-		logger:         config.ChainLogger,
-		cch:            cch,
+		config:            config,
+		engine:            engine,
+		eth:               eth,
+		mux:               mux,
+		gasFloor:          gasFloor,
+		gasCeil:           gasCeil,
+		specialGasReserve: specialGasReserve,
+		txCh:              make(chan core.TxPreEvent, txChanSize),
+		chainHeadCh:       make(chan core.ChainHeadEvent, chainHeadChanSize),
+		chainSideCh:       make(chan core.ChainSideEvent, chainSideChanSize),
+		resultCh:          make(chan *Result, resultQueueSize),
+		exitCh:            make(chan struct{}),
+		chain:             eth.BlockChain(),
+		proc:              eth.BlockChain().Validator(),
+		possibleUncles:    make(map[common.Hash]*types.Block),
+		agents:            make(map[Agent]struct{}),
+		unconfirmed:       newUnconfirmedBlocks(eth.BlockChain(), miningLogAtDepth, config.ChainLogger),
+		logger:            config.ChainLogger,
+		cch:               cch,
 	}
 	// Subscribe TxPreEvent for tx pool
 	worker.txSub = eth.TxPool().SubscribeTxPreEvent(worker.txCh)
@@ -310,9 +317,10 @@ func (self *worker) mainLoop() {
 				self.currentMu.Lock()
 				acc, _ := types.Sender(self.current.signer, ev.Tx)
 				txs := map[common.Address]types.Transactions{acc: {ev.Tx}}
-				txset := types.NewTransactionsByPriceAndNonce(self.current.signer, txs)
+				txset := types.NewTransactionsByPriceAndNonce(self.current.signer, txs, self.current.header.BaseFee)
 
-				self.commitTransactionsEx(txset, self.coinbase, big.NewInt(0), self.cch)
+				gp := new(core.GasPool).AddGas(self.current.header.GasLimit)
+				self.commitTransactionsEx(txset, gp, self.coinbase, big.NewInt(0), self.cch)
 				self.currentMu.Unlock()
 			}
 
@@ -451,7 +459,7 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 		return err
 	}
 	work := &Work{
-		signer:    types.NewEIP155Signer(self.config.ChainId),
+		signer:    types.MakeSigner(self.config, header.Number),
 		state:     state,
 		ancestors: set.New(),
 		family:    set.New(),
@@ -509,6 +517,10 @@ func (self *worker) commitNewWork() {
 		Extra:      self.extra,
 		Time:       big.NewInt(tstamp),
 	}
+	// Set the base fee of the block after the London fork
+	if self.config.IsLondon(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(self.config, parent.Header())
+	}
 	// Only set the coinbase if our consensus engine is running (avoid spurious block rewards)
 	if self.isRunning() {
 		if self.coinbase == (common.Address{}) {
@@ -541,9 +553,25 @@ func (self *worker) commitNewWork() {
 	}
 
 	totalUsedMoney := big.NewInt(0)
-	txs := types.NewTransactionsByPriceAndNonce(self.current.signer, pending)
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+
+	// Commit the special transactions first within the gas reserved for them,
+	// so they are not starved by the ordinary transactions during congestion
+	var rmTxs types.Transactions
+	if special := self.eth.TxPool().SpecialPending(); len(special) > 0 && self.specialGasReserve > 0 {
+		reserve := header.GasLimit / 100 * self.specialGasReserve
+		sgp := new(core.GasPool).AddGas(reserve)
+		txs := types.NewTransactionsByPriceAndNonce(self.current.signer, special, header.BaseFee)
+		rmTxs = self.commitTransactionsEx(txs, sgp, self.coinbase, totalUsedMoney, self.cch)
+		gp.SubGas(reserve - sgp.Gas())
+
+		// The main pass continues after the special transactions committed above
+		dropCommitted(pending, special, work.state)
+	}
+
+	txs := types.NewTransactionsByPriceAndNonce(self.current.signer, pending, header.BaseFee)
 	//work.commitTransactions(self.mux, txs, self.chain, self.coinbase)
-	rmTxs := self.commitTransactionsEx(txs, self.coinbase, totalUsedMoney, self.cch)
+	rmTxs = append(rmTxs, self.commitTransactionsEx(txs, gp, self.coinbase, totalUsedMoney, self.cch)...)
 
 	// Remove the Invalid Transactions during tx execution (eg: tx4)
 	if len(rmTxs) > 0 {
@@ -585,6 +613,24 @@ func (self *worker) commitNewWork() {
 	self.push(work)
 }
 
+// dropCommitted removes the transactions of the given accounts from the pending
+// ones, whose nonces are below the account nonces of the work state, as they are
+// already committed to the work.
+func dropCommitted(pending, accounts map[common.Address]types.Transactions, state *state.StateDB) {
+	for addr := range accounts {
+		nonce := state.GetNonce(addr)
+		txs := pending[addr]
+		for len(txs) > 0 && txs[0].Nonce() < nonce {
+			txs = txs[1:]
+		}
+		if len(txs) == 0 {
+			delete(pending, addr)
+		} else {
+			pending[addr] = txs
+		}
+	}
+}
+
 func (self *worker) commitUncle(work *Work, uncle *types.Header) error {
 	hash := uncle.Hash()
 	if work.uncles.Has(hash) {
@@ -600,9 +646,7 @@ func (self *worker) commitUncle(work *Work, uncle *types.Header) error {
 	return nil
 }
 
-func (self *worker) commitTransactionsEx(txs *types.TransactionsByPriceAndNonce, coinbase common.Address, totalUsedMoney *big.Int, cch core.CrossChainHelper) (rmTxs types.Transactions) {
-
-	gp := new(core.GasPool).AddGas(self.current.header.GasLimit)
+func (self *worker) commitTransactionsEx(txs *types.TransactionsByPriceAndNonce, gp *core.GasPool, coinbase common.Address, totalUsedMoney *big.Int, cch core.CrossChainHelper) (rmTxs types.Transactions) {
 
 	var coalescedLogs []*types.Log
 
@@ -620,7 +664,8 @@ func (self *worker) commitTransactionsEx(txs *types.TransactionsByPriceAndNonce,
 		// Error may be ignored here. The error has already been checked
 		// during transaction acceptance is the transaction pool.
 		//
-		// We use the eip155 signer regardless of the current hf.
+		// We use the signer of the block being sealed, it accepts the typed
+		// transactions after the Berlin fork.
 		from, _ := types.Sender(self.current.signer, tx)
 		// Check whether the tx is replay protected. If we're not in the EIP155 hf
 		// phase, start ignoring the sender until we do.
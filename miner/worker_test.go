@@ -0,0 +1,55 @@
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/intfoundation/intchain/common"
+	"github.com/intfoundation/intchain/core/rawdb"
+	"github.com/intfoundation/intchain/core/state"
+	"github.com/intfoundation/intchain/core/types"
+)
+
+// Tests that the transactions committed by the reserved lane of the special
+// transactions are left out of the main pass.
+func TestDropCommitted(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	var (
+		committed = common.BytesToAddress([]byte{0x01})
+		partial   = common.BytesToAddress([]byte{0x02})
+		untouched = common.BytesToAddress([]byte{0x03})
+	)
+	newTxs := func(n int) types.Transactions {
+		txs := make(types.Transactions, n)
+		for i := range txs {
+			txs[i] = types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		}
+		return txs
+	}
+	pending := map[common.Address]types.Transactions{
+		committed: newTxs(2),
+		partial:   newTxs(3),
+		untouched: newTxs(2),
+	}
+	special := map[common.Address]types.Transactions{
+		committed: pending[committed],
+		partial:   pending[partial][:2],
+	}
+	// The reserved lane committed all the transactions of the first account and
+	// the first special transaction of the second
+	statedb.SetNonce(committed, 2)
+	statedb.SetNonce(partial, 1)
+
+	dropCommitted(pending, special, statedb)
+
+	if _, ok := pending[committed]; ok {
+		t.Errorf("fully committed account left in the main pass")
+	}
+	if txs := pending[partial]; len(txs) != 2 || txs[0].Nonce() != 1 {
+		t.Errorf("partially committed account mismatch: have %v", txs)
+	}
+	if txs := pending[untouched]; len(txs) != 2 {
+		t.Errorf("untouched account mismatch: have %d transactions, want %d", len(txs), 2)
+	}
+}
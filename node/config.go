@@ -72,6 +72,11 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DBEngine is the key-value database engine (leveldb or pebble) of newly
+	// created databases, existing ones are opened with the engine they were
+	// created with.
+	DBEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -594,7 +594,34 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string) (
 	if n.config.DataDir == "" {
 		return rawdb.NewMemoryDatabase(), nil
 	}
-	return rawdb.NewLevelDBDatabase(n.config.ResolvePath(name), cache, handles, namespace)
+	return rawdb.OpenDatabase(n.config.DBEngine, n.config.ResolvePath(name), cache, handles, "", namespace)
+}
+
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's data directory,
+// also attaching a chain freezer to it that moves ancient chain data from the
+// database to immutable append-only files, if a freezer directory is given. If the
+// node is an ephemeral one, a memory database is returned.
+func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer, namespace string) (intdb.Database, error) {
+	if n.config.DataDir == "" {
+		return rawdb.NewMemoryDatabase(), nil
+	}
+	return openDatabaseWithFreezer(n.config, name, cache, handles, freezer, namespace)
+}
+
+// openDatabaseWithFreezer resolves the freezer directory of the chain database,
+// the freezer is disabled if no directory is given.
+func openDatabaseWithFreezer(config *Config, name string, cache, handles int, freezer, namespace string) (intdb.Database, error) {
+	root := config.ResolvePath(name)
+	switch {
+	case freezer == "":
+		return rawdb.NewLevelDBDatabase(root, cache, handles, namespace)
+	case !filepath.IsAbs(freezer):
+		freezer = filepath.Join(config.ResolvePath(freezer), config.ChainId)
+	default:
+		freezer = filepath.Join(freezer, config.ChainId)
+	}
+	return rawdb.OpenDatabase(config.DBEngine, root, cache, handles, freezer, namespace)
 }
 
 // ResolvePath returns the absolute path of a resource in the instance directory.
@@ -312,6 +312,25 @@ func (srv *Server) AddPeer(node *discover.Node) {
 	}
 }
 
+// ValidatorNodes returns the nodes announced by the given validators of the chain,
+// the validators with unknown nodes are skipped.
+func (srv *Server) ValidatorNodes(chainId string, addresses []common.Address) []*discover.Node {
+	var nodes []*discover.Node
+	select {
+	case srv.peerOp <- func(map[discover.NodeID]*Peer) {
+		for _, address := range addresses {
+			if info, ok := srv.Validators[P2PValidator{ChainId: chainId, Address: address}]; ok {
+				node := info.Node
+				nodes = append(nodes, &node)
+			}
+		}
+	}:
+		<-srv.peerOpDone
+	case <-srv.quit:
+	}
+	return nodes
+}
+
 // RemovePeer disconnects from the given node
 func (srv *Server) RemovePeer(node *discover.Node) {
 	select {
@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/intfoundation/intchain/common"
 	"github.com/intfoundation/intchain/crypto"
 	"github.com/intfoundation/intchain/crypto/sha3"
 	"github.com/intfoundation/intchain/log"
@@ -84,6 +85,41 @@ func startTestServer(t *testing.T, id discover.NodeID, pf func(*Peer)) *Server {
 	return server
 }
 
+// Tests that only the announced nodes of the requested validators of the chain are
+// returned, which receive the private transactions.
+func TestServerValidatorNodes(t *testing.T) {
+	var (
+		proposer  = common.BytesToAddress([]byte{0x01})
+		other     = common.BytesToAddress([]byte{0x02})
+		unknown   = common.BytesToAddress([]byte{0x03})
+		node      = discover.Node{ID: randomID(), IP: net.IP{127, 0, 0, 1}, TCP: 30303}
+		otherNode = discover.Node{ID: randomID(), IP: net.IP{127, 0, 0, 2}, TCP: 30303}
+		childNode = discover.Node{ID: randomID(), IP: net.IP{127, 0, 0, 3}, TCP: 30303}
+	)
+	srv := &Server{
+		Config: Config{
+			Name:       "test",
+			MaxPeers:   10,
+			ListenAddr: "127.0.0.1:0",
+			PrivateKey: newkey(),
+			Validators: map[P2PValidator]*P2PValidatorNodeInfo{
+				{ChainId: "intchain", Address: proposer}: {Node: node},
+				{ChainId: "intchain", Address: other}:    {Node: otherNode},
+				{ChainId: "child_0", Address: unknown}:   {Node: childNode},
+			},
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	nodes := srv.ValidatorNodes("intchain", []common.Address{proposer, unknown})
+	if len(nodes) != 1 || nodes[0].ID != node.ID {
+		t.Fatalf("validator nodes mismatch: have %v, want %v", nodes, node.ID)
+	}
+}
+
 func TestServerListen(t *testing.T) {
 	// start the test server
 	connected := make(chan *Peer)
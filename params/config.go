@@ -69,7 +69,7 @@ var (
 		},
 	}
 
-	TestChainConfig = &ChainConfig{"", big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil}
+	TestChainConfig = &ChainConfig{"", big.NewInt(1), big.NewInt(0), big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -101,10 +101,21 @@ type ChainConfig struct {
 	ConstantinopleBlock *big.Int `json:"constantinopleBlock,omitempty"` // Constantinople switch block (nil = no fork, 0 = already activated)
 	PetersburgBlock     *big.Int `json:"petersburgBlock,omitempty"`     // Petersburg switch block (nil = same as Constantinople)
 	IstanbulBlock       *big.Int `json:"istanbulBlock,omitempty"`       // Istanbul switch block (nil = no fork, 0 = already on istanbul)
+	BerlinBlock         *big.Int `json:"berlinBlock,omitempty"`         // Berlin switch block, typed and access list transactions (nil = no fork, 0 = already on berlin)
+	LondonBlock         *big.Int `json:"londonBlock,omitempty"`         // London switch block, dynamic base fee and fee market transactions (nil = no fork, 0 = already on london)
+	IntPrecompileBlock  *big.Int `json:"intPrecompileBlock,omitempty"`  // IntChain precompiled contracts switch block (nil = no fork, 0 = already activated)
+	ChildEpochBlock     *big.Int `json:"childEpochBlock,omitempty"`     // Child chain epoch 0 check switch block, only the first child block may carry it (nil = no fork, 0 = already activated)
+
+	// Receiver of the base fee paid by the transactions after the London fork (nil = burnt),
+	// fixed once the London fork is reached
+	BaseFeeTreasury *common.Address `json:"baseFeeTreasury,omitempty"`
 
 	// Various consensus engines
 	IPBFT *IPBFTConfig `json:"ipbft,omitempty"`
 
+	// ERC20 token bridge between the main chain and the child chains (nil = disabled)
+	TokenBridge *TokenBridgeConfig `json:"tokenBridge,omitempty"`
+
 	ChainLogger log.Logger `json:"-"`
 }
 
@@ -119,6 +130,16 @@ func (c *IPBFTConfig) String() string {
 	return "ipbft"
 }
 
+// TokenBridgeConfig is the config of the lock-and-mint token bridge, it only takes effect on the main chain.
+type TokenBridgeConfig struct {
+	MainChainBridge common.Address `json:"mainChainBridge"` // Bridge contract which escrows the locked tokens on the main chain
+}
+
+// String implements the stringer interface, returning the token bridge details.
+func (c *TokenBridgeConfig) String() string {
+	return fmt.Sprintf("{MainChainBridge: %x}", c.MainChainBridge)
+}
+
 // Create a new Chain Config based on the Chain ID, for child chain creation purpose
 func NewChildChainConfig(childChainID string) *ChainConfig {
 	config := &ChainConfig{
@@ -153,7 +174,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{IntChainId: %s ChainID: %v Homestead: %v  EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v Engine: %v}",
+	return fmt.Sprintf("{IntChainId: %s ChainID: %v Homestead: %v  EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v Berlin: %v London: %v IntPrecompile: %v ChildEpoch: %v Engine: %v}",
 		c.IntChainId,
 		c.ChainId,
 		c.HomesteadBlock,
@@ -164,6 +185,10 @@ func (c *ChainConfig) String() string {
 		c.ConstantinopleBlock,
 		c.PetersburgBlock,
 		c.IstanbulBlock,
+		c.BerlinBlock,
+		c.LondonBlock,
+		c.IntPrecompileBlock,
+		c.ChildEpochBlock,
 		engine,
 	)
 }
@@ -205,6 +230,26 @@ func (c *ChainConfig) IsIstanbul(num *big.Int) bool {
 	return isForked(c.IstanbulBlock, num)
 }
 
+// IsBerlin returns whether num is either equal to the Berlin fork block or greater.
+func (c *ChainConfig) IsBerlin(num *big.Int) bool {
+	return isForked(c.BerlinBlock, num)
+}
+
+// IsLondon returns whether num is either equal to the London fork block or greater.
+func (c *ChainConfig) IsLondon(num *big.Int) bool {
+	return isForked(c.LondonBlock, num)
+}
+
+// IsIntPrecompile returns whether num is either equal to the IntChain precompile fork block or greater.
+func (c *ChainConfig) IsIntPrecompile(num *big.Int) bool {
+	return isForked(c.IntPrecompileBlock, num)
+}
+
+// IsChildEpoch returns whether num is either equal to the child chain epoch 0 check fork block or greater.
+func (c *ChainConfig) IsChildEpoch(num *big.Int) bool {
+	return isForked(c.ChildEpochBlock, num)
+}
+
 func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return false
 }
@@ -282,6 +327,21 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.IstanbulBlock, newcfg.IstanbulBlock, head) {
 		return newCompatError("Istanbul fork block", c.IstanbulBlock, newcfg.IstanbulBlock)
 	}
+	if isForkIncompatible(c.BerlinBlock, newcfg.BerlinBlock, head) {
+		return newCompatError("Berlin fork block", c.BerlinBlock, newcfg.BerlinBlock)
+	}
+	if isForkIncompatible(c.LondonBlock, newcfg.LondonBlock, head) {
+		return newCompatError("London fork block", c.LondonBlock, newcfg.LondonBlock)
+	}
+	if isForkIncompatible(c.IntPrecompileBlock, newcfg.IntPrecompileBlock, head) {
+		return newCompatError("IntChain precompile fork block", c.IntPrecompileBlock, newcfg.IntPrecompileBlock)
+	}
+	if isForkIncompatible(c.ChildEpochBlock, newcfg.ChildEpochBlock, head) {
+		return newCompatError("Child chain epoch fork block", c.ChildEpochBlock, newcfg.ChildEpochBlock)
+	}
+	if c.IsLondon(head) && !configAddressEqual(c.BaseFeeTreasury, newcfg.BaseFeeTreasury) {
+		return newCompatError("London base fee treasury", c.LondonBlock, newcfg.LondonBlock)
+	}
 	return nil
 }
 
@@ -309,6 +369,13 @@ func configNumEqual(x, y *big.Int) bool {
 	return x.Cmp(y) == 0
 }
 
+func configAddressEqual(x, y *common.Address) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return *x == *y
+}
+
 // ConfigCompatError is raised if the locally-stored blockchain is initialised with a
 // ChainConfig that would alter the past.
 type ConfigCompatError struct {
@@ -349,6 +416,7 @@ type Rules struct {
 	ChainId                                                 *big.Int
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
+	IsBerlin, IsLondon, IsIntPrecompile                     bool
 }
 
 func (c *ChainConfig) Rules(num *big.Int) Rules {
@@ -366,5 +434,8 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 		IsConstantinople: c.IsConstantinople(num),
 		IsPetersburg:     c.IsPetersburg(num),
 		IsIstanbul:       c.IsIstanbul(num),
+		IsBerlin:         c.IsBerlin(num),
+		IsLondon:         c.IsLondon(num),
+		IsIntPrecompile:  c.IsIntPrecompile(num),
 	}
 }
@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/intfoundation/intchain/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -70,6 +72,40 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     9,
 			},
 		},
+		{
+			stored:  &ChainConfig{LondonBlock: big.NewInt(10), BaseFeeTreasury: &common.Address{1}},
+			new:     &ChainConfig{LondonBlock: big.NewInt(10), BaseFeeTreasury: &common.Address{1}},
+			head:    20,
+			wantErr: nil,
+		},
+		{
+			stored:  &ChainConfig{LondonBlock: big.NewInt(10)},
+			new:     &ChainConfig{LondonBlock: big.NewInt(10), BaseFeeTreasury: &common.Address{1}},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{LondonBlock: big.NewInt(10)},
+			new:    &ChainConfig{LondonBlock: big.NewInt(10), BaseFeeTreasury: &common.Address{1}},
+			head:   20,
+			wantErr: &ConfigCompatError{
+				What:         "London base fee treasury",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{ChildEpochBlock: big.NewInt(30)},
+			new:    &ChainConfig{},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "Child chain epoch fork block",
+				StoredConfig: big.NewInt(30),
+				NewConfig:    nil,
+				RewindTo:     29,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -24,3 +24,11 @@ const (
 	// contains.
 	BloomBitsBlocks uint64 = 4096
 )
+
+const (
+	// ImmutabilityThreshold is the number of blocks after which the chain segment is
+	// moved from the key-value database into the ancient store. IPBFT blocks never
+	// reorg once committed, the threshold only keeps the recent blocks in the faster
+	// key-value database.
+	ImmutabilityThreshold = 90000
+)